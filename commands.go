@@ -0,0 +1,925 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/melkeydev/govm/internal/cli"
+	"github.com/melkeydev/govm/internal/config"
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// command describes one govm subcommand. Adding a command means adding an
+// entry to commands; parsing, help and usage errors come from the entry.
+type command struct {
+	name    string
+	aliases []string
+	// args is the synopsis of the positional arguments, e.g. "<version>...".
+	args    string
+	summary string
+	example string
+	// minArgs is the number of positional arguments the command requires.
+	minArgs int
+	// flagsFirst stops flag parsing at the first positional argument, for
+	// commands whose trailing arguments belong to another program.
+	flagsFirst bool
+	// raw commands receive their arguments without any flag parsing.
+	raw    bool
+	hidden bool
+	// setup defines the command's flags on fs and returns the function that
+	// runs it with the remaining positional arguments.
+	setup func(fs *flag.FlagSet) func(args []string)
+}
+
+// noFlags is the setup of a command without flags.
+func noFlags(run func(args []string)) func(*flag.FlagSet) func([]string) {
+	return func(*flag.FlagSet) func([]string) { return run }
+}
+
+// jsonFlag defines --json on fs and returns a function that switches the
+// CLI to JSON output when the flag was given.
+func jsonFlag(fs *flag.FlagSet, usage string) func() {
+	enabled := fs.Bool("json", false, usage)
+	return func() {
+		if *enabled {
+			cli.EnableJSON()
+		}
+	}
+}
+
+// yesFlag defines --yes and -y on fs and returns a function that makes the
+// command skip its confirmation prompts when either was given.
+func yesFlag(fs *flag.FlagSet) func() {
+	yes := fs.Bool("yes", false, "skip the confirmation prompt (or set GOVM_ASSUME_YES=1)")
+	fs.BoolVar(yes, "y", false, "shorthand for --yes")
+	return func() {
+		if *yes {
+			cli.AssumeYes()
+		}
+	}
+}
+
+// optionalArg returns args[0], or an empty string when there is none.
+func optionalArg(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+	return ""
+}
+
+var commands []*command
+
+func init() {
+	commands = []*command{
+		{
+			name: "install", aliases: []string{"i"}, args: "<version>...",
+			summary: "Install one or more Go versions",
+			example: "govm install 1.21",
+			setup: func(fs *flag.FlagSet) func([]string) {
+				parallel := fs.Bool("parallel", false, "install several versions concurrently")
+				jobs := fs.Int("jobs", 0, fmt.Sprintf("install up to `n` versions at once (implies --parallel; default %d)", utils.DefaultInstallJobs))
+				pre := fs.Bool("pre", false, "allow betas and release candidates for partial versions")
+				dryRun := fs.Bool("dry-run", false, "show what would be downloaded and created without installing")
+				fromGoMod := fs.Bool("from-gomod", false, "install the version the nearest go.mod requires")
+				use := fs.Bool("use", false, "with --from-gomod, switch to the installed version")
+				pin := fs.Bool("pin", false, "with --from-gomod, pin the installed version in .go-version")
+				applyJSON := jsonFlag(fs, "print the results as JSON")
+				return func(args []string) {
+					switch {
+					case *fromGoMod && len(args) > 0:
+						usageError("Error: --from-gomod takes no version argument",
+							"Usage: govm install --from-gomod [--use] [--pin]")
+					case *fromGoMod:
+						cli.InstallFromGoMod(*use, *pin, *pre, *dryRun)
+						return
+					case *use || *pin:
+						usageError("Error: --use and --pin require --from-gomod",
+							"Usage: govm install --from-gomod [--use] [--pin]")
+					case len(args) == 0:
+						usageError("Error: 'install' requires a version argument or --from-gomod",
+							"Usage: govm install <version>... | govm install --from-gomod",
+							"Example: govm install 1.21")
+					}
+					applyJSON()
+					cli.InstallVersions(args, installJobs(*parallel, *jobs), *pre, *dryRun)
+				}
+			},
+		},
+		{
+			name: "update", args: "tip", minArgs: 1,
+			summary: "Rebuild tip from the latest master commit",
+			example: "govm update tip",
+			setup:   noFlags(func(args []string) { cli.Update(args[0]) }),
+		},
+		{
+			name: "use", aliases: []string{"switch"}, args: "<version>", minArgs: 1,
+			summary: "Switch to a specific Go version",
+			example: "govm use 1.21",
+			setup: func(fs *flag.FlagSet) func([]string) {
+				global := fs.Bool("global", false, "switch the machine-wide version (the default)")
+				local := fs.Bool("local", false, "pin the current directory with a .go-version file instead")
+				dryRun := fs.Bool("dry-run", false, "show what files would be written without switching")
+				applyJSON := jsonFlag(fs, "print the result as JSON")
+				return func(args []string) {
+					if *local {
+						if *global {
+							usageError("Error: --global and --local cannot be used together")
+						}
+						if args[0] == "-" {
+							usageError("Error: 'use -' switches back globally and cannot be combined with --local")
+						}
+						cli.UseLocal(args[0], *dryRun)
+						return
+					}
+					applyJSON()
+					cli.UseVersion(args[0], *dryRun)
+				}
+			},
+		},
+		{
+			name: "delete", aliases: []string{"rm"}, args: "<version>...",
+			summary: "Delete one or more Go versions",
+			example: "govm delete 1.21",
+			setup: func(fs *flag.FlagSet) func([]string) {
+				allExceptActive := fs.Bool("all-except-active", false, "delete every version except the active one")
+				olderThan := fs.String("older-than", "", "delete every version older than the given `version`")
+				dryRun := fs.Bool("dry-run", false, "show what would be removed without deleting anything")
+				applyYes := yesFlag(fs)
+				return func(args []string) {
+					applyYes()
+					if len(args) == 0 && !*allExceptActive && *olderThan == "" {
+						usageError("Error: 'delete' requires a version argument",
+							"Usage: govm delete <version>... | --all-except-active | --older-than <version>",
+							"Example: govm delete 1.21")
+					}
+					cli.DeleteVersions(args, *allExceptActive, *olderThan, *dryRun)
+				}
+			},
+		},
+		{
+			name: "list", aliases: []string{"ls"},
+			summary: "List installed Go versions",
+			setup: func(fs *flag.FlagSet) func([]string) {
+				applyJSON := jsonFlag(fs, "print the installed versions as JSON")
+				cfg, _ := config.Load()
+				iso := fs.Bool("iso", cfg.ISOTime(), "print timestamps as ISO-8601 in UTC")
+				return func([]string) {
+					applyJSON()
+					cli.ListVersions(*iso)
+				}
+			},
+		},
+		{
+			name: "ls-remote", aliases: []string{"list-remote"},
+			summary: "List Go versions available on go.dev",
+			setup: func(fs *flag.FlagSet) func([]string) {
+				pre := fs.Bool("pre", false, "also show betas and release candidates")
+				// Stable releases are the default; the flag is kept for old scripts
+				fs.Bool("stable-only", false, "only show stable releases (the default)")
+				major := fs.String("major", "", "only show releases of the minor line `version`, e.g. 1.22")
+				limit := fs.Int("limit", 0, "show at most `n` versions (0 for no limit)")
+				applyJSON := jsonFlag(fs, "print the versions as JSON")
+				return func([]string) {
+					applyJSON()
+					cli.ListRemoteVersions(*pre, *major, *limit)
+				}
+			},
+		},
+		{
+			name: "search", args: "<pattern>", minArgs: 1,
+			summary: "Find remote and installed versions matching a pattern",
+			example: "govm search '1.2*'",
+			setup: func(fs *flag.FlagSet) func([]string) {
+				pre := fs.Bool("pre", false, "also match betas and release candidates")
+				applyJSON := jsonFlag(fs, "print the matches as JSON")
+				return func(args []string) {
+					applyJSON()
+					cli.Search(args[0], *pre)
+				}
+			},
+		},
+		{
+			name:    "prune",
+			summary: "Remove all but the latest patch of each minor version",
+			setup: func(fs *flag.FlagSet) func([]string) {
+				dryRun := fs.Bool("dry-run", false, "show what would be removed without deleting anything")
+				applyYes := yesFlag(fs)
+				return func([]string) {
+					applyYes()
+					cli.PruneVersions(*dryRun)
+				}
+			},
+		},
+		{
+			name:    "clean",
+			summary: "Clear the downloads and project caches",
+			setup: func(fs *flag.FlagSet) func([]string) {
+				partial := fs.Bool("partial", false, "also remove incomplete installs left by failed downloads")
+				return func([]string) { cli.CleanDownloads(*partial) }
+			},
+		},
+		{
+			name:    "bootstrap",
+			summary: "Install the latest stable Go and configure your shell",
+			setup: func(fs *flag.FlagSet) func([]string) {
+				dryRun := fs.Bool("dry-run", false, "show what would be installed and changed without touching anything")
+				return func([]string) { cli.Bootstrap(*dryRun) }
+			},
+		},
+		{
+			name: "which", args: "<tool>", minArgs: 1,
+			summary: "Show the binary a shim runs and its Go version",
+			example: "govm which gofmt",
+			setup:   noFlags(func(args []string) { cli.Which(args[0]) }),
+		},
+		{
+			name:    "takeover",
+			summary: "Migrate a manual /usr/local/go install to GoVM",
+			setup:   noFlags(func([]string) { cli.Takeover() }),
+		},
+		{
+			name: "exec", args: "<version> -- <command> [args...]", raw: true,
+			summary: "Run a command with a version without switching to it",
+			example: "govm exec 1.20 -- go test ./...",
+			setup: noFlags(func(args []string) {
+				if len(args) > 1 && args[1] == "--" {
+					args = append(args[:1], args[2:]...)
+				}
+				if len(args) < 2 {
+					usageError("Error: 'exec' requires a version and a command",
+						"Usage: govm exec <version> -- <command> [args...]",
+						"Example: govm exec 1.20 -- go test ./...")
+				}
+				os.Exit(cli.Exec(args[0], args[1:]))
+			}),
+		},
+		{
+			name: "run", args: "-- <command> [args...]", minArgs: 1, flagsFirst: true,
+			summary: "Run a command with the version the project pins",
+			example: "govm run -- go build ./...",
+			setup: func(fs *flag.FlagSet) func([]string) {
+				install := fs.Bool("install", false, "install the pinned version if it is missing")
+				return func(args []string) { os.Exit(cli.Run(args, *install)) }
+			},
+		},
+		{
+			name: "migrate", args: "<goenv|gvm|g|asdf>", minArgs: 1,
+			summary: "Adopt toolchains from goenv, gvm, g or asdf",
+			example: "govm migrate goenv",
+			setup: func(fs *flag.FlagSet) func([]string) {
+				link := fs.Bool("link", false, "symlink toolchains instead of copying them")
+				return func(args []string) { cli.Migrate(args[0], *link) }
+			},
+		},
+		{
+			name: "shim", args: "list | trace <tool>", minArgs: 1,
+			summary: "List shims, or show how a shim resolves its target",
+			example: "govm shim trace go",
+			setup: noFlags(func(args []string) {
+				switch {
+				case args[0] == "list":
+					cli.ListShims()
+				case args[0] == "trace" && len(args) > 1:
+					cli.TraceShim(args[1])
+				case args[0] == "trace":
+					usageError("Error: 'shim trace' requires a tool argument",
+						"Usage: govm shim trace <tool>",
+						"Example: govm shim trace go")
+				default:
+					usageError("Unknown shim subcommand: "+args[0], "Usage: govm shim list | govm shim trace <tool>")
+				}
+			}),
+		},
+		{
+			name: "mise", args: "list-all | path <version>", minArgs: 1,
+			summary: "Plumbing for mise: list releases or print a version's GOROOT",
+			example: `mise use go@path:$(govm mise path --install 1.22)`,
+			setup: func(fs *flag.FlagSet) func([]string) {
+				install := fs.Bool("install", false, "with path, install the version if it is missing")
+				return func(args []string) {
+					switch {
+					case args[0] == "list-all":
+						cli.MiseListAll()
+					case args[0] == "path" && len(args) > 1:
+						cli.MisePath(args[1], *install)
+					case args[0] == "path":
+						usageError("Error: 'mise path' requires a version argument",
+							"Usage: govm mise path [--install] <version>",
+							"Example: govm mise path 1.22")
+					default:
+						usageError("Unknown mise subcommand: "+args[0], "Usage: govm mise list-all | govm mise path [--install] <version>")
+					}
+				}
+			},
+		},
+		{
+			name:    "env",
+			summary: "Print GOROOT and GoVM directories",
+			setup: func(fs *flag.FlagSet) func([]string) {
+				format := fs.String("format", "shell", "output `format`: shell, fish, powershell, json or dotenv")
+				return func([]string) { cli.PrintEnv(*format) }
+			},
+		},
+		{
+			name: "ci", args: "[version]",
+			summary: "Install the project's Go version and export it for CI",
+			setup:   noFlags(func(args []string) { cli.CI(optionalArg(args)) }),
+		},
+		{
+			name: "cache", args: "export|restore <dir>", minArgs: 2,
+			summary: "Save or restore the active version and downloads for a CI cache",
+			example: "govm cache export .cache/govm",
+			setup: noFlags(func(args []string) {
+				switch args[0] {
+				case "export":
+					cli.CacheExport(args[1])
+				case "restore":
+					cli.CacheRestore(args[1])
+				default:
+					usageError("Unknown cache subcommand: "+args[0], "Usage: govm cache export|restore <dir>")
+				}
+			}),
+		},
+		{
+			name: "completion", args: "<shell>", minArgs: 1,
+			summary: "Print a completion script (bash, zsh, fish, powershell)",
+			example: "source <(govm completion bash)",
+			setup:   noFlags(func(args []string) { cli.Completion(args[0], completionCommands()) }),
+		},
+		{
+			name:    "status",
+			summary: "Summarize the GoVM setup for debugging",
+			setup: func(fs *flag.FlagSet) func([]string) {
+				remote := fs.String("remote", "", "show the status of another machine over SSH, given its `host`")
+				applyJSON := jsonFlag(fs, "print the status as JSON")
+				cfg, _ := config.Load()
+				iso := fs.Bool("iso", cfg.ISOTime(), "print timestamps as ISO-8601 in UTC")
+				return func([]string) {
+					applyJSON()
+					if *remote != "" {
+						cli.RemoteStatus(*remote)
+					} else {
+						cli.Status(*iso)
+					}
+				}
+			},
+		},
+		{
+			name: "tool", args: "list | install [<tool> <version>] | use <tool> <version>", minArgs: 1,
+			summary: "Manage companion tools (golangci-lint, buf, plugins)",
+			example: "govm tool install golangci-lint 1.59.1",
+			setup: noFlags(func(args []string) {
+				switch {
+				case args[0] == "list":
+					cli.ListTools()
+				case args[0] == "install" && len(args) == 1:
+					cli.InstallTools()
+				case args[0] == "install" && len(args) == 3:
+					cli.InstallTool(args[1], args[2])
+				case args[0] == "use" && len(args) == 3:
+					cli.UseTool(args[1], args[2])
+				default:
+					usageError("Usage: govm tool list | govm tool install [<tool> <version>] | govm tool use <tool> <version>")
+				}
+			}),
+		},
+		{
+			name:    "self-uninstall",
+			summary: "Remove all GoVM data and its PATH setup",
+			setup: func(fs *flag.FlagSet) func([]string) {
+				applyYes := yesFlag(fs)
+				return func([]string) {
+					applyYes()
+					cli.SelfUninstall()
+				}
+			},
+		},
+		{
+			name: "diff", args: "<from> <to>", minArgs: 2,
+			summary: "Summarize upstream changes between two versions",
+			example: "govm diff 1.21.8 1.22.4",
+			setup:   noFlags(func(args []string) { cli.Diff(args[0], args[1]) }),
+		},
+		{
+			name: "upgrade", args: "[minor]",
+			summary: "Install the newest patch of each installed minor version",
+			setup: func(fs *flag.FlagSet) func([]string) {
+				prune := fs.Bool("prune", false, "delete the superseded patch releases after upgrading")
+				preflight := fs.Bool("preflight", false, "build and vet the current project with the new version before switching")
+				return func(args []string) { cli.Upgrade(optionalArg(args), *prune, *preflight) }
+			},
+		},
+		{
+			name: "alias", args: "[list] | set <name> <version> | rm <name>",
+			summary: "Manage version aliases usable wherever a version is expected",
+			example: "govm alias set work 1.22",
+			setup: noFlags(func(args []string) {
+				switch {
+				case len(args) == 0 || (args[0] == "list" && len(args) == 1):
+					cli.ListAliases()
+				case args[0] == "set" && len(args) == 3:
+					cli.SetAlias(args[1], args[2])
+				case args[0] == "rm" && len(args) == 2:
+					cli.RemoveAlias(args[1])
+				case len(args) == 2 && args[0] != "set" && args[0] != "rm" && args[0] != "list":
+					cli.SetAlias(args[0], args[1])
+				default:
+					usageError("Usage: govm alias list | govm alias set <name> <version> | govm alias rm <name>")
+				}
+			}),
+		},
+		{
+			name: "pin", args: "<version>",
+			summary: "Pin the current directory to a version (.go-version)",
+			example: "govm pin 1.22.4",
+			setup: func(fs *flag.FlagSet) func([]string) {
+				fromActive := fs.Bool("from-active", false, "pin the currently active version")
+				return func(args []string) {
+					if len(args) == 0 && !*fromActive {
+						usageError("Error: 'pin' requires a version argument or --from-active",
+							"Usage: govm pin <version> | govm pin --from-active",
+							"Example: govm pin 1.22.4")
+					}
+					cli.Pin(optionalArg(args), *fromActive)
+				}
+			},
+		},
+		{
+			name:    "unpin",
+			summary: "Remove the .go-version file from the current directory",
+			setup:   noFlags(func([]string) { cli.Unpin() }),
+		},
+		{
+			name:    "rollback",
+			summary: "Switch back to the previously active version (or: govm use -)",
+			setup:   noFlags(func([]string) { cli.Rollback() }),
+		},
+		{
+			name: "download", args: "<version>", minArgs: 1,
+			summary: "Download a release archive without activating it",
+			example: "govm download 1.22",
+			setup: func(fs *flag.FlagSet) func([]string) {
+				extract := fs.Bool("extract", false, "also unpack the archive into the versions directory")
+				return func(args []string) { cli.Download(args[0], *extract) }
+			},
+		},
+		{
+			name:    "history",
+			summary: "Show recent version switches",
+			setup: func(fs *flag.FlagSet) func([]string) {
+				fs.Bool("switches", true, "show version switches (the default and only view)")
+				cfg, _ := config.Load()
+				iso := fs.Bool("iso", cfg.ISOTime(), "print timestamps as ISO-8601 in UTC")
+				return func([]string) { cli.History(*iso) }
+			},
+		},
+		{
+			name: "verify", args: "<version>", minArgs: 1,
+			summary: "Check an installed version for damage or tampering",
+			example: "govm verify 1.22",
+			setup: noFlags(func(args []string) {
+				if !cli.Verify(args[0]) {
+					exitFailure()
+				}
+			}),
+		},
+		{
+			name:    "repair",
+			summary: "Fix broken shims, permissions and incomplete installs",
+			setup:   noFlags(func([]string) { cli.Repair() }),
+		},
+		{
+			name:    "doctor",
+			summary: "Diagnose PATH, active version and shim problems",
+			setup: func(fs *flag.FlagSet) func([]string) {
+				applyJSON := jsonFlag(fs, "print the problems found as JSON")
+				return func([]string) {
+					applyJSON()
+					cli.Doctor()
+				}
+			},
+		},
+		{
+			name:    "path",
+			summary: "Check that the GoVM shim comes first in PATH",
+			setup: func(fs *flag.FlagSet) func([]string) {
+				fix := fs.Bool("fix", false, "move the GoVM shim to the front of PATH in your shell config")
+				return func([]string) { cli.Path(*fix) }
+			},
+		},
+		{
+			name: "sync", args: "[file]",
+			summary: "Install the versions in govm.versions and activate its default",
+			setup: func(fs *flag.FlagSet) func([]string) {
+				parallel := fs.Bool("parallel", false, "install missing versions concurrently")
+				jobs := fs.Int("jobs", 0, fmt.Sprintf("install up to `n` versions at once (implies --parallel; default %d)", utils.DefaultInstallJobs))
+				return func(args []string) {
+					if !cli.Sync(optionalArg(args), installJobs(*parallel, *jobs)) {
+						exitFailure()
+					}
+				}
+			},
+		},
+		{
+			name: "export", args: "<version>", minArgs: 1,
+			summary: "Package an installed version for another machine",
+			example: "govm export 1.22 -o go1.22.tar.zst",
+			setup: func(fs *flag.FlagSet) func([]string) {
+				output := fs.String("o", "", "archive `file` to write (.tar.gz or .tar.zst)")
+				return func(args []string) { cli.Export(args[0], *output) }
+			},
+		},
+		{
+			name: "import", args: "<file>", minArgs: 1,
+			summary: "Install a version packaged by 'govm export'",
+			example: "govm import go1.22.tar.zst",
+			setup:   noFlags(func(args []string) { cli.Import(args[0]) }),
+		},
+		{
+			name:    "serve",
+			summary: "Serve read-only status JSON and install progress for editor plugins",
+			setup: func(fs *flag.FlagSet) func([]string) {
+				addr := fs.String("addr", "127.0.0.1:7385", "`address` to listen on")
+				return func([]string) { cli.Serve(*addr) }
+			},
+		},
+		{
+			name: "init", args: "<bash|zsh|fish|powershell>", minArgs: 1,
+			summary: "Print shell setup to eval from your rc file",
+			example: `eval "$(govm init zsh)"`,
+			setup: func(fs *flag.FlagSet) func([]string) {
+				autoSwitch := fs.Bool("auto-switch", false, "on cd, warn when the project's pinned version is not installed")
+				return func(args []string) { cli.Init(args[0], *autoSwitch, completionCommands()) }
+			},
+		},
+		{
+			name: "hook", args: "<bash|zsh|fish|powershell>", minArgs: 1,
+			summary: "Print only the cd hook that checks a project's pinned version",
+			example: `eval "$(govm hook zsh)"`,
+			setup:   noFlags(func(args []string) { cli.Hook(args[0]) }),
+		},
+		{
+			name: "shellenv", args: "[shell]",
+			summary: "Print just the PATH export for the current shell",
+			setup:   noFlags(func(args []string) { cli.ShellEnv(optionalArg(args)) }),
+		},
+		{
+			name: "shell", args: "<version>",
+			summary: "Print the export that selects a version for this shell session only",
+			example: `eval "$(govm shell 1.22)"`,
+			setup: func(fs *flag.FlagSet) func([]string) {
+				unset := fs.Bool("unset", false, "print the statement that clears the session version")
+				return func(args []string) {
+					if len(args) == 0 && !*unset {
+						usageError("Error: 'shell' requires a version argument or --unset",
+							"Usage: govm shell <version> | govm shell --unset",
+							`Example: eval "$(govm shell 1.22)"`)
+					}
+					cli.Shell(optionalArg(args), *unset)
+				}
+			},
+		},
+		{
+			name:    "current",
+			summary: "Print the Go version that runs in the current directory",
+			example: "govm current --explain",
+			setup: func(fs *flag.FlagSet) func([]string) {
+				explain := fs.Bool("explain", false, "show every layer of the resolution order and which one won")
+				applyJSON := jsonFlag(fs, "print the version and what selected it as JSON")
+				return func([]string) {
+					applyJSON()
+					cli.Current(*explain)
+				}
+			},
+		},
+		{
+			name:    "prompt",
+			summary: "Print the version that runs here, for shell prompts",
+			setup:   noFlags(func([]string) { cli.Prompt() }),
+		},
+		{
+			name: "goroot", args: "[version]",
+			summary: "Print the GOROOT of the version that runs here or of a named one",
+			setup: noFlags(func(args []string) {
+				if !cli.GoRoot(optionalArg(args)) {
+					exitFailure()
+				}
+			}),
+		},
+		{
+			name: "auto-switch", hidden: true,
+			summary: "Check the project's pinned version is installed (run by the init hook)",
+			setup:   noFlags(func([]string) { cli.AutoSwitch() }),
+		},
+		{
+			name: "background-install", args: "<version> <source>", minArgs: 2, hidden: true,
+			summary: "Install a pinned version for the cd hook (started by auto-switch)",
+			setup:   noFlags(func(args []string) { cli.BackgroundInstall(args[0], args[1]) }),
+		},
+		{
+			name: "gen-docs", args: "man|markdown", minArgs: 1,
+			summary: "Generate man pages or a markdown reference from the commands",
+			example: "govm gen-docs -o man/ man",
+			setup: func(fs *flag.FlagSet) func([]string) {
+				outDir := fs.String("o", "", "write one page per command into `dir` instead of printing a single page")
+				return func(args []string) {
+					if err := genDocs(args[0], *outDir); err != nil {
+						term.Fprintf(os.Stderr, "❌ %s\n", err)
+						os.Exit(cli.ExitFailure)
+					}
+				}
+			},
+		},
+		{
+			name:    "version",
+			summary: "Print the govm version",
+			setup: noFlags(func([]string) {
+				term.Printf("govm %s\n", utils.GetVersion())
+			}),
+		},
+		{
+			name: "help", args: "[command]",
+			summary: "Show help for govm or one of its commands",
+			setup: noFlags(func(args []string) {
+				if len(args) == 0 {
+					printUsage()
+					return
+				}
+				c := findCommand(args[0])
+				if c == nil {
+					usageError("Unknown command: "+args[0], "Run 'govm help' for usage.")
+				}
+				printCommandHelp(c)
+			}),
+		},
+	}
+}
+
+// findCommand returns the command called name or one of its aliases.
+func findCommand(name string) *command {
+	for _, c := range commands {
+		if c.name == name {
+			return c
+		}
+		for _, alias := range c.aliases {
+			if alias == name {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// runCommand parses args, whose first element is the command name, and
+// runs the command. Unknown commands, unknown flags and missing arguments
+// are usage errors.
+func runCommand(args []string) {
+	c := findCommand(args[0])
+	if c == nil {
+		usageError("Unknown command: "+args[0], "Run 'govm help' for usage.")
+	}
+	fs := newFlagSet(c)
+	run := c.setup(fs)
+	positional := args[1:]
+	if !c.raw {
+		var err error
+		if c.flagsFirst {
+			err = fs.Parse(positional)
+			positional = fs.Args()
+		} else {
+			positional, err = parseInterspersed(fs, positional)
+		}
+		if errors.Is(err, flag.ErrHelp) {
+			printCommandHelp(c)
+			return
+		}
+		if err != nil {
+			usageError("Error: "+err.Error(), "Usage: "+synopsis(c), fmt.Sprintf("Run 'govm help %s' for details.", c.name))
+		}
+	}
+	if len(positional) < c.minArgs {
+		lines := []string{fmt.Sprintf("Error: '%s' requires %s", c.name, c.args), "Usage: " + synopsis(c)}
+		if c.example != "" {
+			lines = append(lines, "Example: "+c.example)
+		}
+		usageError(lines...)
+	}
+	run(positional)
+}
+
+// newFlagSet returns the flag set for c. Parse errors are reported by
+// runCommand, so the flag package itself prints nothing.
+func newFlagSet(c *command) *flag.FlagSet {
+	fs := flag.NewFlagSet("govm "+c.name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// parseInterspersed parses flags that may appear before or after positional
+// arguments and returns the positional arguments. Everything after "--" is
+// positional.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var rest []string
+	for i, arg := range args {
+		if arg == "--" {
+			args, rest = args[:i], args[i+1:]
+			break
+		}
+	}
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return append(positional, rest...), nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// synopsis returns the one-line usage of c.
+func synopsis(c *command) string {
+	s := "govm " + c.name
+	hasFlags := false
+	newFlagSetFor(c).VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		s += " [flags]"
+	}
+	if c.args != "" {
+		s += " " + c.args
+	}
+	return s
+}
+
+// completionCommands lists the visible commands and their flags for the
+// completion scripts, so they always offer what the parser accepts.
+func completionCommands() []cli.CompletionCommand {
+	var completions []cli.CompletionCommand
+	for _, c := range visibleCommands() {
+		completion := cli.CompletionCommand{Name: c.name}
+		newFlagSetFor(c).VisitAll(func(f *flag.Flag) {
+			prefix := "--"
+			if len(f.Name) == 1 {
+				prefix = "-"
+			}
+			completion.Flags = append(completion.Flags, prefix+f.Name)
+		})
+		completions = append(completions, completion)
+	}
+	return completions
+}
+
+// newFlagSetFor returns a flag set with c's flags defined, for help output.
+func newFlagSetFor(c *command) *flag.FlagSet {
+	fs := newFlagSet(c)
+	c.setup(fs)
+	return fs
+}
+
+// flagLines describes the flags of fs, one "name  usage" pair per flag.
+func flagLines(fs *flag.FlagSet) [][2]string {
+	var lines [][2]string
+	fs.VisitAll(func(f *flag.Flag) {
+		name, usage := flag.UnquoteUsage(f)
+		prefix := "--"
+		if len(f.Name) == 1 {
+			prefix = "-"
+		}
+		left := prefix + f.Name
+		if name != "" {
+			left += " <" + name + ">"
+		}
+		lines = append(lines, [2]string{left, capitalize(usage)})
+	})
+	return lines
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// printHelpLine prints left and right in two columns, moving right to its
+// own line when left is too wide.
+func printHelpLine(indent, left, right string) {
+	const column = 25
+	left = indent + left
+	if len(left) >= column {
+		term.Println(left)
+		term.Printf("%s%s\n", strings.Repeat(" ", column), right)
+		return
+	}
+	term.Printf("%-*s%s\n", column, left, right)
+}
+
+// printCommandHelp prints the usage, flags and example of c.
+func printCommandHelp(c *command) {
+	term.Printf("Usage: %s\n\n%s\n", synopsis(c), c.summary)
+	if len(c.aliases) > 0 {
+		term.Printf("\nAliases: %s\n", strings.Join(c.aliases, ", "))
+	}
+	if lines := flagLines(newFlagSetFor(c)); len(lines) > 0 {
+		term.Println("\nFlags:")
+		for _, line := range lines {
+			printHelpLine("  ", line[0], line[1])
+		}
+	}
+	if c.example != "" {
+		term.Printf("\nExample:\n  %s\n", c.example)
+	}
+}
+
+func printUsage() {
+	term.Println("GoVM - Go Version Manager")
+	term.Println("\nUsage:")
+	printHelpLine("  ", "govm", "Launch the interactive TUI")
+	for _, c := range commands {
+		if c.hidden {
+			continue
+		}
+		left := "govm " + c.name
+		if c.args != "" {
+			left += " " + c.args
+		}
+		printHelpLine("  ", left, c.summary)
+		for _, line := range flagLines(newFlagSetFor(c)) {
+			printHelpLine("      ", line[0], line[1])
+		}
+	}
+	term.Println("\nGlobal flags:")
+	for _, line := range globalFlags {
+		printHelpLine("  ", line[0], line[1])
+	}
+	term.Println("\nRun 'govm help <command>' or 'govm <command> --help' for details on a command.")
+	term.Println("\nExamples:")
+	for _, line := range usageExamples {
+		printHelpLine("  ", line[0], line[1])
+	}
+}
+
+// globalFlags describes the flags parseOutputFlags accepts before any command.
+var globalFlags = [][2]string{
+	{"--no-emoji", "Print plain text without emoji (or set GOVM_NO_EMOJI=1)"},
+	{"--no-color", "Disable colors (or set NO_COLOR=1)"},
+	{"-q, --quiet", "Only print results, warnings and errors"},
+	{"--verbose", "Show URLs, paths and commands used underneath"},
+	{"--limit-rate <rate>", "Cap download speed, e.g. 5M for 5 MiB/s (or set download.limit_rate)"},
+	{"--offline", "Work from the cached release list and downloads without the network"},
+	{"--ipv4", "Connect over IPv4 only (or set download.ip_version)"},
+	{"--ipv6", "Connect over IPv6 only"},
+}
+
+// usageExamples are the examples at the end of the usage text.
+var usageExamples = [][2]string{
+	{"govm install 1.21", "Install Go 1.21.x (latest)"},
+	{"govm use 1.20", "Switch to Go 1.20.x (latest)"},
+	{"govm install latest", "Install the newest stable release (also: oldstable, stable)"},
+	{"govm install go1.22.4.linux-amd64.tar.gz", "Install an exact release archive (a full URL also works)"},
+	{"govm install tip", "Build the unstable master branch of Go from source"},
+}
+
+// installJobs turns the --parallel and --jobs flags into how many versions
+// install at once.
+func installJobs(parallel bool, jobs int) int {
+	switch {
+	case jobs < 0:
+		usageError("Error: --jobs must be at least 1")
+	case jobs > 0:
+		return jobs
+	case parallel:
+		return utils.DefaultInstallJobs
+	}
+	return 1
+}
+
+// usageError prints a malformed command line's explanation to stderr and
+// exits with the usage exit code.
+func usageError(lines ...string) {
+	for _, line := range lines {
+		term.Fprintln(os.Stderr, line)
+	}
+	os.Exit(cli.ExitUsage)
+}
+
+// exitFailure exits with the code of the failure the command reported, for
+// commands that signal failure by their return value.
+func exitFailure() {
+	code := cli.ExitCode()
+	if code == cli.ExitOK {
+		code = cli.ExitFailure
+	}
+	os.Exit(code)
+}
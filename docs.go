@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/cli"
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// exitStatuses describes govm's exit codes for the generated reference.
+var exitStatuses = []struct {
+	code int
+	desc string
+}{
+	{cli.ExitOK, "Success"},
+	{cli.ExitFailure, "Any other failure"},
+	{cli.ExitUsage, "Invalid command line, e.g. a missing argument or unknown shell"},
+	{cli.ExitNotFound, "No release on go.dev matches the requested version"},
+	{cli.ExitNotInstalled, "The requested version is not installed"},
+	{cli.ExitNetwork, "go.dev or its mirrors could not be reached"},
+	{cli.ExitPolicy, "The version is blocked by the organization policy"},
+}
+
+// genDocs renders the reference for every visible command in format ("man"
+// or "markdown"). With an empty outDir it prints a single page to stdout;
+// otherwise it writes an overview page and one page per command into outDir.
+// The pages carry no date so that package builds are reproducible.
+func genDocs(format, outDir string) error {
+	var page func(w io.Writer)
+	var commandPage func(w io.Writer, c *command)
+	ext := ""
+	switch format {
+	case "man":
+		page, commandPage, ext = manPage, manCommandPage, ".1"
+	case "markdown", "md":
+		page, commandPage, ext = markdownPage, markdownCommandPage, ".md"
+	default:
+		usageError(fmt.Sprintf("Error: unknown docs format %q", format),
+			"Usage: govm gen-docs [-o <dir>] man|markdown")
+	}
+	if outDir == "" {
+		w := bufio.NewWriter(os.Stdout)
+		page(w)
+		return w.Flush()
+	}
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %v", outDir, err)
+	}
+	write := func(name string, render func(w io.Writer)) error {
+		var b strings.Builder
+		render(&b)
+		path := filepath.Join(outDir, name+ext)
+		if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %v", path, err)
+		}
+		term.Println(path)
+		return nil
+	}
+	if err := write("govm", page); err != nil {
+		return err
+	}
+	for _, c := range visibleCommands() {
+		if err := write("govm-"+c.name, func(w io.Writer) { commandPage(w, c) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func visibleCommands() []*command {
+	var visible []*command
+	for _, c := range commands {
+		if !c.hidden {
+			visible = append(visible, c)
+		}
+	}
+	return visible
+}
+
+// roff escapes s for use in a man page.
+func roff(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
+
+func manHeader(w io.Writer, title string) {
+	fmt.Fprintf(w, ".TH %s 1 \"\" \"govm %s\" \"GoVM Manual\"\n", strings.ToUpper(roff(title)), roff(utils.GetVersion()))
+}
+
+// manFlags writes c's flags as a tagged paragraph list.
+func manFlags(w io.Writer, c *command) {
+	for _, line := range flagLines(newFlagSetFor(c)) {
+		fmt.Fprintf(w, ".TP\n.B %s\n%s\n", roff(line[0]), roff(line[1]))
+	}
+}
+
+func manExample(w io.Writer, c *command) {
+	fmt.Fprintf(w, ".PP\n.RS 4\n.nf\n%s\n.fi\n.RE\n", roff(c.example))
+}
+
+func manPage(w io.Writer) {
+	manHeader(w, "govm")
+	fmt.Fprintln(w, ".SH NAME")
+	fmt.Fprintln(w, `govm \- Go Version Manager`)
+	fmt.Fprintln(w, ".SH SYNOPSIS")
+	fmt.Fprintln(w, `.B govm`)
+	fmt.Fprintln(w, `[\fIglobal flags\fR] [\fIcommand\fR [\fIflags\fR] [\fIarguments\fR]]`)
+	fmt.Fprintln(w, ".SH DESCRIPTION")
+	fmt.Fprintln(w, "GoVM installs Go versions and switches between them. Without a command it launches the interactive TUI.")
+	fmt.Fprintln(w, ".SH COMMANDS")
+	for _, c := range visibleCommands() {
+		fmt.Fprintf(w, ".SS \"%s\"\n%s\n", roff(synopsis(c)), roff(c.summary))
+		if len(c.aliases) > 0 {
+			fmt.Fprintf(w, ".PP\nAliases: %s\n", roff(strings.Join(c.aliases, ", ")))
+		}
+		manFlags(w, c)
+		if c.example != "" {
+			manExample(w, c)
+		}
+	}
+	fmt.Fprintln(w, ".SH GLOBAL FLAGS")
+	for _, line := range globalFlags {
+		fmt.Fprintf(w, ".TP\n.B %s\n%s\n", roff(line[0]), roff(line[1]))
+	}
+	fmt.Fprintln(w, ".SH EXIT STATUS")
+	for _, s := range exitStatuses {
+		fmt.Fprintf(w, ".TP\n.B %d\n%s\n", s.code, roff(s.desc))
+	}
+	fmt.Fprintln(w, ".SH EXAMPLES")
+	for _, line := range usageExamples {
+		fmt.Fprintf(w, ".TP\n.B %s\n%s\n", roff(line[0]), roff(line[1]))
+	}
+}
+
+func manCommandPage(w io.Writer, c *command) {
+	manHeader(w, "govm-"+c.name)
+	fmt.Fprintln(w, ".SH NAME")
+	fmt.Fprintf(w, "govm\\-%s \\- %s\n", roff(c.name), roff(c.summary))
+	fmt.Fprintln(w, ".SH SYNOPSIS")
+	fmt.Fprintf(w, ".B %s\n", roff(synopsis(c)))
+	if len(c.aliases) > 0 {
+		fmt.Fprintln(w, ".SH ALIASES")
+		fmt.Fprintln(w, roff(strings.Join(c.aliases, ", ")))
+	}
+	if len(flagLines(newFlagSetFor(c))) > 0 {
+		fmt.Fprintln(w, ".SH OPTIONS")
+		manFlags(w, c)
+	}
+	if c.example != "" {
+		fmt.Fprintln(w, ".SH EXAMPLE")
+		manExample(w, c)
+	}
+	fmt.Fprintln(w, ".SH SEE ALSO")
+	fmt.Fprintln(w, `.BR govm (1)`)
+}
+
+// markdownCommand writes c's synopsis, aliases, flags and example under a
+// heading of the given level.
+func markdownCommand(w io.Writer, c *command, level int) {
+	fmt.Fprintf(w, "%s govm %s\n\n%s\n\n", strings.Repeat("#", level), c.name, c.summary)
+	fmt.Fprintf(w, "```\n%s\n```\n\n", synopsis(c))
+	if len(c.aliases) > 0 {
+		fmt.Fprintf(w, "Aliases: `%s`\n\n", strings.Join(c.aliases, "`, `"))
+	}
+	if lines := flagLines(newFlagSetFor(c)); len(lines) > 0 {
+		fmt.Fprintln(w, "| Flag | Description |")
+		fmt.Fprintln(w, "|------|-------------|")
+		for _, line := range lines {
+			fmt.Fprintf(w, "| `%s` | %s |\n", line[0], strings.ReplaceAll(line[1], "|", `\|`))
+		}
+		fmt.Fprintln(w)
+	}
+	if c.example != "" {
+		fmt.Fprintf(w, "Example:\n\n```bash\n%s\n```\n\n", c.example)
+	}
+}
+
+func markdownPage(w io.Writer) {
+	fmt.Fprintln(w, "# govm")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "GoVM installs Go versions and switches between them. Without a command it launches the interactive TUI.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "```\ngovm [global flags] [command [flags] [arguments]]\n```")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "## Commands")
+	fmt.Fprintln(w)
+	for _, c := range visibleCommands() {
+		markdownCommand(w, c, 3)
+	}
+	fmt.Fprintln(w, "## Global flags")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Flag | Description |")
+	fmt.Fprintln(w, "|------|-------------|")
+	for _, line := range globalFlags {
+		fmt.Fprintf(w, "| `%s` | %s |\n", line[0], line[1])
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "## Exit codes")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Code | Meaning |")
+	fmt.Fprintln(w, "|------|---------|")
+	for _, s := range exitStatuses {
+		fmt.Fprintf(w, "| %d | %s |\n", s.code, s.desc)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "## Examples")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "```bash")
+	for _, line := range usageExamples {
+		fmt.Fprintf(w, "# %s\n%s\n", line[1], line[0])
+	}
+	fmt.Fprintln(w, "```")
+}
+
+func markdownCommandPage(w io.Writer, c *command) {
+	markdownCommand(w, c, 1)
+	fmt.Fprintln(w, "See [govm](govm.md) for global flags and exit codes.")
+}
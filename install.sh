@@ -0,0 +1,54 @@
+#!/bin/sh
+# Installs the latest GoVM release and bootstraps a Go toolchain.
+#
+#   curl -fsSL https://raw.githubusercontent.com/melkeydev/govm/main/install.sh | sh
+set -eu
+
+REPO="melkeydev/govm"
+INSTALL_DIR="${GOVM_INSTALL_DIR:-$HOME/.govm/bin}"
+
+os=$(uname -s)
+case "$os" in
+  Linux) os="Linux" ;;
+  Darwin) os="Darwin" ;;
+  *) echo "Unsupported operating system: $os" >&2; exit 1 ;;
+esac
+
+arch=$(uname -m)
+case "$arch" in
+  x86_64|amd64) arch="x86_64" ;;
+  arm64|aarch64) arch="arm64" ;;
+  *) echo "Unsupported architecture: $arch" >&2; exit 1 ;;
+esac
+if [ "$os" = "Darwin" ]; then
+  arch="all"
+fi
+
+echo "==> Finding the latest GoVM release"
+tag=$(curl -fsSL "https://api.github.com/repos/$REPO/releases/latest" |
+  sed -n 's/.*"tag_name": *"\([^"]*\)".*/\1/p' | head -n 1)
+if [ -z "$tag" ]; then
+  echo "Could not determine the latest release" >&2
+  exit 1
+fi
+version=${tag#v}
+
+archive="govm_${version}_${os}_${arch}.tar.gz"
+tmp=$(mktemp -d)
+trap 'rm -rf "$tmp"' EXIT
+
+echo "==> Downloading $archive"
+curl -fsSL -o "$tmp/$archive" "https://github.com/$REPO/releases/download/$tag/$archive"
+tar -xzf "$tmp/$archive" -C "$tmp"
+
+mkdir -p "$INSTALL_DIR"
+mv "$tmp/govm" "$INSTALL_DIR/govm"
+chmod +x "$INSTALL_DIR/govm"
+echo "==> Installed govm to $INSTALL_DIR/govm"
+
+"$INSTALL_DIR/govm" bootstrap
+
+case ":$PATH:" in
+  *":$INSTALL_DIR:"*) ;;
+  *) echo "Add $INSTALL_DIR to your PATH to run govm directly." ;;
+esac
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+func ListAliases() {
+	names, aliases, err := utils.ListAliases()
+	if err != nil {
+		fail(err)
+		return
+	}
+	if len(names) == 0 {
+		term.Println("  No aliases defined. Create one with: govm alias set <name> <version>")
+		return
+	}
+	term.Println("📋 Aliases:")
+	for _, name := range names {
+		term.Printf("  %s -> %s\n", name, aliases[name])
+	}
+}
+
+func SetAlias(name, version string) {
+	version = strings.TrimPrefix(version, "go")
+	if isVersionKeyword(name) {
+		failCodef(ExitUsage, "%q is a built-in version keyword and cannot be an alias", name)
+		return
+	}
+	if err := utils.SetAlias(name, version); err != nil {
+		fail(err)
+		return
+	}
+	term.Printf("✅ %s now points to Go %s\n", name, version)
+}
+
+func RemoveAlias(name string) {
+	if err := utils.RemoveAlias(name); err != nil {
+		fail(err)
+		return
+	}
+	term.Printf("🗑️  Removed alias %s\n", name)
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/melkeydev/govm/internal/clock"
+	"github.com/melkeydev/govm/internal/term"
+)
+
+// staleAutoInstall is how long a background install may run before its
+// marker is assumed to have been left behind by a crashed process.
+const staleAutoInstall = 30 * time.Minute
+
+// autoInstallMarker is the file that records a background install of
+// version in progress, so that entering the project again does not start a
+// second one.
+func autoInstallMarker(homeDir, version string) string {
+	return filepath.Join(homeDir, ".govm", "auto_install", version)
+}
+
+// startBackgroundInstall installs version for the cd hook without blocking
+// the prompt: it starts 'govm background-install' detached from the hook,
+// logging to ~/.govm/auto_install.log, and prints a notice on stderr.
+func startBackgroundInstall(version, source string) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return
+	}
+	marker := autoInstallMarker(homeDir, version)
+	if info, err := os.Stat(marker); err == nil && clock.Since(info.ModTime()) < staleAutoInstall {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(marker), 0755); err != nil {
+		return
+	}
+	if err := os.WriteFile(marker, nil, 0644); err != nil {
+		return
+	}
+	self, err := os.Executable()
+	if err != nil {
+		os.Remove(marker)
+		return
+	}
+	logPath := filepath.Join(homeDir, ".govm", "auto_install.log")
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		os.Remove(marker)
+		return
+	}
+	defer logFile.Close()
+	cmd := exec.Command(self, "--quiet", "--no-emoji", "background-install", version, source)
+	cmd.Stdout = logFile
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		os.Remove(marker)
+		term.Fprintf(os.Stderr, "govm: failed to start installing Go %s: %v\n", version, err)
+		return
+	}
+	term.Fprintf(os.Stderr, "govm: %s pins Go %s, which is not installed; installing it in the background (log: %s)\n", source, version, logPath)
+}
+
+// BackgroundInstall is the process startBackgroundInstall starts. Progress
+// goes to its stdout, the log; only the outcome is reported on stderr, the
+// terminal the cd hook ran in.
+func BackgroundInstall(version, source string) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		failf("Failed to get home directory: %v", err)
+		return
+	}
+	defer os.Remove(autoInstallMarker(homeDir, version))
+	term.Printf("%s: installing Go %s for %s\n", clock.Now().UTC().Format(time.RFC3339), version, source)
+	matchedVersion, err := installPinned(version, source)
+	if err != nil {
+		term.Printf("%s: failed: %v\n", clock.Now().UTC().Format(time.RFC3339), err)
+		term.Fprintf(os.Stderr, "\ngovm: installing Go %s for %s failed; see %s\n", version, source, filepath.Join(homeDir, ".govm", "auto_install.log"))
+		return
+	}
+	term.Fprintf(os.Stderr, "\ngovm: Go %s is installed for %s\n", matchedVersion.Version, source)
+}
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/events"
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// CacheExport copies the active toolchain and the downloads directory into
+// a CI cache directory so a later pipeline run can restore them.
+func CacheExport(dir string) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		failf("Failed to get home directory: %v", err)
+		os.Exit(ExitCode())
+	}
+	govmDir := filepath.Join(homeDir, ".govm")
+	active := activeVersion()
+	if active == "" {
+		failf("No active version to export. Run 'govm use <version>' first.")
+		os.Exit(ExitCode())
+	}
+	versionName := "go" + active
+	if err := os.MkdirAll(filepath.Join(dir, "versions"), 0755); err != nil {
+		failf("Failed to create cache directory: %v", err)
+		os.Exit(ExitCode())
+	}
+	cachedVersion := filepath.Join(dir, "versions", versionName)
+	if utils.IsCompleteInstall(cachedVersion) {
+		term.Printf("✅ Go %s is already in the cache\n", active)
+	} else {
+		os.RemoveAll(cachedVersion)
+		term.Printf("📦 Exporting Go %s...\n", active)
+		if err := utils.CopyDir(filepath.Join(govmDir, "versions", versionName), cachedVersion); err != nil {
+			failf("Failed to export Go %s: %v", active, err)
+			os.Exit(ExitCode())
+		}
+	}
+	if err := copyMissing(filepath.Join(govmDir, "downloads"), filepath.Join(dir, "downloads")); err != nil {
+		failf("Failed to export downloads: %v", err)
+		os.Exit(ExitCode())
+	}
+	if err := os.WriteFile(filepath.Join(dir, "active_version"), []byte(active), 0644); err != nil {
+		failf("Failed to record active version: %v", err)
+		os.Exit(ExitCode())
+	}
+	term.Printf("✅ Exported cache to %s\n", dir)
+}
+
+// CacheRestore copies toolchains and downloads from a CI cache directory
+// back into ~/.govm, activating the cached version if none is active yet.
+func CacheRestore(dir string) {
+	if _, err := os.Stat(dir); err != nil {
+		term.Printf("ℹ️  No cache found at %s, nothing to restore\n", dir)
+		return
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		failf("Failed to get home directory: %v", err)
+		os.Exit(ExitCode())
+	}
+	govmDir := filepath.Join(homeDir, ".govm")
+	for _, sub := range []string{"versions", "downloads"} {
+		if err := copyMissing(filepath.Join(dir, sub), filepath.Join(govmDir, sub)); err != nil {
+			failf("Failed to restore %s: %v", sub, err)
+			os.Exit(ExitCode())
+		}
+	}
+	versionBytes, err := os.ReadFile(filepath.Join(dir, "active_version"))
+	if err != nil {
+		term.Printf("✅ Restored cache from %s\n", dir)
+		return
+	}
+	if _, err := os.Stat(filepath.Join(govmDir, "active_version")); err == nil {
+		term.Printf("✅ Restored cache from %s\n", dir)
+		return
+	}
+	version := strings.TrimSpace(string(versionBytes))
+	versionPath := filepath.Join(govmDir, "versions", "go"+version)
+	msg := utils.SwitchVersion(utils.GoVersion{Version: version, Path: versionPath, Installed: true})()
+	if errMsg, ok := msg.(events.ErrMsg); ok {
+		failf("Failed to activate Go %s: %v", version, errMsg)
+		os.Exit(ExitCode())
+	}
+	term.Printf("✅ Restored cache from %s and activated Go %s\n", dir, version)
+}
+
+// copyMissing copies each entry of src into dst unless dst already has it.
+func copyMissing(src, dst string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		target := filepath.Join(dst, entry.Name())
+		if _, err := os.Stat(target); err == nil {
+			continue
+		}
+		source := filepath.Join(src, entry.Name())
+		if entry.IsDir() {
+			err = utils.CopyDir(source, target)
+		} else {
+			err = utils.CopyFile(source, target, 0644)
+		}
+		if err != nil {
+			os.RemoveAll(target)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/melkeydev/govm/internal/events"
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// CI installs the project's pinned Go version without any prompts and
+// exports its environment for the detected CI system. An already installed
+// toolchain is reused as is, an archive already downloaded to the cache is
+// installed without the network, and installs are serialized with a lock so
+// jobs sharing a cache volume can run in parallel.
+func CI(version string) {
+	if version == "" {
+		cwd, _ := os.Getwd()
+		detected, source, err := utils.DetectProjectVersion(cwd)
+		if err != nil {
+			failf("No version given and %v", err)
+			os.Exit(ExitCode())
+		}
+		term.Fprintf(os.Stderr, "==> Using Go %s from %s\n", detected, source)
+		version = detected
+	}
+	version = strings.TrimPrefix(utils.ResolveAlias(version), "go")
+
+	release, err := utils.AcquireLock(10 * time.Minute)
+	if err != nil {
+		fail(err)
+		os.Exit(ExitCode())
+	}
+	installed, err := findInstalledVersion(version)
+	if err != nil || !utils.IsCompleteInstall(installed.Path) {
+		term.Fprintf(os.Stderr, "==> Installing Go %s\n", version)
+		remote, cached := cachedRelease(version)
+		if cached {
+			// The archive and its checksum are both on the cache volume, so
+			// the install needs no network at all
+			term.Fprintf(os.Stderr, "==> Using the cached %s\n", remote.Filename)
+			utils.SetOffline(true)
+		} else if remote, err = findMatchingVersion(version, false); err != nil {
+			release()
+			fail(err)
+			os.Exit(ExitCode())
+		}
+		switch msg := utils.DownloadAndInstall(remote)().(type) {
+		case events.ErrMsg:
+			release()
+			failf("Installation failed: %v", msg)
+			os.Exit(ExitCode())
+		case events.DownloadCompleteMsg:
+			installed = utils.GoVersion{Version: msg.Version, Path: msg.Path, Installed: true}
+		}
+	} else {
+		term.Fprintf(os.Stderr, "==> Go %s found in cache\n", installed.Version)
+	}
+	release()
+
+	goRoot := installed.Path
+	binDir := filepath.Join(goRoot, "bin")
+	switch {
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		term.Fprintln(os.Stderr, "==> Exporting environment for GitHub Actions")
+		if err := appendLine(os.Getenv("GITHUB_ENV"), "GOROOT="+goRoot); err != nil {
+			fail(err)
+			os.Exit(ExitCode())
+		}
+		if err := appendLine(os.Getenv("GITHUB_PATH"), binDir); err != nil {
+			fail(err)
+			os.Exit(ExitCode())
+		}
+	case os.Getenv("CIRCLECI") == "true":
+		term.Fprintln(os.Stderr, "==> Exporting environment for CircleCI")
+		if err := appendLine(os.Getenv("BASH_ENV"), ciExports(goRoot, binDir)); err != nil {
+			fail(err)
+			os.Exit(ExitCode())
+		}
+	default:
+		if os.Getenv("GITLAB_CI") == "true" {
+			term.Fprintln(os.Stderr, "==> Detected GitLab CI, run: eval \"$(govm ci)\"")
+		}
+		term.Println(ciExports(goRoot, binDir))
+	}
+	term.Fprintf(os.Stderr, "✅ Go %s is ready\n", installed.Version)
+}
+
+// ciExports returns the shell commands that put the Go in goRoot first on
+// PATH. The paths are single-quoted, so nothing in them is expanded.
+func ciExports(goRoot, binDir string) string {
+	return "export GOROOT=" + shQuote(goRoot) + "\nexport PATH=" + shQuote(binDir) + `:"$PATH"`
+}
+
+// cachedRelease returns the release named exactly by version from the
+// cached release index when its archive is already in ~/.govm/downloads.
+func cachedRelease(version string) (utils.GoVersion, bool) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return utils.GoVersion{}, false
+	}
+	versions, _ := utils.CachedRemoteVersions().(events.RemoteVersionsMsg)
+	for _, v := range versions {
+		if v.Version != version || v.SHA256 == "" {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(homeDir, ".govm", "downloads", v.Filename)); err == nil {
+			return v, true
+		}
+	}
+	return utils.GoVersion{}, false
+}
+
+func appendLine(path, line string) error {
+	if path == "" {
+		return fmt.Errorf("CI environment file is not set")
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %v", path, err)
+	}
+	defer f.Close()
+	_, err = fmt.Fprintln(f, line)
+	return err
+}
@@ -0,0 +1,25 @@
+package cli
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestCIExportsQuoting(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("no sh to evaluate the exports with")
+	}
+	// A GOROOT a double-quoted value would expand or cut short
+	goRoot := filepath.Join(t.TempDir(), `it's $HOME "go"`)
+	binDir := filepath.Join(goRoot, "bin")
+	exports := ciExports(goRoot, binDir)
+	got, err := exec.Command(sh, "-c", exports+"\n"+`printf '%s\n%s' "$GOROOT" "${PATH%%:*}"`).Output()
+	if err != nil {
+		t.Fatalf("sh rejected %q: %v", exports, err)
+	}
+	if want := goRoot + "\n" + binDir; string(got) != want {
+		t.Errorf("exports set %q, want %q", got, want)
+	}
+}
@@ -1,161 +1,455 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"github.com/melkeydev/govm/internal/clock"
+	"github.com/melkeydev/govm/internal/events"
+	"github.com/melkeydev/govm/internal/term"
 	"github.com/melkeydev/govm/internal/utils"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
 )
 
-func InstallVersion(version string) {
-	fmt.Printf("🔍 Looking for Go version matching %s...\n", version)
-	matchedVersion, err := findMatchingVersion(version)
+func InstallVersion(version string, pre bool) {
+	matchedVersion, err := resolveInstallTarget(version, pre)
 	if err != nil {
-		fmt.Printf("❌ %s\n", err)
+		fail(err)
 		return
 	}
-	fmt.Printf("📥 Installing Go %s...\n", matchedVersion.Version)
+	installResolved(matchedVersion)
+}
+
+// Update rebuilds a toolchain that tracks a moving target. Only tip does;
+// releases never change once published.
+func Update(version string) {
+	version = strings.TrimPrefix(version, "go")
+	if version != utils.TipVersion {
+		failCodef(ExitUsage, "Only tip can be updated; install a newer release with: govm install <version>")
+		return
+	}
+	if _, err := findInstalledVersion(version); err != nil {
+		failCodef(ExitNotInstalled, "Go %s is not installed. Build it first with: govm install %s", version, version)
+		return
+	}
+	term.Infoln("🚧 Rebuilding Go from the latest master commit; this can take several minutes")
+	installResolved(utils.TipGoVersion())
+}
+
+// resolveInstallTarget turns an install argument (version, alias, archive
+// filename or URL) into the release to install. With pre, a partial version
+// may resolve to a beta or release candidate.
+func resolveInstallTarget(version string, pre bool) (utils.GoVersion, error) {
+	version = utils.ResolveAlias(version)
+	if utils.IsArchiveReference(version) {
+		term.Infof("🔍 Resolving archive %s...\n", version)
+		matchedVersion, err := resolveArchiveReference(version)
+		if err != nil {
+			return matchedVersion, err
+		}
+		return matchedVersion, checkPolicy(matchedVersion.Version)
+	}
+	version, err := expandVersionKeyword(strings.TrimPrefix(version, "go"))
+	if err != nil {
+		return utils.GoVersion{}, err
+	}
+	if version == utils.TipVersion {
+		term.Infoln("🚧 Building Go from the master branch; tip builds are unstable and can take several minutes")
+		return utils.TipGoVersion(), nil
+	}
+	term.Infof("🔍 Looking for Go version matching %s...\n", version)
+	matchedVersion, err := findMatchingVersion(version, pre)
+	if err != nil {
+		return matchedVersion, err
+	}
+	return matchedVersion, checkPolicy(matchedVersion.Version)
+}
+
+// resolveArchiveReference parses an archive filename or URL and looks up
+// the checksum go.dev publishes for that filename, so a copy fetched from
+// elsewhere is verified too. Archives go.dev does not list install with the
+// warning utils.OnWeakChecksum gives.
+func resolveArchiveReference(ref string) (utils.GoVersion, error) {
+	version, err := utils.ParseArchiveReference(ref)
+	if err != nil {
+		return version, err
+	}
+	version.SHA256 = utils.PublishedChecksum(version.Filename)
+	return version, nil
+}
+
+// InstallVersions installs several versions one after another, or with
+// jobs above 1 up to that many at once, and prints a summary of the
+// results. With dryRun it only prints what each install would download and
+// create.
+func InstallVersions(versions []string, jobs int, pre, dryRun bool) {
+	if dryRun {
+		for _, arg := range versions {
+			matchedVersion, err := resolveInstallTarget(arg, pre)
+			if err != nil {
+				fail(err)
+				continue
+			}
+			planInstall(matchedVersion)
+		}
+		return
+	}
+	if len(versions) == 1 && !jsonOutput {
+		InstallVersion(versions[0], pre)
+		return
+	}
+	type result struct {
+		arg     string
+		version string
+		err     error
+	}
+	results := make([]result, len(versions))
+	if jobs > 1 {
+		// Resolve first so that two arguments naming the same release
+		// install it once
+		var pending []utils.GoVersion
+		pendingIndex := map[string]int{}
+		owners := make([]int, len(versions))
+		for i, arg := range versions {
+			results[i].arg = arg
+			owners[i] = -1
+			matchedVersion, err := resolveInstallTarget(arg, pre)
+			if err != nil {
+				results[i].err = err
+				continue
+			}
+			results[i].version = matchedVersion.Version
+			j, ok := pendingIndex[matchedVersion.Version]
+			if !ok {
+				j = len(pending)
+				pendingIndex[matchedVersion.Version] = j
+				pending = append(pending, matchedVersion)
+			}
+			owners[i] = j
+		}
+		errs := installConcurrently(pending, jobs)
+		for i, j := range owners {
+			if j >= 0 {
+				results[i].err = errs[j]
+			}
+		}
+	} else {
+		for i, arg := range versions {
+			results[i].arg = arg
+			matchedVersion, err := resolveInstallTarget(arg, pre)
+			if err != nil {
+				fail(err)
+				results[i].err = err
+				continue
+			}
+			results[i].version = matchedVersion.Version
+			results[i].err = installResolved(matchedVersion)
+		}
+	}
+	term.Println("\n📋 Summary:")
+	failed := 0
+	for _, r := range results {
+		name := r.arg
+		if r.version != "" {
+			name = "Go " + r.version
+		}
+		if r.err != nil {
+			failed++
+			setExitCode(exitCodeFor(r.err))
+			term.Printf("  ❌ %s: %v\n", name, r.err)
+		} else {
+			term.Printf("  ✅ %s\n", name)
+		}
+	}
+	term.Printf("\n%d installed, %d failed\n", len(results)-failed, failed)
+	if jsonOutput {
+		installed := make([]InstallResult, len(results))
+		for i, r := range results {
+			installed[i] = InstallResult{Requested: r.arg, Version: r.version}
+			if r.err != nil {
+				installed[i].Error = r.err.Error()
+			} else if v, err := findInstalledVersion(r.version); err == nil {
+				installed[i].GOROOT = v.Path
+			}
+		}
+		printJSON(installed)
+	}
+}
+
+func installResolved(matchedVersion utils.GoVersion) error {
+	term.Infof("📥 Installing Go %s...\n", matchedVersion.Version)
 	done := make(chan bool)
 	errCh := make(chan error)
+	progressCh := make(chan events.DownloadProgressMsg, 1)
 	go func() {
-		msg := utils.DownloadAndInstall(matchedVersion)()
+		msg := utils.DownloadAndInstallWithProgress(matchedVersion, func(p events.DownloadProgressMsg) {
+			// Drop a report rather than stall the download while the
+			// spinner is busy; the next one supersedes it anyway
+			select {
+			case progressCh <- p:
+			default:
+			}
+		})()
 		switch msg := msg.(type) {
-		case utils.ErrMsg:
+		case events.ErrMsg:
 			errCh <- msg
-		case utils.DownloadCompleteMsg:
+		case events.DownloadCompleteMsg:
 			done <- true
 		}
 	}()
-	spinChars := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	spinChars := term.SpinnerFrames()
 	spinIdx := 0
-	ticker := time.NewTicker(100 * time.Millisecond)
+	ticker := clock.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
+	var progress events.DownloadProgressMsg
 	for {
 		select {
 		case <-done:
-			fmt.Printf("\r✅ Successfully installed Go %s\n", matchedVersion.Version)
-			fmt.Printf("👉 To activate this version, run: govm use %s\n", matchedVersion.Version)
-			return
+			term.Printf("\r\033[K✅ Successfully installed Go %s\n", matchedVersion.Version)
+			term.Infof("👉 To activate this version, run: govm use %s\n", matchedVersion.Version)
+			return nil
 		case err := <-errCh:
-			fmt.Printf("\r❌ Installation failed: %v\n", err)
-			return
-		case <-ticker.C:
-			fmt.Printf("\r%s Installing Go %s...", spinChars[spinIdx], matchedVersion.Version)
+			term.Println()
+			failf("Installation failed: %v", err)
+			return err
+		case progress = <-progressCh:
+		case <-ticker.C():
+			// Once the archive is complete the spinner covers extracting it
+			if progress.Downloaded > 0 && progress.Downloaded != progress.Total {
+				term.Infof("\r\033[K%s Downloading Go %s... %s", spinChars[spinIdx], matchedVersion.Version, progress)
+			} else {
+				term.Infof("\r\033[K%s Installing Go %s...", spinChars[spinIdx], matchedVersion.Version)
+			}
 			spinIdx = (spinIdx + 1) % len(spinChars)
 		}
 	}
 }
-func UseVersion(version string) {
-	fmt.Printf("🔍 Looking for installed Go version matching %s...\n", version)
+
+// UseVersion switches the shims to version. With dryRun it only prints the
+// files the switch would write.
+func UseVersion(version string, dryRun bool) {
+	if version == "-" {
+		rollback(dryRun)
+		return
+	}
+	version, err := expandVersionKeyword(strings.TrimPrefix(utils.ResolveAlias(version), "go"))
+	if err != nil {
+		fail(err)
+		return
+	}
+	term.Infof("🔍 Looking for installed Go version matching %s...\n", version)
 	matchedVersion, err := findInstalledVersion(version)
 	if err != nil {
-		fmt.Printf("❌ %s\n", err)
+		fail(err)
+		return
+	}
+	if err := checkPolicy(matchedVersion.Version); err != nil {
+		fail(err)
+		return
+	}
+	if dryRun {
+		planSwitch(matchedVersion)
 		return
 	}
-	fmt.Printf("🔄 Switching to Go %s...\n", matchedVersion.Version)
-	msg := utils.SwitchVersion(matchedVersion)()
+	term.Infof("🔄 Switching to Go %s...\n", matchedVersion.Version)
+	written := 0
+	msg := utils.SwitchVersionWithProgress(matchedVersion, func(done, total int, tool string, changed bool) {
+		if changed {
+			written++
+		}
+		term.Infof("\r\033[K  Updating shims %d/%d (%s)", done, total, tool)
+		if done == total {
+			term.Infof("\r\033[K  %d of %d shims updated\n", written, total)
+		}
+	})()
 	switch msg := msg.(type) {
-	case utils.ErrMsg:
-		fmt.Printf("❌ Failed to switch version: %v\n", msg)
-	case utils.SwitchCompletedMsg:
-		fmt.Printf("✅ Switched to Go %s\n", matchedVersion.Version)
+	case events.ErrMsg:
+		term.Println()
+		failf("Failed to switch version: %v", msg)
+	case events.SwitchCompletedMsg:
+		term.Printf("✅ Switched to Go %s\n", matchedVersion.Version)
+		if jsonOutput {
+			printJSON(UseResult{
+				Version:    matchedVersion.Version,
+				GOROOT:     matchedVersion.Path,
+				ShimInPath: msg.ShimInPath,
+			})
+			return
+		}
 		if !utils.IsShimInPath() {
-			fmt.Println("\n⚠️  GoVM is not in your PATH")
-			fmt.Println(utils.GetShimPathInstructions())
+			term.Println("\n⚠️  GoVM is not in your PATH")
+			term.Println(utils.GetShimPathInstructions())
 		} else {
-			fmt.Println("🚀 Run 'go version' in a new terminal to verify")
+			term.Infoln("🚀 Run 'go version' in a new terminal to verify")
 		}
 	}
 }
-func ListVersions() {
-	fmt.Println("📋 Installed Go Versions:")
+
+// ListVersions prints the installed versions with when each was installed
+// and last switched to, as ISO-8601 with iso set.
+func ListVersions(iso bool) {
+	if jsonOutput {
+		status, err := currentStatus("")
+		if err != nil {
+			fail(err)
+			return
+		}
+		printJSON(status.Installed)
+		return
+	}
+	term.Infoln("📋 Installed Go Versions:")
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		fmt.Printf("❌ Error getting home directory: %v\n", err)
+		failf("Error getting home directory: %v", err)
 		return
 	}
-	activeVersion := ""
-	activeVersionFile := filepath.Join(homeDir, ".govm", "active_version")
-	if versionBytes, err := os.ReadFile(activeVersionFile); err == nil {
-		activeVersion = string(versionBytes)
-	}
+	active := activeVersion()
 	goVersionsDir := filepath.Join(homeDir, ".govm", "versions")
 	if _, err := os.Stat(goVersionsDir); os.IsNotExist(err) {
-		fmt.Println("  No versions installed yet")
+		term.Println("  No versions installed yet")
 		return
 	}
 	entries, err := os.ReadDir(goVersionsDir)
 	if err != nil {
-		fmt.Printf("❌ Error reading versions directory: %v\n", err)
+		failf("Error reading versions directory: %v", err)
 		return
 	}
 	if len(entries) == 0 {
-		fmt.Println("  No versions installed yet")
+		term.Println("  No versions installed yet")
 		return
 	}
+	records, _ := utils.ReadSwitchHistory()
+	lastUsed := utils.LastUsed(records)
 	for _, entry := range entries {
 		if entry.IsDir() && strings.HasPrefix(entry.Name(), "go") {
 			version := strings.TrimPrefix(entry.Name(), "go")
-			if version == activeVersion {
-				fmt.Printf("  %s %s\n", version, "✓ (active)")
-			} else {
-				fmt.Printf("  %s\n", version)
+			label := ""
+			if version == utils.TipVersion {
+				label = " (unstable, built from source)"
 			}
+			if version == active {
+				label = " ✓ (active)" + label
+			}
+			term.Printf("  %s%s%s\n", version, label, usageDates(filepath.Join(goVersionsDir, entry.Name()), lastUsed[version], iso))
 		}
 	}
-	fmt.Println("\nTo install a new version: govm install <version>")
-	fmt.Println("To switch versions: govm use <version>")
+	term.Infoln("\nTo install a new version: govm install <version>")
+	term.Infoln("To switch versions: govm use <version>")
+}
+
+// usageDates describes when the toolchain at versionPath was installed and,
+// unless lastUsed is zero, last switched to, for a line of 'govm list'.
+func usageDates(versionPath string, lastUsed time.Time, iso bool) string {
+	var dates []string
+	if at, ok := utils.InstalledAt(versionPath); ok {
+		dates = append(dates, "installed "+utils.FormatTimestamp(at, iso))
+	}
+	if !lastUsed.IsZero() {
+		dates = append(dates, "last used "+utils.FormatTimestamp(lastUsed, iso))
+	}
+	if len(dates) == 0 {
+		return ""
+	}
+	return "  " + strings.Join(dates, ", ")
 }
-func findMatchingVersion(version string) (utils.GoVersion, error) {
+
+// findMatchingVersion resolves version against the releases on go.dev. An
+// exact version such as 1.24rc1 always matches; a partial one picks the newest
+// final release of that line, or with pre also betas and release candidates.
+// A range such as ~1.22 picks the newest release inside it.
+func findMatchingVersion(version string, pre bool) (utils.GoVersion, error) {
+	if utils.IsVersionConstraint(version) {
+		return findMatchingRange(version, pre)
+	}
 	msg := utils.FetchGoVersions()
-	versions, ok := msg.(utils.VersionsMsg)
+	versions, ok := msg.(events.VersionsMsg)
 	if !ok {
-		if errMsg, isErr := msg.(utils.ErrMsg); isErr {
-			return utils.GoVersion{}, fmt.Errorf("failed to fetch versions: %v", errMsg)
+		if errMsg, isErr := msg.(events.ErrMsg); isErr {
+			return utils.GoVersion{}, withExitCode(ExitNetwork, fmt.Errorf("failed to fetch versions: %v", errMsg))
 		}
-		return utils.GoVersion{}, fmt.Errorf("failed to fetch versions")
+		return utils.GoVersion{}, withExitCode(ExitNetwork, fmt.Errorf("failed to fetch versions"))
 	}
 	for _, v := range versions {
 		if v.Version == version {
 			return v, nil
 		}
 	}
-	prefix := version + "."
 	var matchedVersion utils.GoVersion
 	found := false
 	for _, v := range versions {
-		if strings.HasPrefix(v.Version, prefix) {
-			if !found || compareVersions(v.Version, matchedVersion.Version) > 0 {
-				matchedVersion = v
-				found = true
-			}
+		release, _ := utils.SplitPreRelease(v.Version)
+		if utils.IsPreRelease(v.Version) && !pre {
+			continue
+		}
+		if release != version && !strings.HasPrefix(release, version+".") {
+			continue
+		}
+		if !found || compareVersions(v.Version, matchedVersion.Version) > 0 {
+			matchedVersion = v
+			found = true
 		}
 	}
-	if !found && !strings.Contains(version, ".") {
-		prefix = version + "."
+	if found {
+		return matchedVersion, nil
+	}
+	if !pre {
 		for _, v := range versions {
-			if strings.HasPrefix(v.Version, prefix) {
-				if !found || compareVersions(v.Version, matchedVersion.Version) > 0 {
-					matchedVersion = v
-					found = true
-				}
+			if release, _ := utils.SplitPreRelease(v.Version); utils.IsPreRelease(v.Version) && (release == version || strings.HasPrefix(release, version+".")) {
+				return utils.GoVersion{}, withExitCode(ExitNotFound, fmt.Errorf("no release matching '%s' found; pass --pre to install pre-release %s", version, v.Version))
 			}
 		}
 	}
-	if found {
-		return matchedVersion, nil
+	return utils.GoVersion{}, withExitCode(ExitNotFound, fmt.Errorf("no version matching '%s' found", version))
+}
+
+// findMatchingRange is findMatchingVersion for a version range.
+func findMatchingRange(version string, pre bool) (utils.GoVersion, error) {
+	constraint, err := utils.ParseVersionConstraint(version)
+	if err != nil {
+		return utils.GoVersion{}, withExitCode(ExitUsage, err)
 	}
-	return utils.GoVersion{}, fmt.Errorf("no version matching '%s' found", version)
+	msg := utils.FetchGoVersions()
+	versions, ok := msg.(events.VersionsMsg)
+	if !ok {
+		if errMsg, isErr := msg.(events.ErrMsg); isErr {
+			return utils.GoVersion{}, withExitCode(ExitNetwork, fmt.Errorf("failed to fetch versions: %v", errMsg))
+		}
+		return utils.GoVersion{}, withExitCode(ExitNetwork, fmt.Errorf("failed to fetch versions"))
+	}
+	var matchedVersion utils.GoVersion
+	found := false
+	for _, v := range versions {
+		if (utils.IsPreRelease(v.Version) && !pre) || !constraint.Match(v.Version) {
+			continue
+		}
+		if !found || compareVersions(v.Version, matchedVersion.Version) > 0 {
+			matchedVersion = v
+			found = true
+		}
+	}
+	if !found {
+		return utils.GoVersion{}, withExitCode(ExitNotFound, fmt.Errorf("no release in range '%s' found", version))
+	}
+	return matchedVersion, nil
 }
+
+// findInstalledVersion resolves version against the installed versions: an
+// exact match, else the newest installed release of a partial version or
+// inside a range.
 func findInstalledVersion(version string) (utils.GoVersion, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return utils.GoVersion{}, fmt.Errorf("failed to get home directory: %v", err)
 	}
 	goVersionsDir := filepath.Join(homeDir, ".govm", "versions")
+	if utils.IsVersionConstraint(version) {
+		return findInstalledRange(goVersionsDir, version)
+	}
 	versionDir := filepath.Join(goVersionsDir, "go"+version)
 	if _, err := os.Stat(versionDir); err == nil {
 		return utils.GoVersion{
@@ -205,72 +499,822 @@ func findInstalledVersion(version string) (utils.GoVersion, error) {
 	if found {
 		return matchedVersion, nil
 	}
-	return utils.GoVersion{}, fmt.Errorf("no installed version matching '%s' found", version)
+	return utils.GoVersion{}, withExitCode(ExitNotInstalled, fmt.Errorf("no installed version matching '%s' found", version))
 }
+
+// findInstalledRange is findInstalledVersion for a version range.
+func findInstalledRange(goVersionsDir, version string) (utils.GoVersion, error) {
+	constraint, err := utils.ParseVersionConstraint(version)
+	if err != nil {
+		return utils.GoVersion{}, withExitCode(ExitUsage, err)
+	}
+	entries, err := os.ReadDir(goVersionsDir)
+	if err != nil {
+		return utils.GoVersion{}, fmt.Errorf("failed to read versions directory: %v", err)
+	}
+	var matchedVersion utils.GoVersion
+	found := false
+	for _, entry := range entries {
+		versionStr, ok := strings.CutPrefix(entry.Name(), "go")
+		if !entry.IsDir() || !ok || versionStr == utils.TipVersion || !constraint.Match(versionStr) {
+			continue
+		}
+		if !found || compareVersions(versionStr, matchedVersion.Version) > 0 {
+			matchedVersion = utils.GoVersion{
+				Version:   versionStr,
+				Path:      filepath.Join(goVersionsDir, entry.Name()),
+				Installed: true,
+			}
+			found = true
+		}
+	}
+	if !found {
+		return utils.GoVersion{}, withExitCode(ExitNotInstalled, fmt.Errorf("no installed version in range '%s' found", version))
+	}
+	return matchedVersion, nil
+}
+
 func compareVersions(v1, v2 string) int {
-	parts1 := strings.Split(v1, ".")
-	parts2 := strings.Split(v2, ".")
-	for i := 0; i < len(parts1) && i < len(parts2); i++ {
-		p1, _ := strconv.Atoi(parts1[i])
-		p2, _ := strconv.Atoi(parts2[i])
-		if p1 < p2 {
-			return -1
+	return utils.CompareVersions(v1, v2)
+}
+
+func DeleteVersion(version string, dryRun bool) {
+	version = strings.TrimPrefix(utils.ResolveAlias(version), "go")
+	term.Infof("🔍 Looking for installed Go version matching %s...\n", version)
+	matchedVersion, err := findInstalledVersion(version)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	if matchedVersion.Version == activeVersion() {
+		failf("Cannot delete active version. Switch to another version first using 'govm use'.")
+		return
+	}
+	if dryRun {
+		planDelete([]utils.GoVersion{matchedVersion})
+		return
+	}
+
+	if !confirmDestructive(fmt.Sprintf("⚠️  Are you sure you want to delete Go %s? (y/N): ", matchedVersion.Version)) {
+		term.Println("🛑 Operation canceled.")
+		return
+	}
+
+	term.Printf("🗑️  Deleting Go %s...\n", matchedVersion.Version)
+
+	msg := utils.DeleteVersion(matchedVersion)()
+	switch msg := msg.(type) {
+	case events.ErrMsg:
+		failf("Failed to delete version: %v", msg)
+	case events.DeleteCompleteMsg:
+		term.Printf("✅ Successfully deleted Go %s\n", matchedVersion.Version)
+	}
+}
+
+func ListRemoteVersions(pre bool, major string, limit int) {
+	if !jsonOutput {
+		term.Infoln("🌐 Available Go Versions:")
+	}
+	msg := utils.FetchGoVersions()
+	versions, ok := msg.(events.VersionsMsg)
+	if !ok {
+		if errMsg, isErr := msg.(events.ErrMsg); isErr {
+			failf("Failed to fetch versions: %v", errMsg)
+		} else {
+			failCodef(ExitNetwork, "Failed to fetch versions")
+		}
+		return
+	}
+	major = strings.TrimPrefix(major, "go")
+	shown := 0
+	remote := []RemoteVersion{}
+	for _, v := range versions {
+		// Betas and release candidates are only listed on request
+		if !pre && !v.Stable {
+			continue
 		}
-		if p1 > p2 {
-			return 1
+		if major != "" && v.Version != major && !strings.HasPrefix(v.Version, major+".") {
+			continue
 		}
+		if limit > 0 && shown >= limit {
+			break
+		}
+		shown++
+		if jsonOutput {
+			remote = append(remote, RemoteVersion{
+				Version:     v.Version,
+				Stable:      v.Stable,
+				Installed:   v.Installed,
+				Active:      v.Active,
+				Installable: v.Unsupported == "",
+				Reason:      v.Unsupported,
+			})
+			continue
+		}
+		term.Printf("  %s%s\n", v.Version, remoteStatus(v))
 	}
-	if len(parts1) < len(parts2) {
-		return -1
+	if jsonOutput {
+		printJSON(remote)
+		return
 	}
-	if len(parts1) > len(parts2) {
-		return 1
+	if shown == 0 {
+		term.Println("  No versions match the given filters")
+		return
 	}
-	return 0
+	term.Infoln("\nTo install a version: govm install <version>")
 }
 
-func DeleteVersion(version string) {
-	fmt.Printf("🔍 Looking for installed Go version matching %s...\n", version)
-	matchedVersion, err := findInstalledVersion(version)
+// remoteStatus describes whether a release is active, installed, unstable or
+// not installable, as shown next to it in ls-remote and search.
+func remoteStatus(v utils.GoVersion) string {
+	status := ""
+	if v.Active {
+		status = " ✓ (active)"
+	} else if v.Installed {
+		status = " (installed)"
+	}
+	if !v.Stable {
+		status += " (unstable)"
+	}
+	if v.Unsupported != "" {
+		status += " (not installable via govm)"
+	}
+	return status
+}
+
+func PruneVersions(dryRun bool) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		failf("Failed to get home directory: %v", err)
+		return
+	}
+	active := activeVersion()
+	goVersionsDir := filepath.Join(homeDir, ".govm", "versions")
+	entries, err := os.ReadDir(goVersionsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			term.Println("✨ Nothing to prune, no versions installed yet")
+			return
+		}
+		failf("Error reading versions directory: %v", err)
+		return
+	}
+	// Keep the newest patch release of every minor line
+	latest := map[string]string{}
+	var installed []utils.GoVersion
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), "go") {
+			continue
+		}
+		version := strings.TrimPrefix(entry.Name(), "go")
+		installed = append(installed, utils.GoVersion{
+			Version:   version,
+			Path:      filepath.Join(goVersionsDir, entry.Name()),
+			Installed: true,
+		})
+		minor := minorVersion(version)
+		if current, ok := latest[minor]; !ok || compareVersions(version, current) > 0 {
+			latest[minor] = version
+		}
+	}
+	var stale []utils.GoVersion
+	for _, v := range installed {
+		if v.Version == active || latest[minorVersion(v.Version)] == v.Version {
+			continue
+		}
+		stale = append(stale, v)
+	}
+	if len(stale) == 0 {
+		term.Println("✨ Nothing to prune")
+		return
+	}
+	if dryRun {
+		term.Println("📋 The following versions would be removed:")
+		for _, v := range stale {
+			term.Printf("  %s\n", v.Version)
+		}
+		return
+	}
+	term.Println("The following versions will be removed:")
+	for _, v := range stale {
+		term.Printf("  %s\n", v.Version)
+	}
+	if !confirmDestructive(fmt.Sprintf("⚠️  Prune %d version(s)? (y/N): ", len(stale))) {
+		term.Println("🛑 Operation canceled.")
+		return
+	}
+	for _, v := range stale {
+		term.Printf("🗑️  Deleting Go %s...\n", v.Version)
+		msg := utils.DeleteVersion(v)()
+		if errMsg, ok := msg.(events.ErrMsg); ok {
+			failf("Failed to delete version: %v", errMsg)
+		}
+	}
+	term.Printf("✅ Pruned %d version(s)\n", len(stale))
+}
+
+// minorVersion returns the major.minor line a version belongs to, e.g. "1.22"
+// for both "1.22.4" and "1.22rc1".
+func minorVersion(version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return version
+	}
+	minor := parts[1]
+	if i := strings.IndexFunc(minor, func(r rune) bool { return r < '0' || r > '9' }); i >= 0 {
+		minor = minor[:i]
+	}
+	return parts[0] + "." + minor
+}
+
+// CleanDownloads removes the archives kept in ~/.govm/downloads and, with
+// partial, version directories left by failed installs. It holds the
+// install lock, and leaves alone .part downloads and .extract- directories
+// younger than utils.StaleLockAge, which may belong to an install still
+// running in another process.
+func CleanDownloads(partial bool) {
+	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		fmt.Printf("❌ %s\n", err)
+		failf("Failed to get home directory: %v", err)
 		return
 	}
+	release, err := utils.AcquireLock(time.Minute)
+	if err != nil {
+		fail(err)
+		return
+	}
+	defer release()
+	var reclaimed int64
+	removed := 0
+	downloadDir := filepath.Join(homeDir, ".govm", "downloads")
+	entries, err := os.ReadDir(downloadDir)
+	if err != nil && !os.IsNotExist(err) {
+		failf("Error reading downloads directory: %v", err)
+		return
+	}
+	for _, entry := range entries {
+		entryPath := filepath.Join(downloadDir, entry.Name())
+		if strings.HasSuffix(entry.Name(), ".part") && inProgress(entry) {
+			term.Printf("⏳ Skipping %s: a download may still be writing it\n", entry.Name())
+			continue
+		}
+		size := dirSize(entryPath)
+		if err := os.RemoveAll(entryPath); err != nil {
+			failf("Failed to remove %s: %v", entry.Name(), err)
+			continue
+		}
+		reclaimed += size
+		removed++
+	}
+	if partial {
+		// Installs that failed partway leave a version directory without a
+		// working go binary behind
+		goVersionsDir := filepath.Join(homeDir, ".govm", "versions")
+		entries, _ := os.ReadDir(goVersionsDir)
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			versionPath := filepath.Join(goVersionsDir, entry.Name())
+			if utils.IsCompleteInstall(versionPath) {
+				continue
+			}
+			if strings.HasPrefix(entry.Name(), ".extract-") && inProgress(entry) {
+				term.Printf("⏳ Skipping %s: an install may still be extracting into it\n", entry.Name())
+				continue
+			}
+			if err := utils.CheckNotInside(versionPath); err != nil {
+				term.Printf("⚠️  Skipping %s: %v\n", entry.Name(), err)
+				continue
+			}
+			size := dirSize(versionPath)
+			if err := os.RemoveAll(versionPath); err != nil {
+				failf("Failed to remove %s: %v", entry.Name(), err)
+				continue
+			}
+			term.Printf("🗑️  Removed incomplete install %s\n", entry.Name())
+			reclaimed += size
+			removed++
+		}
+	}
+	// Entries rebuild themselves on the next lookup, so dropping them only
+	// costs a walk
+	if err := utils.ClearProjectCache(); err != nil {
+		failf("Failed to clear the project cache: %v", err)
+	}
+	if removed == 0 {
+		term.Println("✨ Nothing to clean")
+		return
+	}
+	term.Printf("✅ Removed %d item(s), reclaimed %s\n", removed, events.FormatBytes(reclaimed))
+}
+
+// inProgress reports whether entry was modified recently enough that an
+// install in another process may still be writing it.
+func inProgress(entry os.DirEntry) bool {
+	info, err := entry.Info()
+	return err == nil && clock.Since(info.ModTime()) < utils.StaleLockAge
+}
 
+func dirSize(path string) int64 {
+	var size int64
+	filepath.WalkDir(path, func(_ string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
+
+// Bootstrap performs first-time setup non-interactively: it installs and
+// activates the latest stable Go, adds the shim directory to the shell
+// config and verifies the result. Output is line based so that it reads
+// well when piped from the install script. With dryRun it only prints what
+// it would download, write and append.
+func Bootstrap(dryRun bool) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		fmt.Printf("❌ Failed to get home directory: %v\n", err)
+		failf("Failed to get home directory: %v", err)
+		os.Exit(ExitCode())
+	}
+	term.Infoln("==> Setting up GoVM directories")
+	if dryRun {
+		if _, err := os.Stat(filepath.Join(homeDir, ".govm", "shim")); err != nil {
+			term.Printf("📋 Would create %s\n", filepath.Join(homeDir, ".govm", "shim"))
+		}
+	} else if err := utils.SetupShimDirectory(); err != nil {
+		fail(err)
+		os.Exit(ExitCode())
+	}
+	term.Infoln("==> Looking up the latest stable Go release")
+	msg := utils.FetchGoVersions()
+	versions, ok := msg.(events.VersionsMsg)
+	if !ok {
+		failf("Failed to fetch versions: %v", msg)
+		os.Exit(ExitCode())
+	}
+	var latest utils.GoVersion
+	for _, v := range versions {
+		if v.Stable {
+			latest = v
+			break
+		}
+	}
+	if latest.Version == "" {
+		failf("No stable Go release found for this platform")
+		os.Exit(ExitCode())
+	}
+	if dryRun {
+		if !latest.Installed {
+			planInstall(latest)
+			latest.Path = filepath.Join(homeDir, ".govm", "versions", "go"+latest.Version)
+		}
+		planSwitch(latest)
+		if !utils.IsShimInPath() {
+			configFile, line := shellConfigFile(homeDir)
+			term.Printf("📋 Would append to %s:\n  %s\n", configFile, line)
+		}
 		return
 	}
+	if latest.Installed {
+		term.Infof("==> Go %s is already installed\n", latest.Version)
+	} else {
+		term.Infof("==> Installing Go %s\n", latest.Version)
+		switch msg := utils.DownloadAndInstall(latest)().(type) {
+		case events.ErrMsg:
+			failf("Installation failed: %v", msg)
+			os.Exit(ExitCode())
+		case events.DownloadCompleteMsg:
+			latest.Installed = true
+			latest.Path = msg.Path
+		}
+	}
+	term.Infof("==> Activating Go %s\n", latest.Version)
+	if msg, ok := utils.SwitchVersion(latest)().(events.ErrMsg); ok {
+		failf("Failed to switch version: %v", msg)
+		os.Exit(ExitCode())
+	}
+	term.Infoln("==> Configuring shell")
+	if utils.IsShimInPath() {
+		term.Println("    Shim directory is already in PATH")
+	} else if configFile, err := configureShell(); err != nil {
+		term.Printf("⚠️  Could not configure shell automatically: %v\n", err)
+		term.Println(utils.GetShimPathInstructions())
+	} else {
+		term.Printf("    Updated %s\n", configFile)
+	}
+	term.Infoln("==> Verifying installation")
+	goShim := filepath.Join(homeDir, ".govm", "shim", "go")
+	if runtime.GOOS == "windows" {
+		goShim += ".bat"
+	}
+	output, err := exec.Command(goShim, "version").CombinedOutput()
+	if err != nil {
+		failf("Verification failed: %v\n%s", err, output)
+		os.Exit(ExitCode())
+	}
+	term.Printf("    %s", output)
+	term.Println("✅ GoVM is ready. Open a new terminal to start using Go.")
+}
 
-	activeVersionFile := filepath.Join(homeDir, ".govm", "active_version")
-	activeVersion := ""
-	if versionBytes, err := os.ReadFile(activeVersionFile); err == nil {
-		activeVersion = string(versionBytes)
+// shellConfigFile returns the config file of the user's login shell and the
+// line that puts the shim directory at the front of PATH in it.
+func shellConfigFile(homeDir string) (string, string) {
+	shell := os.Getenv("SHELL")
+	switch {
+	case strings.Contains(shell, "zsh"):
+		return filepath.Join(homeDir, ".zshrc"), `export PATH="$HOME/.govm/shim:$PATH"`
+	case strings.Contains(shell, "fish"):
+		return filepath.Join(homeDir, ".config", "fish", "config.fish"), `fish_add_path --move "$HOME/.govm/shim"`
 	}
+	return filepath.Join(homeDir, ".bashrc"), `export PATH="$HOME/.govm/shim:$PATH"`
+}
 
-	if matchedVersion.Version == activeVersion {
-		fmt.Printf("❌ Cannot delete active version. Switch to another version first using 'govm use'.\n")
+// configureShell appends the shim directory to the user's shell config file
+// unless it is already referenced there, returning the file it changed.
+func configureShell() (string, error) {
+	if runtime.GOOS == "windows" {
+		return "", fmt.Errorf("automatic PATH setup is not supported on Windows")
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	configFile, line := shellConfigFile(homeDir)
+	if content, err := os.ReadFile(configFile); err == nil && strings.Contains(string(content), ".govm/shim") {
+		return configFile, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(configFile), 0755); err != nil {
+		return "", err
+	}
+	f, err := os.OpenFile(configFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := fmt.Fprintf(f, "\n%s\n%s\n", govmMarker, line); err != nil {
+		return "", err
+	}
+	return configFile, nil
+}
+
+// Which prints the binary tool's shim runs from the current directory, which
+// is the pinned version's when a pin file applies.
+func Which(tool string) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		failf("Failed to get current directory: %v", err)
+		return
+	}
+	res, err := utils.ResolveShim(tool, cwd)
+	if err != nil {
+		fail(err)
 		return
 	}
+	target := res.Target
+	term.Println(target)
+	version := utils.VersionFromPath(target)
+	if version == "" {
+		version = "unknown"
+	}
+	term.Printf("  version: %s\n", version)
+	if res.PinSource != "" {
+		if res.PinInstalled {
+			term.Printf("  pinned by: %s\n", res.PinSource)
+		} else {
+			term.Printf("⚠️  %s pins Go %s, which is not installed; the global version runs instead\n", res.PinSource, res.Pin)
+		}
+	}
+	if _, err := os.Stat(target); err != nil {
+		term.Println("⚠️  The target binary does not exist; reinstall this version or run 'govm use' again")
+	}
+}
+
+// Exec runs a command with the given installed version first on PATH and
+// GOROOT pointing at it, without changing the active version. A minor
+// version such as 1.22 floats to its newest installed patch. With
+// project.auto_install on, a version that is not installed, or a newer
+// patch of a floating one, is installed first. It returns the command's
+// exit code.
+func Exec(version string, args []string) int {
+	return execVersion(version, "govm exec", args)
+}
+
+// execVersion is Exec on behalf of source, which named version.
+func execVersion(version, source string, args []string) int {
+	version = strings.TrimPrefix(utils.ResolveAlias(version), "go")
+	if autoInstall() {
+		installNewestPatch(version, source)
+	}
+	matchedVersion, err := findInstalledVersion(version)
+	if err != nil && exitCodeFor(err) == ExitNotInstalled && autoInstall() {
+		if _, err := installPinned(version, source); err != nil {
+			return ExitCode()
+		}
+		matchedVersion, err = findInstalledVersion(version)
+	}
+	if err != nil {
+		fail(err)
+		return ExitCode()
+	}
+	binDir := filepath.Join(matchedVersion.Path, "bin")
+	env := []string{}
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, "PATH=") || strings.HasPrefix(kv, "GOROOT=") {
+			continue
+		}
+		env = append(env, kv)
+	}
+	env = append(env,
+		"PATH="+binDir+string(os.PathListSeparator)+os.Getenv("PATH"),
+		"GOROOT="+matchedVersion.Path,
+	)
+	name := args[0]
+	// Resolve against the version's bin directory first so "go" never
+	// falls through to the shim
+	if _, err := os.Stat(filepath.Join(binDir, name)); err == nil {
+		name = filepath.Join(binDir, name)
+	} else if _, err := os.Stat(filepath.Join(binDir, name+".exe")); err == nil {
+		name = filepath.Join(binDir, name+".exe")
+	}
+	cmd := exec.Command(name, args[1:]...)
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return exitErr.ExitCode()
+		}
+		failf("Failed to run %s: %v", args[0], err)
+		return ExitCode()
+	}
+	return 0
+}
+
+// PrintEnv prints where GoVM keeps its toolchains in shell, fish,
+// powershell, json or dotenv format for consumption by build tooling.
+func PrintEnv(format string) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		failf("Failed to get home directory: %v", err)
+		os.Exit(ExitCode())
+	}
+	active := activeVersion()
+	goVersionsDir := filepath.Join(homeDir, ".govm", "versions")
+	goRoot := ""
+	if active != "" {
+		goRoot = filepath.Join(goVersionsDir, "go"+active)
+	}
+	vars := [][2]string{
+		{"GOROOT", goRoot},
+		{"GOVM_SHIM_DIR", filepath.Join(homeDir, ".govm", "shim")},
+		{"GOVM_ACTIVE_VERSION", active},
+		{"GOVM_VERSIONS_DIR", goVersionsDir},
+	}
+	switch format {
+	case "shell", "":
+		for _, kv := range vars {
+			term.Printf("export %s=%s\n", kv[0], shQuote(kv[1]))
+		}
+	case "fish":
+		for _, kv := range vars {
+			term.Printf("set -gx %s %s\n", kv[0], shQuote(kv[1]))
+		}
+	case "powershell", "pwsh":
+		for _, kv := range vars {
+			term.Printf("$env:%s = %s\n", kv[0], psQuote(kv[1]))
+		}
+	case "dotenv":
+		for _, kv := range vars {
+			term.Printf("%s=%s\n", kv[0], strconv.Quote(kv[1]))
+		}
+	case "json":
+		env := map[string]string{}
+		for _, kv := range vars {
+			env[kv[0]] = kv[1]
+		}
+		out, _ := json.MarshalIndent(env, "", "  ")
+		term.Println(string(out))
+	default:
+		failCodef(ExitUsage, "Unknown format %q (expected shell, fish, powershell, json or dotenv)", format)
+		os.Exit(ExitCode())
+	}
+}
 
-	fmt.Printf("⚠️  Are you sure you want to delete Go %s? (y/N): ", matchedVersion.Version)
-	var response string
-	fmt.Scanln(&response)
+// Rollback switches back to the version that was active before the last
+// switch. Running it twice returns to where you started.
+func Rollback() {
+	rollback(false)
+}
 
-	if strings.ToLower(response) != "y" {
-		fmt.Println("🛑 Operation canceled.")
+// rollback is Rollback; with dryRun it only prints what rolling back would
+// restore and write.
+func rollback(dryRun bool) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		failf("Failed to get home directory: %v", err)
 		return
 	}
+	previousBytes, err := os.ReadFile(filepath.Join(homeDir, ".govm", "previous_version"))
+	if err != nil {
+		failf("No previous version recorded yet")
+		return
+	}
+	previous := strings.TrimSpace(string(previousBytes))
+	installed, err := findInstalledVersion(previous)
+	missing := err != nil || installed.Version != previous || !utils.IsCompleteInstall(installed.Path)
+	if dryRun {
+		if !missing {
+			UseVersion(previous, true)
+			return
+		}
+		planRestore(previous)
+		planSwitch(utils.GoVersion{Version: previous, Path: filepath.Join(homeDir, ".govm", "versions", "go"+previous)})
+		return
+	}
+	term.Printf("⏪ Rolling back to Go %s...\n", previous)
+	if missing {
+		if err := reinstallForRollback(previous); err != nil {
+			failf("Go %s is no longer installed and could not be reinstalled: %v", previous, err)
+			return
+		}
+	}
+	UseVersion(previous, false)
+}
 
-	fmt.Printf("🗑️  Deleting Go %s...\n", matchedVersion.Version)
+// reinstallForRollback brings back a version that was pruned or deleted
+// since it was last active, preferring an archive left in the downloads
+// directory over downloading it again.
+func reinstallForRollback(version string) error {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	downloadDir := filepath.Join(homeDir, ".govm", "downloads")
+	if archives := cachedArchives(downloadDir, version); len(archives) > 0 {
+		term.Printf("📦 Go %s was removed, restoring it from %s...\n", version, archives[0])
+		_, err := utils.ExtractArchive(utils.GoVersion{Version: version, Filename: archives[0]}, filepath.Join(downloadDir, archives[0]))
+		return err
+	}
+	term.Infof("📥 Go %s was removed and no archive is cached, downloading it again...\n", version)
+	matchedVersion, err := findMatchingVersion(version, false)
+	if err != nil {
+		return err
+	}
+	if matchedVersion.Version != version {
+		return fmt.Errorf("Go %s is no longer available on go.dev", version)
+	}
+	return installResolved(matchedVersion)
+}
 
-	msg := utils.DeleteVersion(matchedVersion)()
-	switch msg := msg.(type) {
-	case utils.ErrMsg:
-		fmt.Printf("❌ Failed to delete version: %v\n", msg)
-	case utils.DeleteCompleteMsg:
-		fmt.Printf("✅ Successfully deleted Go %s\n", matchedVersion.Version)
+// Download fetches a release archive into ~/.govm/downloads without touching
+// the shims or the active version. With extract the archive is also
+// unpacked into the versions directory.
+func Download(version string, extract bool) {
+	version = utils.ResolveAlias(version)
+	var matchedVersion utils.GoVersion
+	var err error
+	if utils.IsArchiveReference(version) {
+		matchedVersion, err = resolveArchiveReference(version)
+	} else {
+		version = strings.TrimPrefix(version, "go")
+		term.Infof("🔍 Looking for Go version matching %s...\n", version)
+		matchedVersion, err = findMatchingVersion(version, false)
+	}
+	if err != nil {
+		fail(err)
+		return
+	}
+	term.Infof("📥 Downloading Go %s...\n", matchedVersion.Version)
+	downloadPath, err := utils.DownloadArchiveWithProgress(matchedVersion, func(p events.DownloadProgressMsg) {
+		term.Infof("\r\033[K   %s", p)
+	})
+	if err != nil {
+		term.Println()
+		failf("Download failed: %v", err)
+		return
+	}
+	term.Printf("\r\033[K✅ Downloaded %s\n", downloadPath)
+	if !extract {
+		return
+	}
+	term.Printf("📦 Extracting Go %s...\n", matchedVersion.Version)
+	versionDir, err := utils.ExtractArchive(matchedVersion, downloadPath)
+	if err != nil {
+		failf("Extraction failed: %v", err)
+		return
+	}
+	term.Printf("✅ Installed Go %s to %s\n", matchedVersion.Version, versionDir)
+	term.Infof("👉 To activate this version, run: govm use %s\n", matchedVersion.Version)
+}
+
+// History prints recent version switches, newest first.
+func History(iso bool) {
+	records, err := utils.ReadSwitchHistory()
+	if err != nil {
+		failf("Failed to read switch history: %v", err)
+		return
+	}
+	if len(records) == 0 {
+		term.Println("  No switches recorded yet")
+		return
+	}
+	term.Println("📋 Recent switches:")
+	for _, record := range records {
+		term.Printf("  %s  %s\n", utils.FormatTimestamp(record.Time, iso), record.Version)
 	}
+	term.Println("\nTo switch back: govm use <version> (or 'govm use -' for the previous one)")
 }
 
+// DeleteVersions deletes every version named in args plus, with
+// allExceptActive, every installed version but the active one, or with
+// olderThan, every installed version older than that version. The active
+// version is never deleted. All targets are confirmed with a single prompt,
+// or with dryRun only listed.
+func DeleteVersions(args []string, allExceptActive bool, olderThan string, dryRun bool) {
+	if len(args) == 1 && !allExceptActive && olderThan == "" {
+		DeleteVersion(args[0], dryRun)
+		return
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		failf("Failed to get home directory: %v", err)
+		return
+	}
+	active := activeVersion()
+
+	var targets []utils.GoVersion
+	seen := map[string]bool{}
+	add := func(v utils.GoVersion) {
+		if !seen[v.Version] && v.Version != active {
+			seen[v.Version] = true
+			targets = append(targets, v)
+		}
+	}
+	for _, arg := range args {
+		version := strings.TrimPrefix(utils.ResolveAlias(arg), "go")
+		matchedVersion, err := findInstalledVersion(version)
+		if err != nil {
+			fail(err)
+			return
+		}
+		if matchedVersion.Version == active {
+			term.Printf("⚠️  Skipping Go %s because it is the active version\n", active)
+		}
+		add(matchedVersion)
+	}
+	if allExceptActive || olderThan != "" {
+		olderThan = strings.TrimPrefix(utils.ResolveAlias(olderThan), "go")
+		goVersionsDir := filepath.Join(homeDir, ".govm", "versions")
+		entries, _ := os.ReadDir(goVersionsDir)
+		for _, entry := range entries {
+			if !entry.IsDir() || !strings.HasPrefix(entry.Name(), "go") {
+				continue
+			}
+			version := strings.TrimPrefix(entry.Name(), "go")
+			// A tip build has no release number to compare against
+			if olderThan != "" && (version == utils.TipVersion || compareVersions(version, olderThan) >= 0) {
+				continue
+			}
+			add(utils.GoVersion{
+				Version:   version,
+				Path:      filepath.Join(goVersionsDir, entry.Name()),
+				Installed: true,
+			})
+		}
+	}
+	if len(targets) == 0 {
+		term.Println("✨ Nothing to delete")
+		return
+	}
+	if dryRun {
+		planDelete(targets)
+		return
+	}
+
+	term.Println("The following versions will be deleted:")
+	for _, v := range targets {
+		term.Printf("  %s\n", v.Version)
+	}
+	if !confirmDestructive(fmt.Sprintf("⚠️  Are you sure you want to delete these %d versions? (y/N): ", len(targets))) {
+		term.Println("🛑 Operation canceled.")
+		return
+	}
+	for _, v := range targets {
+		term.Printf("🗑️  Deleting Go %s...\n", v.Version)
+		switch msg := utils.DeleteVersion(v)().(type) {
+		case events.ErrMsg:
+			failf("Failed to delete version: %v", msg)
+		case events.DeleteCompleteMsg:
+			term.Printf("✅ Successfully deleted Go %s\n", v.Version)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/term"
+)
+
+// CompletionCommand is a subcommand the completion scripts offer, with
+// the flags it accepts spelled as on the command line, e.g. "--json" or
+// "-o". main builds the list from its command table.
+type CompletionCommand struct {
+	Name  string
+	Flags []string
+}
+
+// versionCommands take an installed version as their first argument.
+var versionCommands = []string{"use", "delete", "exec", "verify", "export", "goroot", "shell"}
+
+// Completion prints a completion script for the given shell that
+// completes commands and their flags.
+func Completion(shell string, commands []CompletionCommand) {
+	switch shell {
+	case "bash":
+		term.Print(bashCompletion(commands))
+	case "zsh":
+		term.Print("#compdef govm\nautoload -U +X bashcompinit && bashcompinit\n")
+		term.Print(bashCompletion(commands))
+	case "fish":
+		term.Print(fishCompletion(commands))
+	case "powershell":
+		term.Print(powershellCompletion(commands))
+	default:
+		failCodef(ExitUsage, "Unsupported shell %q (expected bash, zsh, fish or powershell)", shell)
+		os.Exit(ExitCode())
+	}
+}
+
+func bashCompletion(commands []CompletionCommand) string {
+	var b strings.Builder
+	b.WriteString("# bash completion for govm\n")
+	b.WriteString("_govm() {\n")
+	b.WriteString("    local cur prev\n")
+	b.WriteString("    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	b.WriteString("    prev=\"${COMP_WORDS[1]}\"\n")
+	b.WriteString("    if [ \"$COMP_CWORD\" -eq 1 ]; then\n")
+	fmt.Fprintf(&b, "        COMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(commandNames(commands), " "))
+	b.WriteString("        return\n")
+	b.WriteString("    fi\n")
+	// Flags are completed only once a dash is typed so they never hide
+	// the command's positional arguments
+	b.WriteString("    if [[ \"$cur\" == -* ]]; then\n")
+	b.WriteString("        case \"$prev\" in\n")
+	for _, c := range commands {
+		if len(c.Flags) > 0 {
+			fmt.Fprintf(&b, "        %s) COMPREPLY=($(compgen -W %q -- \"$cur\")) ;;\n", c.Name, strings.Join(c.Flags, " "))
+		}
+	}
+	b.WriteString("        esac\n")
+	b.WriteString("        return\n")
+	b.WriteString("    fi\n")
+	b.WriteString("    case \"$prev\" in\n")
+	fmt.Fprintf(&b, "    %s)\n", strings.Join(versionCommands, "|"))
+	b.WriteString("        if [ \"$COMP_CWORD\" -eq 2 ]; then\n")
+	b.WriteString("            COMPREPLY=($(compgen -W \"$(ls \"$HOME/.govm/versions\" 2>/dev/null | sed 's/^go//')\" -- \"$cur\"))\n")
+	b.WriteString("        fi ;;\n")
+	b.WriteString("    shim) [ \"$COMP_CWORD\" -eq 2 ] && COMPREPLY=($(compgen -W \"list trace\" -- \"$cur\")) ;;\n")
+	b.WriteString("    cache) [ \"$COMP_CWORD\" -eq 2 ] && COMPREPLY=($(compgen -W \"export restore\" -- \"$cur\")) ;;\n")
+	b.WriteString("    alias) [ \"$COMP_CWORD\" -eq 2 ] && COMPREPLY=($(compgen -W \"list set rm\" -- \"$cur\")) ;;\n")
+	b.WriteString("    tool) [ \"$COMP_CWORD\" -eq 2 ] && COMPREPLY=($(compgen -W \"list install use\" -- \"$cur\")) ;;\n")
+	b.WriteString("    migrate) [ \"$COMP_CWORD\" -eq 2 ] && COMPREPLY=($(compgen -W \"goenv gvm g asdf\" -- \"$cur\")) ;;\n")
+	b.WriteString("    mise) [ \"$COMP_CWORD\" -eq 2 ] && COMPREPLY=($(compgen -W \"list-all path\" -- \"$cur\")) ;;\n")
+	b.WriteString("    gen-docs) COMPREPLY=($(compgen -W \"man markdown\" -- \"$cur\")) ;;\n")
+	b.WriteString("    completion|init|hook) COMPREPLY=($(compgen -W \"bash zsh fish powershell\" -- \"$cur\")) ;;\n")
+	b.WriteString("    esac\n")
+	b.WriteString("}\n")
+	b.WriteString("complete -F _govm govm\n")
+	return b.String()
+}
+
+func fishCompletion(commands []CompletionCommand) string {
+	var b strings.Builder
+	b.WriteString("# fish completion for govm\n")
+	b.WriteString("complete -c govm -f\n")
+	fmt.Fprintf(&b, "complete -c govm -n __fish_use_subcommand -a %q\n", strings.Join(commandNames(commands), " "))
+	for _, c := range commands {
+		for _, flag := range c.Flags {
+			option := "-l " + strings.TrimPrefix(flag, "--")
+			if !strings.HasPrefix(flag, "--") {
+				option = "-s " + strings.TrimPrefix(flag, "-")
+			}
+			fmt.Fprintf(&b, "complete -c govm -n '__fish_seen_subcommand_from %s' %s\n", c.Name, option)
+		}
+	}
+	fmt.Fprintf(&b, "complete -c govm -n '__fish_seen_subcommand_from %s' -a '(ls $HOME/.govm/versions 2>/dev/null | string replace -r \"^go\" \"\")'\n", strings.Join(versionCommands, " "))
+	b.WriteString("complete -c govm -n '__fish_seen_subcommand_from shim' -a 'list trace'\n")
+	b.WriteString("complete -c govm -n '__fish_seen_subcommand_from cache' -a 'export restore'\n")
+	b.WriteString("complete -c govm -n '__fish_seen_subcommand_from tool' -a 'list install use'\n")
+	b.WriteString("complete -c govm -n '__fish_seen_subcommand_from alias' -a 'list set rm'\n")
+	b.WriteString("complete -c govm -n '__fish_seen_subcommand_from migrate' -a 'goenv gvm g asdf'\n")
+	b.WriteString("complete -c govm -n '__fish_seen_subcommand_from gen-docs' -a 'man markdown'\n")
+	b.WriteString("complete -c govm -n '__fish_seen_subcommand_from completion init hook' -a 'bash zsh fish powershell'\n")
+	return b.String()
+}
+
+func powershellCompletion(commands []CompletionCommand) string {
+	var b strings.Builder
+	b.WriteString("# PowerShell completion for govm\n")
+	b.WriteString("Register-ArgumentCompleter -Native -CommandName govm -ScriptBlock {\n")
+	b.WriteString("    param($wordToComplete, $commandAst, $cursorPosition)\n")
+	b.WriteString("    $words = $commandAst.CommandElements | ForEach-Object { $_.ToString() }\n")
+	b.WriteString("    if ($words.Count -le 2 -and -not ($words.Count -eq 2 -and $wordToComplete -eq '')) {\n")
+	fmt.Fprintf(&b, "        $candidates = @('%s')\n", strings.Join(commandNames(commands), "', '"))
+	b.WriteString("    } else {\n")
+	b.WriteString("        $candidates = switch ($words[1]) {\n")
+	for _, c := range commands {
+		if len(c.Flags) > 0 {
+			fmt.Fprintf(&b, "            '%s' { @('%s') }\n", c.Name, strings.Join(c.Flags, "', '"))
+		}
+	}
+	fmt.Fprintf(&b, "            { $_ -in @('%s') } { Get-ChildItem \"$HOME\\.govm\\versions\" -Directory -ErrorAction SilentlyContinue | ForEach-Object { $_.Name -replace '^go', '' } }\n", strings.Join(versionCommands, "', '"))
+	b.WriteString("            'shim' { @('list', 'trace') }\n")
+	b.WriteString("            'cache' { @('export', 'restore') }\n")
+	b.WriteString("            'tool' { @('list', 'install', 'use') }\n")
+	b.WriteString("            'alias' { @('list', 'set', 'rm') }\n")
+	b.WriteString("            'migrate' { @('goenv', 'gvm', 'g', 'asdf') }\n")
+	b.WriteString("            'gen-docs' { @('man', 'markdown') }\n")
+	b.WriteString("            { $_ -in @('completion', 'init', 'hook') } { @('bash', 'zsh', 'fish', 'powershell') }\n")
+	b.WriteString("            default { @() }\n")
+	b.WriteString("        }\n")
+	b.WriteString("    }\n")
+	b.WriteString("    $candidates | Where-Object { $_ -like \"$wordToComplete*\" } | ForEach-Object {\n")
+	b.WriteString("        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)\n")
+	b.WriteString("    }\n")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func commandNames(commands []CompletionCommand) []string {
+	names := make([]string, len(commands))
+	for i, c := range commands {
+		names[i] = c.Name
+	}
+	return names
+}
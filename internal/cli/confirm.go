@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/term"
+)
+
+// assumeYes answers the confirmation prompts of the destructive commands
+// with yes, for automation that runs without a TTY.
+var assumeYes = false
+
+// AssumeYes makes delete, prune and self-uninstall skip their prompts.
+func AssumeYes() {
+	assumeYes = true
+}
+
+// confirm asks prompt and reports whether the user answered y. Without a
+// terminal there is no one to answer, so it reports false.
+func confirm(prompt string) bool {
+	term.Print(prompt)
+	var response string
+	fmt.Scanln(&response)
+	return strings.ToLower(response) == "y"
+}
+
+// confirmDestructive is confirm for the prompts of delete, prune and
+// self-uninstall, the only ones --yes or GOVM_ASSUME_YES set to a true value
+// answer on the user's behalf. Without either and without a terminal it
+// refuses with a usage error rather than going ahead unasked.
+func confirmDestructive(prompt string) bool {
+	if yes, _ := strconv.ParseBool(os.Getenv("GOVM_ASSUME_YES")); assumeYes || yes {
+		term.Print(prompt)
+		term.Println("y")
+		return true
+	}
+	if !term.Interactive() {
+		failCodef(ExitUsage, "Refusing to delete without a terminal to confirm on; pass --yes (or set GOVM_ASSUME_YES=1) to go ahead")
+		return false
+	}
+	return confirm(prompt)
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/melkeydev/govm/internal/term"
+)
+
+func TestConfirmDestructiveUnattended(t *testing.T) {
+	if term.Interactive() {
+		t.Skip("stdin is a terminal")
+	}
+	t.Cleanup(func() {
+		assumeYes = false
+		exitCode = ExitOK
+	})
+
+	t.Setenv("GOVM_ASSUME_YES", "")
+	if confirmDestructive("Delete? ") {
+		t.Error("confirmDestructive went ahead without a terminal or --yes")
+	}
+	if ExitCode() != ExitUsage {
+		t.Errorf("exit code = %d, want %d", ExitCode(), ExitUsage)
+	}
+
+	t.Setenv("GOVM_ASSUME_YES", "1")
+	if !confirmDestructive("Delete? ") {
+		t.Error("GOVM_ASSUME_YES=1 did not answer yes")
+	}
+	// The variable answers only the destructive prompts
+	if confirm("Edit ~/.bashrc? ") {
+		t.Error("GOVM_ASSUME_YES=1 answered a non-destructive prompt")
+	}
+
+	t.Setenv("GOVM_ASSUME_YES", "")
+	AssumeYes()
+	if !confirmDestructive("Delete? ") {
+		t.Error("--yes did not answer yes")
+	}
+}
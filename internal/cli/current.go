@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// versionLayer is one step of the order the shims resolve a version in.
+type versionLayer struct {
+	name   string
+	source string
+	value  string
+}
+
+// Current prints the Go version the go shim runs in the current directory.
+// With explain it walks through every layer of the resolution order (the
+// GOVM_GO_VERSION override, the 'govm shell' session, the nearest pin file
+// and the global version) and says which one won and why. With --json it
+// prints a CurrentResult instead.
+func Current(explain bool) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		failf("Failed to get current directory: %v", err)
+		return
+	}
+	res, err := utils.ResolveShim("go", cwd)
+	if err != nil {
+		failCodef(ExitNotInstalled, "No Go version is active. Run 'govm use <version>' first.")
+		return
+	}
+	version := utils.VersionFromPath(res.Target)
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		failf("Failed to get home directory: %v", err)
+		return
+	}
+	activeVersionFile := filepath.Join(homeDir, ".govm", "active_version")
+	if jsonOutput {
+		result := CurrentResult{
+			Version: version,
+			GOROOT:  filepath.Dir(filepath.Dir(res.Target)),
+			Layer:   "global",
+			Source:  activeVersionFile,
+		}
+		switch {
+		case res.PinSource != "" && !res.PinInstalled:
+			result.MissingPin, result.MissingPinSource = res.Pin, res.PinSource
+		case res.PinSource == utils.VersionEnv:
+			result.Layer, result.Source = "env", res.PinSource
+		case res.PinSource == utils.ShellVersionEnv:
+			result.Layer, result.Source = "shell", res.PinSource
+		case res.PinSource != "":
+			result.Layer, result.Source = "local", res.PinSource
+		}
+		printJSON(result)
+		return
+	}
+	if !explain {
+		term.Println(version)
+		return
+	}
+
+	localPin, localSource, _ := utils.DetectProjectVersion(cwd)
+	if localSource == "" {
+		localSource = strings.Join(utils.PinFiles(), ", ")
+	}
+	layers := []versionLayer{
+		{"env", utils.VersionEnv, os.Getenv(utils.VersionEnv)},
+		{"shell", utils.ShellVersionEnv, os.Getenv(utils.ShellVersionEnv)},
+		{"local", localSource, localPin},
+		{"global", activeVersionFile, activeVersion()},
+	}
+	winner := len(layers) - 1
+	for i, layer := range layers[:winner] {
+		if layer.value != "" {
+			winner = i
+			break
+		}
+	}
+	// A layer naming a version that is not installed doesn't hand over to the
+	// next one; the shims fall back to the global version
+	skipped := -1
+	if winner < len(layers)-1 && !res.PinInstalled {
+		skipped, winner = winner, len(layers)-1
+	}
+
+	switch {
+	case skipped >= 0:
+		term.Printf("Go %s, the global version, because %s names Go %s, which is not installed\n", version, layers[skipped].source, layers[skipped].value)
+	case winner == len(layers)-1:
+		term.Printf("Go %s, the global version, because nothing overrides it here\n", version)
+	default:
+		term.Printf("Go %s, selected by %s\n", version, layers[winner].source)
+	}
+	term.Println()
+	term.Println("Resolution order (the first layer that is set wins):")
+	top := winner
+	if skipped >= 0 {
+		top = skipped
+	}
+	for i, layer := range layers {
+		value := layer.value
+		switch {
+		case value == "":
+			value = "(not set)"
+		case i == skipped:
+			value += " (not installed)"
+		case i == winner:
+			value += "  ← selected"
+		case i > top:
+			value += " (overridden)"
+		}
+		term.Printf("  %d. %-7s %-*s %s\n", i+1, layer.name, sourceWidth(layers), layer.source, value)
+	}
+	if layers[1].value == "" {
+		term.Println()
+		term.Println(`Set a version for this shell session with: eval "$(govm shell <version>)"`)
+	}
+}
+
+func sourceWidth(layers []versionLayer) int {
+	width := 0
+	for _, layer := range layers {
+		width = max(width, len(layer.source))
+	}
+	return width
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/events"
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// Diff summarizes what changed upstream between two Go versions: the minor
+// releases crossed, with links to their release notes, and the patch
+// releases in between.
+func Diff(from, to string) {
+	from = strings.TrimPrefix(utils.ResolveAlias(from), "go")
+	to = strings.TrimPrefix(utils.ResolveAlias(to), "go")
+	if compareVersions(from, to) > 0 {
+		from, to = to, from
+	}
+	versions, ok := utils.FetchGoVersions().(events.VersionsMsg)
+	if !ok {
+		failCodef(ExitNetwork, "Failed to fetch versions from go.dev")
+		return
+	}
+	// Patch releases newer than from and up to to, grouped by minor line
+	between := map[string][]string{}
+	for _, v := range versions {
+		if !v.Stable || compareVersions(v.Version, from) <= 0 || compareVersions(v.Version, to) > 0 {
+			continue
+		}
+		minor := minorVersion(v.Version)
+		between[minor] = append(between[minor], v.Version)
+	}
+	if len(between) == 0 {
+		term.Printf("No stable releases between Go %s and Go %s\n", from, to)
+		return
+	}
+	minors := make([]string, 0, len(between))
+	for minor := range between {
+		minors = append(minors, minor)
+	}
+	sort.Slice(minors, func(i, j int) bool { return compareVersions(minors[i], minors[j]) < 0 })
+
+	summaries, err := utils.FetchReleaseSummaries()
+	if err != nil {
+		term.Printf("⚠️  Release summaries unavailable: %v\n", err)
+	}
+	term.Printf("📋 Changes from Go %s to Go %s\n", from, to)
+	total := 0
+	for _, minor := range minors {
+		patches := between[minor]
+		sort.Slice(patches, func(i, j int) bool { return compareVersions(patches[i], patches[j]) < 0 })
+		total += len(patches)
+		term.Println()
+		if minor != minorVersion(from) {
+			term.Printf("  Go %s (new minor release): %s\n", minor, utils.ReleaseNotesURL(minor))
+		} else {
+			term.Printf("  Go %s\n", minor)
+		}
+		term.Printf("    %d patch release(s): %s\n", len(patches), strings.Join(patches, ", "))
+		for _, patch := range patches {
+			if summary, ok := summaries[patch]; ok {
+				term.Printf("    - %s\n", summary)
+			}
+		}
+	}
+	term.Printf("\n%d release(s) between Go %s and Go %s across %d minor line(s)\n", total, from, to, len(minors))
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// Doctor checks the GoVM setup for common problems: the shim directory not
+// being on PATH, a missing active version, shims pointing at removed
+// binaries, and shims edited by hand, which it offers to restore.
+func Doctor() {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		failf("Failed to get home directory: %v", err)
+		return
+	}
+	term.Infoln("🩺 Checking your GoVM setup...")
+	var problems []string
+	defer func() {
+		if len(problems) > 0 {
+			setExitCode(ExitFailure)
+		}
+		if jsonOutput {
+			printJSON(DoctorReport{OK: len(problems) == 0, Problems: append([]string{}, problems...)})
+		}
+	}()
+
+	if utils.IsShimInPath() {
+		term.Println("  ✅ Shim directory is in PATH")
+	} else {
+		term.Println("  ❌ Shim directory is not in PATH")
+		term.Printf("     %s\n", utils.GetShimPathInstructions())
+		problems = append(problems, "shim directory is not in PATH")
+	}
+
+	active := activeVersion()
+	switch {
+	case active == "":
+		term.Println("  ➖ No active version set")
+	case utils.IsCompleteInstall(filepath.Join(homeDir, ".govm", "versions", "go"+active)):
+		term.Printf("  ✅ Active version Go %s is installed\n", active)
+	default:
+		term.Printf("  ❌ Active version Go %s is not installed\n", active)
+		problems = append(problems, fmt.Sprintf("active version Go %s is not installed", active))
+	}
+
+	shims, err := utils.ListShims()
+	if err != nil {
+		term.Printf("  ❌ %s\n", err)
+		problems = append(problems, err.Error())
+		return
+	}
+	var modified []string
+	for _, shim := range shims {
+		if shim.Target != "" && !shim.TargetExists {
+			term.Printf("  ❌ Shim %s points at missing %s\n", shim.Tool, shim.Target)
+			problems = append(problems, fmt.Sprintf("shim %s points at missing %s", shim.Tool, shim.Target))
+		}
+		if shim.Modified {
+			modified = append(modified, shim.Tool)
+		}
+	}
+	if len(modified) > 0 {
+		term.Printf("  ⚠️  %d shim(s) were changed outside govm: %s\n", len(modified), strings.Join(modified, ", "))
+		// JSON output is for scripts, which cannot answer the prompt
+		if !jsonOutput && confirm("Restore the shims govm generated? (y/N): ") {
+			for _, tool := range modified {
+				if err := utils.RestoreShim(tool); err != nil {
+					term.Printf("  ❌ %s\n", err)
+					problems = append(problems, err.Error())
+					continue
+				}
+				term.Printf("  ✅ Restored %s\n", tool)
+			}
+		} else {
+			for _, tool := range modified {
+				problems = append(problems, fmt.Sprintf("shim %s was changed outside govm", tool))
+			}
+		}
+	} else if len(shims) > 0 {
+		term.Printf("  ✅ %d shim(s) match what govm generated\n", len(shims))
+	}
+
+	if len(problems) > 0 {
+		term.Printf("\n⚠️  Found %d problem(s); 'govm repair' can fix most of them\n", len(problems))
+		return
+	}
+	term.Println("\n✅ No problems found")
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// The plan functions below print what a mutating command would do to the
+// filesystem under --dry-run. They only read: network lookups are fine, but
+// nothing is downloaded, written or removed.
+
+// planInstall prints the files installing v would download and create.
+func planInstall(v utils.GoVersion) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		failf("Failed to get home directory: %v", err)
+		return
+	}
+	govmDir := filepath.Join(homeDir, ".govm")
+	versionDir := filepath.Join(govmDir, "versions", "go"+v.Version)
+	term.Printf("📋 Installing Go %s would:\n", v.Version)
+	if v.Version == utils.TipVersion {
+		term.Printf("  clone or update %s in %s\n", v.URL, filepath.Join(govmDir, "src", "go"))
+		term.Printf("  build it and create %s\n", versionDir)
+	} else {
+		term.Printf("  download %s\n", v.URL)
+		term.Printf("  save it as %s\n", filepath.Join(govmDir, "downloads", v.Filename))
+		term.Printf("  create %s\n", versionDir)
+	}
+	if _, err := os.Stat(versionDir); err == nil {
+		term.Printf("  remove the existing %s first\n", versionDir)
+	}
+}
+
+// planRestore prints how rolling back would bring back version after it
+// was removed, the way reinstallForRollback does it.
+func planRestore(version string) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		failf("Failed to get home directory: %v", err)
+		return
+	}
+	downloadDir := filepath.Join(homeDir, ".govm", "downloads")
+	if archives := cachedArchives(downloadDir, version); len(archives) > 0 {
+		term.Printf("📋 Go %s was removed; rolling back would extract %s into %s\n", version, filepath.Join(downloadDir, archives[0]), filepath.Join(homeDir, ".govm", "versions", "go"+version))
+		return
+	}
+	matchedVersion, err := findMatchingVersion(version, false)
+	if err != nil {
+		fail(err)
+		return
+	}
+	planInstall(matchedVersion)
+}
+
+// planSwitch prints the files activating v would write.
+func planSwitch(v utils.GoVersion) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		failf("Failed to get home directory: %v", err)
+		return
+	}
+	govmDir := filepath.Join(homeDir, ".govm")
+	shimDir := filepath.Join(govmDir, "shim")
+	term.Printf("📋 Switching to Go %s would:\n", v.Version)
+	if _, err := os.Stat(shimDir); err != nil {
+		term.Printf("  create %s\n", shimDir)
+	}
+	format := utils.DefaultShimFormat(runtime.GOOS)
+	pinFiles := utils.PinFiles()
+	binDir := filepath.Join(v.Path, "bin")
+	entries, _ := os.ReadDir(binDir)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		binName := strings.TrimSuffix(entry.Name(), ".exe")
+		shimPath := filepath.Join(shimDir, binName) + format.Extension()
+		script := utils.ShimScript(filepath.Join(binDir, binName), pinFiles, format)
+		if existing, err := os.ReadFile(shimPath); err == nil && bytes.Equal(existing, script) {
+			continue
+		}
+		term.Printf("  write shim %s\n", shimPath)
+	}
+	term.Printf("  update %s\n", filepath.Join(govmDir, "shim_hashes.json"))
+	active := activeVersion()
+	if active != "" && active != v.Version {
+		term.Printf("  record Go %s in %s\n", active, filepath.Join(govmDir, "previous_version"))
+	}
+	term.Printf("  write Go %s to %s\n", v.Version, filepath.Join(govmDir, "active_version"))
+	term.Printf("  append the switch to %s\n", filepath.Join(govmDir, "history"))
+}
+
+// planDelete prints the directories deleting versions would remove.
+func planDelete(versions []utils.GoVersion) {
+	term.Println("📋 Deleting would remove:")
+	for _, v := range versions {
+		term.Printf("  %s (Go %s)\n", v.Path, v.Version)
+	}
+}
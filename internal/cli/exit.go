@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"errors"
+	"net"
+	"os"
+	"sync"
+
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// Exit codes govm returns so scripts can branch on why a command failed.
+const (
+	ExitOK = 0
+	// ExitFailure is any failure without a more specific code.
+	ExitFailure = 1
+	// ExitUsage means the command line was malformed.
+	ExitUsage = 2
+	// ExitNotFound means no release on go.dev matches the requested version.
+	ExitNotFound = 3
+	// ExitNotInstalled means the requested version is not installed.
+	ExitNotInstalled = 4
+	// ExitNetwork means go.dev or a mirror could not be reached, or would
+	// have been needed while working offline.
+	ExitNetwork = 5
+	// ExitPolicy means the version is forbidden by the configured policy.
+	ExitPolicy = 6
+)
+
+// exitCode is the code main exits with once the command returns. The first
+// failure wins, so a later generic error cannot mask a specific one.
+var (
+	exitCode   = ExitOK
+	exitCodeMu sync.Mutex
+)
+
+// ExitCode returns the exit code for the command that just ran.
+func ExitCode() int {
+	exitCodeMu.Lock()
+	defer exitCodeMu.Unlock()
+	return exitCode
+}
+
+// setExitCode records code unless an earlier failure already set one.
+func setExitCode(code int) {
+	exitCodeMu.Lock()
+	defer exitCodeMu.Unlock()
+	if exitCode == ExitOK {
+		exitCode = code
+	}
+}
+
+// exitError attaches an exit code to an error.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e exitError) Error() string { return e.err.Error() }
+
+func (e exitError) Unwrap() error { return e.err }
+
+// withExitCode tags err so that reporting it exits with code.
+func withExitCode(code int, err error) error {
+	return exitError{code: code, err: err}
+}
+
+// exitCodeFor picks the exit code describing err.
+func exitCodeFor(err error) int {
+	var tagged exitError
+	if errors.As(err, &tagged) {
+		return tagged.code
+	}
+	var netErr net.Error
+	var requestErr *utils.NetworkError
+	if errors.As(err, &netErr) || errors.As(err, &requestErr) || errors.Is(err, utils.ErrOffline) {
+		return ExitNetwork
+	}
+	return ExitFailure
+}
+
+// fail reports err on stderr and records its exit code.
+func fail(err error) {
+	term.Fprintf(os.Stderr, "❌ %s\n", err)
+	setExitCode(exitCodeFor(err))
+}
+
+// failf reports a formatted error on stderr. The exit code comes from the
+// first error among args, or is ExitFailure.
+func failf(format string, args ...any) {
+	term.Fprintf(os.Stderr, "❌ "+format+"\n", args...)
+	code := ExitFailure
+	for _, arg := range args {
+		if err, ok := arg.(error); ok {
+			code = exitCodeFor(err)
+			break
+		}
+	}
+	setExitCode(code)
+}
+
+// failCodef reports a formatted error on stderr with an explicit exit code.
+func failCodef(code int, format string, args ...any) {
+	term.Fprintf(os.Stderr, "❌ "+format+"\n", args...)
+	setExitCode(code)
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// InstallFromGoMod installs the Go version the nearest go.mod asks for: its
+// toolchain directive, or else the newest release of its go directive's
+// line, unless a matching version is already installed. With pin it also
+// writes the exact version to .go-version, and with use it switches to it.
+// With dryRun it only prints what it would do.
+func InstallFromGoMod(use, pin, pre, dryRun bool) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		failf("Failed to get current directory: %v", err)
+		return
+	}
+	version, source, err := utils.DetectGoModVersion(cwd)
+	if err != nil {
+		failCodef(ExitNotFound, "No go.mod found in %s or its parents", cwd)
+		return
+	}
+	term.Infof("📄 %s requires Go %s\n", source, version)
+	// An installed release of the required line already satisfies go.mod,
+	// so only go to go.dev when there is none
+	matchedVersion, err := findInstalledVersion(version)
+	if err != nil {
+		if matchedVersion, err = resolveInstallTarget(version, pre); err != nil {
+			fail(err)
+			return
+		}
+	}
+
+	if dryRun {
+		if matchedVersion.Installed {
+			term.Printf("✅ Go %s is already installed\n", matchedVersion.Version)
+		} else {
+			planInstall(matchedVersion)
+			if homeDir, err := os.UserHomeDir(); err == nil {
+				matchedVersion.Path = filepath.Join(homeDir, ".govm", "versions", "go"+matchedVersion.Version)
+			}
+		}
+		if pin {
+			UseLocal(matchedVersion.Version, true)
+		}
+		if use {
+			planSwitch(matchedVersion)
+		}
+		return
+	}
+
+	if matchedVersion.Installed {
+		term.Printf("✅ Go %s is already installed\n", matchedVersion.Version)
+	} else if err := installResolved(matchedVersion); err != nil {
+		return
+	}
+	if pin {
+		Pin(matchedVersion.Version, false)
+	}
+	if use {
+		UseVersion(matchedVersion.Version, false)
+	}
+}
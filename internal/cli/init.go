@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/melkeydev/govm/internal/config"
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// Init prints shell code meant to be eval'd from the user's rc file: it puts
+// the shim directory first in PATH, loads completions and, with autoSwitch,
+// installs a hook that checks the project's pinned version on cd.
+func Init(shell string, autoSwitch bool, commands []CompletionCommand) {
+	switch shell {
+	case "bash", "zsh":
+		term.Println(`case ":$PATH:" in`)
+		term.Println(`    ":$HOME/.govm/shim:"*) ;;`)
+		term.Println(`    *) export PATH="$HOME/.govm/shim:$PATH" ;;`)
+		term.Println(`esac`)
+		if shell == "zsh" {
+			term.Print("autoload -U +X bashcompinit && bashcompinit\n")
+		}
+		term.Print(bashCompletion(commands))
+	case "fish":
+		term.Println(`fish_add_path --move "$HOME/.govm/shim"`)
+		term.Print(fishCompletion(commands))
+	case "powershell":
+		term.Println(`$govmShim = Join-Path $HOME ".govm\shim"`)
+		term.Println(`if (($env:PATH -split [IO.Path]::PathSeparator)[0] -ne $govmShim) { $env:PATH = $govmShim + [IO.Path]::PathSeparator + $env:PATH }`)
+		term.Print(powershellCompletion(commands))
+	default:
+		failCodef(ExitUsage, "Unsupported shell %q (expected bash, zsh, fish or powershell)", shell)
+		os.Exit(ExitCode())
+	}
+	if autoSwitch {
+		Hook(shell)
+	}
+}
+
+// Hook prints only the cd hook of 'govm init --auto-switch', for users who
+// manage PATH and completions themselves. The hook runs 'govm auto-switch'
+// whenever the directory changes: zsh through chpwd, bash through
+// PROMPT_COMMAND, fish on changes of PWD and PowerShell from the prompt.
+func Hook(shell string) {
+	switch shell {
+	case "bash", "zsh":
+		term.Println("_govm_auto_switch() { command govm --quiet auto-switch; }")
+		if shell == "zsh" {
+			term.Println("autoload -U add-zsh-hook")
+			term.Println("add-zsh-hook chpwd _govm_auto_switch")
+		} else {
+			term.Println(`_govm_last_dir=""`)
+			term.Println(`_govm_prompt_hook() { [ "$PWD" = "$_govm_last_dir" ] || { _govm_last_dir="$PWD"; _govm_auto_switch; }; }`)
+			term.Println(`case ";$PROMPT_COMMAND;" in`)
+			term.Println(`    *";_govm_prompt_hook;"*) ;;`)
+			term.Println(`    *) PROMPT_COMMAND="_govm_prompt_hook${PROMPT_COMMAND:+;$PROMPT_COMMAND}" ;;`)
+			term.Println(`esac`)
+		}
+		term.Println("_govm_auto_switch")
+	case "fish":
+		term.Println("function _govm_auto_switch --on-variable PWD")
+		term.Println("    command govm --quiet auto-switch")
+		term.Println("end")
+		term.Println("_govm_auto_switch")
+	case "powershell":
+		term.Println("$global:GovmLastDir = $null")
+		term.Println("if (-not $global:GovmOriginalPrompt) { $global:GovmOriginalPrompt = $function:prompt }")
+		term.Println("function global:prompt {")
+		term.Println("    if ($PWD.Path -ne $global:GovmLastDir) { $global:GovmLastDir = $PWD.Path; govm --quiet auto-switch }")
+		term.Println("    & $global:GovmOriginalPrompt")
+		term.Println("}")
+	default:
+		failCodef(ExitUsage, "Unsupported shell %q (expected bash, zsh, fish or powershell)", shell)
+		os.Exit(ExitCode())
+	}
+}
+
+// AutoSwitch checks the version pinned for the current directory. It is run
+// by the cd hook from Hook. The shims already run the pinned version, so it
+// never switches the global one: it prints nothing while the pin is
+// installed, warns about one that is not, or with
+// project.auto_install_on_cd starts installing it in the background.
+func AutoSwitch() {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	version, source, err := utils.DetectProjectVersion(cwd)
+	if err != nil {
+		return
+	}
+	utils.RecordProject(source)
+	if _, err := findInstalledVersion(version); err == nil {
+		return
+	}
+	if cfg, err := config.Load(); err == nil && cfg.Project.AutoInstallOnCD {
+		startBackgroundInstall(version, source)
+		return
+	}
+	term.Fprintf(os.Stderr, "govm: %s pins Go %s, which is not installed (govm install %s, or set project.auto_install_on_cd)\n", source, version, version)
+}
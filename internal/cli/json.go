@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/melkeydev/govm/internal/term"
+)
+
+// The types below are the --json output of the CLI. Scripts and editor
+// plugins depend on them, so they are documented in the README and fields
+// are only ever added. list and status reuse the types served by
+// 'govm serve'.
+
+// RemoteVersion is one entry of 'govm ls-remote --json'.
+type RemoteVersion struct {
+	Version     string `json:"version"`
+	Stable      bool   `json:"stable"`
+	Installed   bool   `json:"installed"`
+	Active      bool   `json:"active"`
+	Installable bool   `json:"installable"`
+	// Reason explains why an uninstallable version cannot be installed.
+	Reason string `json:"reason,omitempty"`
+}
+
+// InstallResult is one entry of 'govm install --json', one per argument.
+type InstallResult struct {
+	Requested string `json:"requested"`
+	Version   string `json:"version,omitempty"`
+	GOROOT    string `json:"goroot,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// UseResult is the output of 'govm use --json'.
+type UseResult struct {
+	Version    string `json:"version"`
+	GOROOT     string `json:"goroot"`
+	ShimInPath bool   `json:"shim_in_path"`
+}
+
+// CurrentResult is the output of 'govm current --json'.
+type CurrentResult struct {
+	Version string `json:"version"`
+	GOROOT  string `json:"goroot"`
+	// Layer is the step of the resolution order that selected Version:
+	// "env", "shell", "local" or "global".
+	Layer string `json:"layer"`
+	// Source is the variable or file that layer read.
+	Source string `json:"source"`
+	// MissingPin and MissingPinSource are set when an env, shell or local
+	// layer names a version that is not installed, so the global version
+	// runs instead.
+	MissingPin       string `json:"missing_pin,omitempty"`
+	MissingPinSource string `json:"missing_pin_source,omitempty"`
+}
+
+// DoctorReport is the output of 'govm doctor --json'.
+type DoctorReport struct {
+	OK       bool     `json:"ok"`
+	Problems []string `json:"problems"`
+}
+
+// jsonOutput is set by EnableJSON for commands run with --json.
+var jsonOutput bool
+
+// EnableJSON switches the current command to machine-readable output: its
+// result is printed as JSON on stdout and all other text moves to stderr.
+func EnableJSON() {
+	jsonOutput = true
+	term.Stdout = os.Stderr
+}
+
+func printJSON(v any) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(v)
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/events"
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+func isVersionKeyword(version string) bool {
+	return slices.Contains(utils.VersionKeywords, version)
+}
+
+// expandVersionKeyword replaces a version keyword with the release it names
+// and returns any other version unchanged.
+func expandVersionKeyword(version string) (string, error) {
+	if !isVersionKeyword(version) {
+		return version, nil
+	}
+	resolved, err := resolveVersionKeyword(version)
+	if err != nil {
+		return "", err
+	}
+	term.Infof("🔍 %s is Go %s\n", version, resolved)
+	return resolved, nil
+}
+
+// resolveVersionKeyword looks keyword up in the go.dev release index and
+// returns the exact version it names.
+func resolveVersionKeyword(keyword string) (string, error) {
+	msg := utils.FetchGoVersions()
+	versions, ok := msg.(events.VersionsMsg)
+	if !ok {
+		if errMsg, isErr := msg.(events.ErrMsg); isErr {
+			return "", withExitCode(ExitNetwork, fmt.Errorf("failed to fetch versions: %v", errMsg))
+		}
+		return "", withExitCode(ExitNetwork, fmt.Errorf("failed to fetch versions"))
+	}
+	var stable []string
+	for _, v := range versions {
+		if v.Stable && !utils.IsPreRelease(v.Version) {
+			stable = append(stable, v.Version)
+		}
+	}
+	sort.Slice(stable, func(i, j int) bool { return compareVersions(stable[i], stable[j]) > 0 })
+	if len(stable) == 0 {
+		return "", withExitCode(ExitNotFound, fmt.Errorf("no stable Go release found"))
+	}
+
+	switch keyword {
+	case "latest":
+		return stable[0], nil
+	case "oldstable":
+		latestLine := minorVersion(stable[0])
+		for _, v := range stable {
+			if minorVersion(v) != latestLine {
+				return v, nil
+			}
+		}
+		return "", withExitCode(ExitNotFound, fmt.Errorf("no release older than the %s line found", latestLine))
+	default:
+		active := activeVersion()
+		if active == "" {
+			return "", withExitCode(ExitNotInstalled, fmt.Errorf("'stable' follows the active version's minor line, but no version is active"))
+		}
+		line := minorVersion(active)
+		for _, v := range stable {
+			if minorVersion(v) == line {
+				return v, nil
+			}
+		}
+		return "", withExitCode(ExitNotFound, fmt.Errorf("no stable release of Go %s found", line))
+	}
+}
+
+// activeVersion returns the version GoVM last switched to, or "" if none.
+func activeVersion() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	versionBytes, err := os.ReadFile(filepath.Join(homeDir, ".govm", "active_version"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(versionBytes))
+}
@@ -0,0 +1,241 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/events"
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// migrationSource describes where another version manager keeps its
+// toolchains and how it records the global version.
+type migrationSource struct {
+	// toolchains maps each version the manager has installed to its GOROOT.
+	toolchains func(homeDir string) (map[string]string, error)
+	// global returns the manager's global default version, if any.
+	global func(homeDir string) string
+	// note is printed after migrating, e.g. about pin files.
+	note string
+}
+
+var migrationSources = map[string]migrationSource{
+	"goenv": {
+		toolchains: func(homeDir string) (map[string]string, error) {
+			return scanVersionDirs(filepath.Join(goenvRoot(homeDir), "versions"), "")
+		},
+		global: func(homeDir string) string {
+			return readVersionFile(filepath.Join(goenvRoot(homeDir), "version"))
+		},
+		note: "goenv's .go-version files are read by govm as they are; no changes needed.",
+	},
+	"gvm": {
+		toolchains: func(homeDir string) (map[string]string, error) {
+			return scanVersionDirs(filepath.Join(gvmRoot(homeDir), "gos"), "go")
+		},
+		global: func(homeDir string) string {
+			// The default environment sets GOROOT="$GVM_ROOT/gos/go1.22.5"
+			content, err := os.ReadFile(filepath.Join(gvmRoot(homeDir), "environments", "default"))
+			if err != nil {
+				return ""
+			}
+			for _, line := range strings.Split(string(content), "\n") {
+				if i := strings.Index(line, "/gos/go"); i >= 0 && strings.Contains(line, "GOROOT=") {
+					return strings.Trim(line[i+len("/gos/go"):], `"' `)
+				}
+			}
+			return ""
+		},
+	},
+	"g": {
+		toolchains: func(homeDir string) (map[string]string, error) {
+			return scanVersionDirs(filepath.Join(gRoot(homeDir), "versions"), "")
+		},
+		global: func(homeDir string) string {
+			// The active version is a symlink from $G_HOME/go into versions/
+			target, err := os.Readlink(filepath.Join(gRoot(homeDir), "go"))
+			if err != nil {
+				return ""
+			}
+			return strings.TrimPrefix(filepath.Base(target), "go")
+		},
+	},
+	"asdf": {
+		toolchains: func(homeDir string) (map[string]string, error) {
+			// asdf-golang unpacks each release into installs/golang/<version>/go
+			dir := filepath.Join(asdfRoot(homeDir), "installs", "golang")
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				return nil, err
+			}
+			toolchains := map[string]string{}
+			for _, entry := range entries {
+				goRoot := filepath.Join(dir, entry.Name(), "go")
+				if utils.IsCompleteInstall(goRoot) {
+					toolchains[entry.Name()] = goRoot
+				}
+			}
+			return toolchains, nil
+		},
+		global: func(homeDir string) string {
+			return toolVersionsPin(homeDir)
+		},
+		note: "asdf's .tool-versions files are read by govm and its shims as they are; no changes needed.",
+	},
+}
+
+func gvmRoot(homeDir string) string {
+	if root := os.Getenv("GVM_ROOT"); root != "" {
+		return root
+	}
+	return filepath.Join(homeDir, ".gvm")
+}
+
+func gRoot(homeDir string) string {
+	if root := os.Getenv("G_HOME"); root != "" {
+		return root
+	}
+	return filepath.Join(homeDir, ".g")
+}
+
+func asdfRoot(homeDir string) string {
+	if root := os.Getenv("ASDF_DATA_DIR"); root != "" {
+		return root
+	}
+	return filepath.Join(homeDir, ".asdf")
+}
+
+// toolVersionsPin returns the golang version in dir's .tool-versions file.
+func toolVersionsPin(dir string) string {
+	version, _ := utils.ToolVersionsVersion(filepath.Join(dir, ".tool-versions"))
+	return version
+}
+
+func goenvRoot(homeDir string) string {
+	if root := os.Getenv("GOENV_ROOT"); root != "" {
+		return root
+	}
+	return filepath.Join(homeDir, ".goenv")
+}
+
+// scanVersionDirs returns the complete Go installs directly inside dir whose
+// names start with prefix, keyed by version.
+func scanVersionDirs(dir, prefix string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	toolchains := map[string]string{}
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if !strings.HasPrefix(entry.Name(), prefix) || !utils.IsCompleteInstall(path) {
+			continue
+		}
+		version := strings.TrimPrefix(strings.TrimPrefix(entry.Name(), prefix), "go")
+		toolchains[version] = path
+	}
+	return toolchains, nil
+}
+
+// readVersionFile returns the first word of a version file, without a "go"
+// prefix.
+func readVersionFile(path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 {
+		return ""
+	}
+	return readVersionField(fields[0])
+}
+
+// readVersionField normalizes a version as written by another manager;
+// "system" means no managed version.
+func readVersionField(field string) string {
+	if field == "system" {
+		return ""
+	}
+	return strings.TrimPrefix(field, "go")
+}
+
+// Migrate adopts the toolchains of another version manager: each one is
+// copied into the versions directory, or symlinked with link, and the
+// manager's global version becomes the active one.
+func Migrate(tool string, link bool) {
+	source, ok := migrationSources[tool]
+	if !ok {
+		names := make([]string, 0, len(migrationSources))
+		for name := range migrationSources {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		failCodef(ExitUsage, "Unknown version manager %q (expected %s)", tool, strings.Join(names, ", "))
+		return
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		failf("Failed to get home directory: %v", err)
+		return
+	}
+	toolchains, err := source.toolchains(homeDir)
+	if err != nil || len(toolchains) == 0 {
+		term.Printf("✨ No %s toolchains found\n", tool)
+		return
+	}
+	goVersionsDir := filepath.Join(homeDir, ".govm", "versions")
+	if err := os.MkdirAll(goVersionsDir, 0755); err != nil {
+		fail(err)
+		return
+	}
+	versions := make([]string, 0, len(toolchains))
+	for version := range toolchains {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return compareVersions(versions[i], versions[j]) < 0 })
+	adopted := 0
+	for _, version := range versions {
+		src := toolchains[version]
+		dest := filepath.Join(goVersionsDir, "go"+version)
+		if _, err := os.Lstat(dest); err == nil {
+			term.Printf("  ➖ Go %s is already installed in govm\n", version)
+			continue
+		}
+		if link {
+			err = os.Symlink(src, dest)
+		} else {
+			err = utils.CopyDir(src, dest)
+		}
+		if err != nil {
+			os.RemoveAll(dest)
+			term.Printf("  ❌ Go %s: %v\n", version, err)
+			setExitCode(ExitFailure)
+			continue
+		}
+		verb := "Copied"
+		if link {
+			verb = "Linked"
+		}
+		term.Printf("  ✅ %s Go %s from %s\n", verb, version, src)
+		adopted++
+	}
+	term.Printf("📋 Adopted %d of %d %s toolchain(s)\n", adopted, len(versions), tool)
+
+	if global := source.global(homeDir); global != "" {
+		if installed, err := findInstalledVersion(global); err == nil {
+			if _, isErr := utils.SwitchVersion(installed)().(events.ErrMsg); !isErr {
+				term.Printf("🔄 Activated Go %s, the global version in %s\n", installed.Version, tool)
+			}
+		}
+	}
+	if source.note != "" {
+		term.Printf("ℹ️  %s\n", source.note)
+	}
+	if link {
+		term.Printf("⚠️  Linked toolchains still live in %s's directories; keep them until you reinstall with govm\n", tool)
+	}
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/events"
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// MiseListAll prints every stable release for this platform, oldest first
+// and separated by spaces, the format mise and asdf expect from a plugin's
+// list-all.
+func MiseListAll() {
+	msg := utils.FetchRemoteVersions()
+	versions, ok := msg.(events.RemoteVersionsMsg)
+	if !ok {
+		if errMsg, isErr := msg.(events.ErrMsg); isErr {
+			failCodef(ExitNetwork, "Failed to fetch versions: %v", errMsg)
+		} else {
+			failCodef(ExitNetwork, "Failed to fetch versions")
+		}
+		return
+	}
+	var stable []string
+	for _, v := range versions {
+		if v.Stable && v.Unsupported == "" {
+			stable = append(stable, v.Version)
+		}
+	}
+	sort.Slice(stable, func(i, j int) bool { return compareVersions(stable[i], stable[j]) < 0 })
+	term.Println(strings.Join(stable, " "))
+}
+
+// MisePath prints the GOROOT of an installed version for mise, e.g. for
+// 'mise use go@path:$(govm mise path 1.22)'. With install, or with
+// project.auto_install, a missing version is installed first. Progress goes
+// to stderr so that stdout carries only the path.
+func MisePath(version string, install bool) {
+	term.Stdout = os.Stderr
+	version = strings.TrimPrefix(utils.ResolveAlias(version), "go")
+	matchedVersion, err := findInstalledVersion(version)
+	if err != nil && exitCodeFor(err) == ExitNotInstalled && (install || autoInstall()) {
+		if _, err := installPinned(version, "mise"); err != nil {
+			return
+		}
+		matchedVersion, err = findInstalledVersion(version)
+	}
+	if err != nil {
+		fail(err)
+		return
+	}
+	term.Fprintln(os.Stdout, matchedVersion.Path)
+}
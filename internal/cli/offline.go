@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"os"
+	"time"
+
+	"github.com/melkeydev/govm/internal/clock"
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// ReportOffline tells the user on stderr that the network could not be
+// reached and govm carries on offline, with the release list cached at
+// cachedAt when one is in use. It is installed as
+// utils.OnNetworkUnreachable for CLI commands.
+func ReportOffline(cachedAt time.Time) {
+	if term.Quiet {
+		return
+	}
+	if cachedAt.IsZero() {
+		term.Fprintf(os.Stderr, "📴 Network unreachable; working offline\n")
+		return
+	}
+	term.Fprintf(os.Stderr, "📴 Network unreachable; working offline with the release list cached %s\n", utils.RelativeTime(cachedAt, clock.Now()))
+}
+
+// ReportStaleIndex tells the user on stderr that the release list could not
+// be refreshed because of err and the copy cached at cachedAt is used. It is
+// installed as utils.OnStaleIndex for CLI commands.
+func ReportStaleIndex(cachedAt time.Time, err error) {
+	term.Fprintf(os.Stderr, "⚠️  Could not refresh the release list (%v); using the copy cached %s, which may be stale\n", err, utils.RelativeTime(cachedAt, clock.Now()))
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/melkeydev/govm/internal/clock"
+	"github.com/melkeydev/govm/internal/events"
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// installConcurrently installs versions with at most jobs downloading or
+// extracting at once. One status line tracks all of them, and a line is
+// printed as each finishes. It returns each install's error by index.
+func installConcurrently(versions []utils.GoVersion, jobs int) []error {
+	errs := make([]error, len(versions))
+	jobs = max(1, min(jobs, len(versions)))
+	term.Infof("📥 Installing %d versions, %d at a time...\n", len(versions), jobs)
+
+	var mu sync.Mutex
+	status := make([]string, len(versions))
+	for i := range status {
+		status[i] = "queued"
+	}
+	setStatus := func(i int, s string) {
+		mu.Lock()
+		status[i] = s
+		mu.Unlock()
+	}
+
+	queue := make(chan int)
+	done := make(chan int)
+	for range jobs {
+		go func() {
+			for i := range queue {
+				v := versions[i]
+				setStatus(i, "starting")
+				msg := utils.DownloadAndInstallWithProgress(v, func(p events.DownloadProgressMsg) {
+					if p.Downloaded == p.Total {
+						setStatus(i, "extracting")
+					} else if percent := p.Percent(); percent >= 0 {
+						setStatus(i, fmt.Sprintf("%.0f%%", percent))
+					} else {
+						setStatus(i, events.FormatBytes(p.Downloaded))
+					}
+				})()
+				if errMsg, ok := msg.(events.ErrMsg); ok {
+					errs[i] = errMsg
+				}
+				done <- i
+			}
+		}()
+	}
+	go func() {
+		for i := range versions {
+			queue <- i
+		}
+		close(queue)
+	}()
+
+	spinChars := term.SpinnerFrames()
+	spinIdx := 0
+	ticker := clock.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for remaining := len(versions); remaining > 0; {
+		select {
+		case i := <-done:
+			remaining--
+			setStatus(i, "")
+			if errs[i] != nil {
+				term.Infof("\r\033[K")
+				failf("Go %s failed: %v", versions[i].Version, errs[i])
+			} else {
+				term.Printf("\r\033[K✅ Go %s installed\n", versions[i].Version)
+			}
+		case <-ticker.C():
+			mu.Lock()
+			var parts []string
+			for i, s := range status {
+				if s != "" {
+					parts = append(parts, versions[i].Version+" "+s)
+				}
+			}
+			mu.Unlock()
+			term.Infof("\r\033[K%s %s", spinChars[spinIdx], strings.Join(parts, " · "))
+			spinIdx = (spinIdx + 1) % len(spinChars)
+		}
+	}
+	return errs
+}
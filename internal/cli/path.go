@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// Path reports where the GoVM shim directory sits in PATH and whether
+// another Go installation comes before it. With fix, it moves the GoVM line
+// to the end of the shell config file, so its PATH change is applied last
+// and the shim ends up in front, after previewing the change and keeping a
+// backup.
+func Path(fix bool) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		failf("Failed to get home directory: %v", err)
+		return
+	}
+	shimDir := filepath.Join(homeDir, ".govm", "shim")
+	shadowing := ""
+	inPath := false
+	for _, entry := range filepath.SplitList(os.Getenv("PATH")) {
+		if entry == shimDir {
+			inPath = true
+			break
+		}
+		if shadowing == "" && hasGoBinary(entry) {
+			shadowing = entry
+		}
+	}
+	switch {
+	case !inPath:
+		term.Println("⚠️  The GoVM shim directory is not in PATH")
+	case shadowing != "":
+		term.Printf("⚠️  %s comes before the GoVM shim directory in PATH, so its go wins\n", shadowing)
+	default:
+		term.Println("✅ The GoVM shim directory comes before any other Go in PATH")
+		return
+	}
+	if !fix {
+		term.Infoln("👉 Run 'govm path --fix' to move GoVM to the front of PATH in your shell config")
+		return
+	}
+	if runtime.GOOS == "windows" {
+		failf("path --fix is not supported on Windows")
+		term.Println(utils.GetShimPathInstructions())
+		return
+	}
+	configFile, line := shellConfigFile(homeDir)
+	rc, err := readRCFile(configFile)
+	if err != nil {
+		failf("Failed to read %s: %v", configFile, err)
+		return
+	}
+	diff, manual := rc.removeGovmPath()
+	rc.append(govmMarker, line)
+
+	term.Printf("\n📝 Proposed changes to %s (added lines go at the end):\n", configFile)
+	for _, text := range diff {
+		term.Printf("  %s\n", text)
+	}
+	term.Printf("  + %s\n  + %s\n", govmMarker, line)
+	for _, text := range manual {
+		term.Printf("⚠️  This line also mentions GoVM and is left as it is: %s\n", strings.TrimSpace(text))
+	}
+	if !confirm("Apply these changes? (y/N): ") {
+		term.Println("🛑 Operation canceled.")
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(configFile), 0755); err != nil {
+		fail(err)
+		return
+	}
+	backup, err := rc.save()
+	if err != nil {
+		failf("Failed to update %s: %v", configFile, err)
+		return
+	}
+	if backup != "" {
+		term.Printf("✅ Updated %s (backup: %s)\n", configFile, backup)
+	} else {
+		term.Printf("✅ Created %s\n", configFile)
+	}
+	term.Infoln("🚀 Open a new terminal for the change to take effect")
+}
+
+// hasGoBinary reports whether dir contains an executable go.
+func hasGoBinary(dir string) bool {
+	path, err := exec.LookPath(filepath.Join(dir, "go"))
+	return err == nil && path != ""
+}
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/config"
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// goVersionFile is the per-project pin file read by GoVM.
+const goVersionFile = ".go-version"
+
+// Pin writes the given version, or the active one when fromActive is set, to
+// a .go-version file in the current directory.
+func Pin(version string, fromActive bool) {
+	if fromActive {
+		if version = activeVersion(); version == "" {
+			failf("No active version. Run 'govm use <version>' first.")
+			return
+		}
+	}
+	version = strings.TrimPrefix(utils.ResolveAlias(version), "go")
+	if version == "" {
+		failf("No version given")
+		return
+	}
+	if err := os.WriteFile(goVersionFile, []byte(version+"\n"), 0644); err != nil {
+		failf("Failed to write %s: %v", goVersionFile, err)
+		return
+	}
+	term.Printf("📌 Pinned this directory to Go %s (%s)\n", version, goVersionFile)
+	if path, err := filepath.Abs(goVersionFile); err == nil {
+		utils.RecordProject(path)
+	}
+	if _, err := findInstalledVersion(version); err != nil {
+		term.Infof("👉 Go %s is not installed yet, run: govm install %s\n", version, version)
+	}
+}
+
+// UseLocal is 'govm use --local': it pins the current directory to version
+// by writing .go-version, leaving the machine-wide version alone. With dryRun
+// it only prints the file it would write.
+func UseLocal(version string, dryRun bool) {
+	if dryRun {
+		cwd, err := os.Getwd()
+		if err != nil {
+			failf("Failed to get current directory: %v", err)
+			return
+		}
+		version = strings.TrimPrefix(utils.ResolveAlias(version), "go")
+		term.Printf("📋 Would write Go %s to %s\n", version, filepath.Join(cwd, goVersionFile))
+		return
+	}
+	Pin(version, false)
+	if ExitCode() != ExitOK {
+		return
+	}
+	term.Infoln("👉 The shims use it in this directory and below; the global version is unchanged")
+}
+
+// Unpin removes the .go-version file from the current directory.
+func Unpin() {
+	if err := os.Remove(goVersionFile); err != nil {
+		if os.IsNotExist(err) {
+			term.Printf("ℹ️  No %s in this directory\n", goVersionFile)
+			return
+		}
+		failf("Failed to remove %s: %v", goVersionFile, err)
+		return
+	}
+	term.Printf("🗑️  Removed %s\n", goVersionFile)
+}
+
+// Run executes a command with the toolchain the current project pins via
+// .go-version, .tool-versions, mise.toml or go.mod (its toolchain directive,
+// else its go directive), without touching the global version. A pinned
+// version that is missing is installed first with install, or after asking
+// when run from a terminal. It returns the command's exit code.
+func Run(args []string, install bool) int {
+	cwd, _ := os.Getwd()
+	version, source, err := utils.DetectProjectVersion(cwd)
+	if err != nil {
+		failf("Cannot infer a version: %v", err)
+		return ExitCode()
+	}
+	utils.RecordProject(source)
+	if _, err := findInstalledVersion(version); err != nil {
+		install = install || autoInstall()
+		if !install && term.Interactive() {
+			install = confirm(fmt.Sprintf("📥 %s pins Go %s, which is not installed. Install it now? (y/N): ", source, version))
+		}
+		if !install {
+			failCodef(ExitNotInstalled, "%s pins Go %s, which is not installed; rerun with --install, run 'govm install %s' or set project.auto_install", source, version, version)
+			return ExitCode()
+		}
+		if _, err := installPinned(version, source); err != nil {
+			return ExitCode()
+		}
+	}
+	return execVersion(version, source, args)
+}
+
+// autoInstall reports whether the project.auto_install setting is on.
+func autoInstall() bool {
+	cfg, err := config.Load()
+	return err == nil && cfg.Project.AutoInstall
+}
+
+// isFloatingPin reports whether version names a minor line, like 1.22, that
+// floats to its newest patch rather than a single release.
+func isFloatingPin(version string) bool {
+	release, pre := utils.SplitPreRelease(version)
+	return pre == "" && strings.Count(release, ".") == 1
+}
+
+// installNewestPatch installs the newest release of a floating pin's line
+// when it is newer than every installed patch, so projects that pin 1.22
+// pick up security releases without editing the pin. Without network access
+// the newest installed patch is kept.
+func installNewestPatch(version, source string) {
+	if !isFloatingPin(version) {
+		return
+	}
+	newest, err := findMatchingVersion(version, false)
+	if err != nil {
+		term.Debugf("not checking for a newer Go %s patch: %v", version, err)
+		return
+	}
+	if newest.Installed {
+		return
+	}
+	term.Infof("📥 %s floats to Go %s, the newest %s release\n", source, newest.Version, version)
+	// A failed install leaves the older installed patches to satisfy the pin
+	installResolved(newest)
+}
+
+// installPinned installs the release version resolves to, on behalf of
+// source, which needs it but found it missing.
+func installPinned(version, source string) (utils.GoVersion, error) {
+	term.Infof("📥 %s needs Go %s, which is not installed; installing it\n", source, version)
+	matchedVersion, err := resolveInstallTarget(version, false)
+	if err != nil {
+		fail(err)
+		return matchedVersion, err
+	}
+	return matchedVersion, installResolved(matchedVersion)
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/config"
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// checkPolicy checks version against the organization policy configured in
+// config.json. Violations are printed as warnings, and returned as an error
+// only when the policy is enforced. A policy that cannot be fetched never
+// blocks anything.
+func checkPolicy(version string) error {
+	cfg, err := config.Load()
+	if err != nil || cfg.Policy.URL == "" {
+		return nil
+	}
+	policy, err := utils.FetchPolicy(cfg.Policy.URL)
+	if err != nil {
+		term.Printf("⚠️  %v\n", err)
+		return nil
+	}
+	violation := policyViolation(policy, version)
+	if violation == "" {
+		return nil
+	}
+	if cfg.Policy.Enforce {
+		return withExitCode(ExitPolicy, fmt.Errorf("Go %s is not allowed by your organization's policy: %s", version, violation))
+	}
+	term.Printf("⚠️  Go %s violates your organization's policy: %s\n", version, violation)
+	return nil
+}
+
+// policyViolation returns why version breaks policy, or an empty string.
+func policyViolation(policy utils.Policy, version string) string {
+	matches := func(pattern string) bool {
+		pattern = strings.TrimPrefix(pattern, "go")
+		return version == pattern || strings.HasPrefix(version, pattern+".")
+	}
+	for _, blocked := range policy.Blocked {
+		if matches(blocked.Version) {
+			if blocked.Reason == "" {
+				return "this version is blocked"
+			}
+			return "blocked: " + blocked.Reason
+		}
+	}
+	if policy.Minimum != "" && compareVersions(version, strings.TrimPrefix(policy.Minimum, "go")) < 0 {
+		return "the minimum allowed version is " + policy.Minimum
+	}
+	if len(policy.Allowed) == 0 {
+		return ""
+	}
+	for _, allowed := range policy.Allowed {
+		if matches(allowed) {
+			return ""
+		}
+	}
+	return "allowed versions are " + strings.Join(policy.Allowed, ", ")
+}
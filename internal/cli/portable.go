@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// Export packages an installed version into a portable archive that
+// 'govm import' can install on a machine without internet access.
+func Export(version, dest string) {
+	version = strings.TrimPrefix(utils.ResolveAlias(version), "go")
+	installed, err := findInstalledVersion(version)
+	if err != nil {
+		fail(err)
+		return
+	}
+	if dest == "" {
+		dest = fmt.Sprintf("go%s.%s-%s.govm.tar.gz", installed.Version, runtime.GOOS, runtime.GOARCH)
+	}
+	term.Printf("📦 Exporting Go %s to %s...\n", installed.Version, dest)
+	if err := utils.ExportVersion(installed, dest); err != nil {
+		failf("Export failed: %v", err)
+		return
+	}
+	term.Printf("✅ Exported Go %s\n", installed.Version)
+	term.Infof("👉 On the other machine, run: govm import %s\n", dest)
+}
+
+// Import installs a version from an archive created by Export.
+func Import(src string) {
+	term.Printf("📦 Importing %s...\n", src)
+	imported, err := utils.ImportVersion(src)
+	if err != nil {
+		failf("Import failed: %v", err)
+		return
+	}
+	term.Printf("✅ Installed Go %s to %s\n", imported.Version, imported.Path)
+	term.Infof("👉 To activate this version, run: govm use %s\n", imported.Version)
+}
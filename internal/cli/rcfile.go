@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/clock"
+)
+
+// govmMarker is the comment configureShell writes above the PATH line it
+// adds to a shell config file.
+const govmMarker = "# Added by govm"
+
+// shimElement matches the shim directory as a PATH element, however the
+// home directory is spelled.
+var shimElement = regexp.MustCompile(`(?:\$HOME|\$\{HOME\}|~|/[^\s:"']*)/\.govm/shim/?`)
+
+// noopPathExport matches what is left of a PATH export once the shim
+// directory is taken out of it.
+var noopPathExport = regexp.MustCompile(`^(?:export\s+)?PATH=("\$PATH"|\$PATH|"\$\{PATH\}"|\$\{PATH\})$`)
+
+// rcFile is a shell config file edited line by line. Edits only change
+// lines; save writes the result after keeping a backup of the original.
+type rcFile struct {
+	path    string
+	content []byte
+	lines   []string
+}
+
+// readRCFile reads the shell config file at path. A file that does not
+// exist reads as empty and is created by save.
+func readRCFile(path string) (*rcFile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	f := &rcFile{path: path, content: content}
+	if len(content) > 0 {
+		f.lines = strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	}
+	return f, nil
+}
+
+// removeGovmPath takes the shim directory out of the file's PATH setup. A
+// line that only adds the shim directory is deleted together with the
+// govmMarker comment above it; a line that adds other directories too
+// keeps them. It returns the change as "- old" and "+ new" lines for a
+// preview, and the lines that mention the shim directory in a way it
+// cannot safely edit, which are left for the user.
+func (f *rcFile) removeGovmPath() (diff, manual []string) {
+	var kept []string
+	for _, line := range f.lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") || !strings.Contains(line, ".govm/shim") {
+			kept = append(kept, line)
+			continue
+		}
+		edited, ok := withoutShimElement(line)
+		if !ok {
+			manual = append(manual, line)
+			kept = append(kept, line)
+			continue
+		}
+		diff = append(diff, "- "+line)
+		if edited != "" {
+			diff = append(diff, "+ "+edited)
+			kept = append(kept, edited)
+			continue
+		}
+		// The whole line went, so its marker and the blank line
+		// configureShell put before it go too
+		if n := len(kept); n > 0 && strings.TrimSpace(kept[n-1]) == govmMarker {
+			diff = append(diff[:len(diff)-1], "- "+kept[n-1], "- "+line)
+			kept = kept[:n-1]
+			if n := len(kept); n > 0 && strings.TrimSpace(kept[n-1]) == "" {
+				kept = kept[:n-1]
+			}
+		}
+	}
+	f.lines = kept
+	return diff, manual
+}
+
+// withoutShimElement returns line with the shim directory taken out of the
+// PATH it sets, or "" when nothing else is left for it to do. It reports
+// false when line mentions the shim directory some other way.
+func withoutShimElement(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if fields := strings.Fields(trimmed); len(fields) > 1 && fields[0] == "fish_add_path" {
+		for _, field := range fields[1:] {
+			field = strings.Trim(field, `"'`)
+			if !strings.HasPrefix(field, "-") && shimElement.FindString(field) != field {
+				return "", false
+			}
+		}
+		return "", true
+	}
+	edited := line
+	for _, element := range shimElement.FindAllString(line, -1) {
+		if strings.Contains(edited, element+":") {
+			edited = strings.Replace(edited, element+":", "", 1)
+		} else {
+			edited = strings.Replace(edited, ":"+element, "", 1)
+		}
+	}
+	if strings.Contains(edited, ".govm/shim") {
+		return "", false
+	}
+	if noopPathExport.MatchString(strings.TrimSpace(edited)) {
+		return "", true
+	}
+	return edited, true
+}
+
+// append adds lines at the end of the file, after a blank line.
+func (f *rcFile) append(lines ...string) {
+	for len(f.lines) > 0 && strings.TrimSpace(f.lines[len(f.lines)-1]) == "" {
+		f.lines = f.lines[:len(f.lines)-1]
+	}
+	if len(f.lines) > 0 {
+		f.lines = append(f.lines, "")
+	}
+	f.lines = append(f.lines, lines...)
+}
+
+// save writes the edited file. An existing file is first copied to a
+// backup named after the time, never overwriting an earlier backup, and
+// the backup's name is returned; a new file has none.
+func (f *rcFile) save() (string, error) {
+	backup := ""
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(f.path); err == nil {
+		mode = info.Mode().Perm()
+		if backup, err = writeBackup(f.path, f.content, mode); err != nil {
+			return "", fmt.Errorf("failed to write backup: %v", err)
+		}
+	}
+	content := ""
+	if len(f.lines) > 0 {
+		content = strings.Join(f.lines, "\n") + "\n"
+	}
+	return backup, os.WriteFile(f.path, []byte(content), mode)
+}
+
+// writeBackup writes content to a new file next to path named
+// path.govm-backup-<time>, adding a counter when that name is taken.
+func writeBackup(path string, content []byte, mode os.FileMode) (string, error) {
+	base := path + ".govm-backup-" + clock.Now().Format("20060102-150405")
+	for i := 1; ; i++ {
+		name := base
+		if i > 1 {
+			name = fmt.Sprintf("%s-%d", base, i)
+		}
+		out, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		_, err = out.Write(content)
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(name)
+			return "", err
+		}
+		return name, nil
+	}
+}
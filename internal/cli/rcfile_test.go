@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/melkeydev/govm/internal/clock"
+)
+
+func TestRemoveGovmPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		manual  int
+	}{
+		{
+			name:    "configureShell block",
+			content: "alias ll='ls -l'\n\n# Added by govm\nexport PATH=\"$HOME/.govm/shim:$PATH\"\nexport EDITOR=vim\n",
+			want:    "alias ll='ls -l'\nexport EDITOR=vim\n",
+		},
+		{
+			name:    "fish",
+			content: "set -gx EDITOR vim\n\n# Added by govm\nfish_add_path --move \"$HOME/.govm/shim\"\n",
+			want:    "set -gx EDITOR vim\n",
+		},
+		{
+			name:    "shared PATH line keeps the other directories",
+			content: "export PATH=\"$HOME/bin:$HOME/.govm/shim:/opt/tools/bin:$PATH\"\n",
+			want:    "export PATH=\"$HOME/bin:/opt/tools/bin:$PATH\"\n",
+		},
+		{
+			name:    "shim last in PATH",
+			content: "PATH=$PATH:/home/me/.govm/shim\n",
+			want:    "",
+		},
+		{
+			name:    "marker comment without its line stays",
+			content: "# Added by govm\nexport GOPATH=$HOME/go\n",
+			want:    "# Added by govm\nexport GOPATH=$HOME/go\n",
+		},
+		{
+			name:    "comments are left alone",
+			content: "# export PATH=\"$HOME/.govm/shim:$PATH\"\n",
+			want:    "# export PATH=\"$HOME/.govm/shim:$PATH\"\n",
+		},
+		{
+			name:    "other uses are left for the user",
+			content: "source ~/.govm/shim/env.sh\nalias gogo=$HOME/.govm/shim/go\n",
+			want:    "source ~/.govm/shim/env.sh\nalias gogo=$HOME/.govm/shim/go\n",
+			manual:  2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".bashrc")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			rc, err := readRCFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, manual := rc.removeGovmPath()
+			if len(manual) != tt.manual {
+				t.Errorf("manual = %q, want %d lines", manual, tt.manual)
+			}
+			if _, err := rc.save(); err != nil {
+				t.Fatal(err)
+			}
+			got, _ := os.ReadFile(path)
+			if string(got) != tt.want {
+				t.Errorf("edited file = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRCFileBackups(t *testing.T) {
+	clock.Default = clock.NewFake(time.Now())
+	t.Cleanup(func() { clock.Default = clock.Real{} })
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".zshrc")
+	versions := []string{"first\n", "second\n", "third\n"}
+	if err := os.WriteFile(path, []byte(versions[0]), 0600); err != nil {
+		t.Fatal(err)
+	}
+	var backups []string
+	for _, next := range versions[1:] {
+		rc, err := readRCFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rc.lines = []string{strings.TrimSpace(next)}
+		backup, err := rc.save()
+		if err != nil {
+			t.Fatal(err)
+		}
+		backups = append(backups, backup)
+	}
+	// Both edits happen within the same second, and neither backup
+	// overwrites the other
+	if backups[0] == backups[1] {
+		t.Fatalf("both edits were backed up to %s", backups[0])
+	}
+	for i, backup := range backups {
+		got, err := os.ReadFile(backup)
+		if err != nil || string(got) != versions[i] {
+			t.Errorf("backup %s = %q, %v; want %q", backup, got, err, versions[i])
+		}
+		if info, err := os.Stat(backup); err == nil && info.Mode().Perm() != 0600 {
+			t.Errorf("backup %s has mode %v, want the file's 0600", backup, info.Mode().Perm())
+		}
+	}
+	if got, _ := os.ReadFile(path); string(got) != versions[2] {
+		t.Errorf("%s = %q, want %q", path, got, versions[2])
+	}
+}
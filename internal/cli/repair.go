@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// Repair fixes what can be fixed without downloading anything: incomplete
+// version trees are re-extracted from a cached archive, toolchain binaries
+// get their executable bit back, and the active version's shims are
+// regenerated.
+func Repair() {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		failf("Failed to get home directory: %v", err)
+		return
+	}
+	govmDir := filepath.Join(homeDir, ".govm")
+	goVersionsDir := filepath.Join(govmDir, "versions")
+	downloadDir := filepath.Join(govmDir, "downloads")
+	problems := 0
+
+	term.Println("🔧 Checking installed versions...")
+	entries, _ := os.ReadDir(goVersionsDir)
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), "go") {
+			continue
+		}
+		version := strings.TrimPrefix(entry.Name(), "go")
+		versionPath := filepath.Join(goVersionsDir, entry.Name())
+		if isIntactInstall(versionPath) {
+			if fixed := fixPermissions(versionPath); fixed > 0 {
+				term.Printf("  ✅ Go %s: restored the executable bit on %d file(s)\n", version, fixed)
+			}
+			continue
+		}
+		archives := cachedArchives(downloadDir, version)
+		if len(archives) == 0 {
+			term.Printf("  ❌ Go %s is incomplete and no archive is cached; reinstall it with: govm install %s\n", version, version)
+			problems++
+			continue
+		}
+		term.Printf("  📦 Go %s is incomplete, re-extracting %s...\n", version, archives[0])
+		goVersion := utils.GoVersion{Version: version, Filename: archives[0]}
+		if _, err := utils.ExtractArchive(goVersion, filepath.Join(downloadDir, archives[0])); err != nil {
+			term.Printf("  ❌ Failed to re-extract Go %s: %v\n", version, err)
+			problems++
+			continue
+		}
+		term.Printf("  ✅ Go %s restored\n", version)
+	}
+
+	term.Println("🔧 Regenerating shims...")
+	active := activeVersion()
+	activePath := filepath.Join(goVersionsDir, "go"+active)
+	switch {
+	case active == "":
+		term.Println("  ➖ No active version; run 'govm use <version>' to create shims")
+	case !utils.IsCompleteInstall(activePath):
+		term.Printf("  ❌ Active version Go %s is not installed\n", active)
+		problems++
+	default:
+		if err := utils.RewriteShims(utils.GoVersion{Version: active, Path: activePath, Installed: true}); err != nil {
+			term.Printf("  ❌ %v\n", err)
+			problems++
+		} else {
+			term.Printf("  ✅ Shims point at Go %s\n", active)
+		}
+	}
+
+	if problems > 0 {
+		setExitCode(ExitFailure)
+		term.Printf("\n⚠️  %d problem(s) could not be repaired automatically\n", problems)
+		return
+	}
+	term.Println("\n✅ Repair complete")
+}
+
+// isIntactInstall reports whether a version directory has the parts a
+// toolchain needs to run.
+func isIntactInstall(versionPath string) bool {
+	if !utils.IsCompleteInstall(versionPath) {
+		return false
+	}
+	for _, dir := range []string{"pkg", "src"} {
+		if info, err := os.Stat(filepath.Join(versionPath, dir)); err != nil || !info.IsDir() {
+			return false
+		}
+	}
+	return true
+}
+
+// fixPermissions makes every file in a toolchain's bin and pkg/tool
+// directories executable again and returns how many needed it.
+func fixPermissions(versionPath string) int {
+	if runtime.GOOS == "windows" {
+		return 0
+	}
+	fixed := 0
+	dirs := []string{filepath.Join(versionPath, "bin")}
+	toolDirs, _ := filepath.Glob(filepath.Join(versionPath, "pkg", "tool", "*"))
+	dirs = append(dirs, toolDirs...)
+	for _, dir := range dirs {
+		entries, _ := os.ReadDir(dir)
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil || entry.IsDir() || info.Mode().Perm()&0111 == 0111 {
+				continue
+			}
+			if os.Chmod(filepath.Join(dir, entry.Name()), info.Mode().Perm()|0755) == nil {
+				fixed++
+			}
+		}
+	}
+	return fixed
+}
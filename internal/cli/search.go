@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"os"
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/melkeydev/govm/internal/events"
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// Search lists the releases on go.dev and the installed versions that match
+// pattern, with the install status of each. A pattern is a version line such
+// as 1.21 (matching 1.21, 1.21.5 and 1.21rc1 but not 1.210), a glob such as
+// 1.2* or 1.22.?, or any other text such as rc, matched as a substring.
+// Betas and release candidates that are not installed are included with pre
+// or when the pattern contains letters. When go.dev cannot be reached only installed versions
+// are searched.
+func Search(pattern string, pre bool) {
+	pattern = strings.TrimPrefix(pattern, "go")
+	if strings.IndexFunc(pattern, unicode.IsLetter) >= 0 {
+		pre = true
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		failCodef(ExitUsage, "Invalid pattern %q: %v", pattern, err)
+		return
+	}
+	if !jsonOutput {
+		term.Infof("🔍 Searching for Go versions matching %s...\n", pattern)
+	}
+	installed, ok := utils.ScanInstalledVersions().(events.InstalledVersionsMsg)
+	if !ok {
+		installed = events.InstalledVersionsMsg{}
+	}
+	active := string(utils.DetectActiveVersion().(events.ActiveVersionMsg))
+	var remote []utils.GoVersion
+	var remoteErr error
+	switch msg := utils.FetchRemoteVersions().(type) {
+	case events.RemoteVersionsMsg:
+		remote = msg
+	case events.ErrMsg:
+		remoteErr = msg
+		term.Fprintf(os.Stderr, "⚠️  Could not reach go.dev, searching installed versions only: %v\n", msg)
+	}
+	versions := utils.MergeVersions(remote, installed, active)
+	// Versions no longer listed on go.dev, and tip, are only known locally
+	listed := map[string]bool{}
+	for _, v := range remote {
+		listed[v.Version] = true
+	}
+	for version, versionPath := range installed {
+		if listed[version] {
+			continue
+		}
+		versions = append(versions, utils.GoVersion{
+			Version:   version,
+			Path:      versionPath,
+			Installed: true,
+			Active:    version == active,
+			Stable:    version != utils.TipVersion && !utils.IsPreRelease(version),
+		})
+	}
+	utils.SortVersions(versions)
+
+	matches := []RemoteVersion{}
+	for _, v := range versions {
+		if !pre && !v.Stable && !v.Installed {
+			continue
+		}
+		if !matchVersionPattern(pattern, v.Version) {
+			continue
+		}
+		matches = append(matches, RemoteVersion{
+			Version:     v.Version,
+			Stable:      v.Stable,
+			Installed:   v.Installed,
+			Active:      v.Active,
+			Installable: v.Unsupported == "",
+			Reason:      v.Unsupported,
+		})
+		if !jsonOutput {
+			term.Printf("  %s%s\n", v.Version, remoteStatus(v))
+		}
+	}
+	if jsonOutput {
+		printJSON(matches)
+	}
+	if len(matches) == 0 {
+		// Without the release list a miss says nothing about go.dev
+		code := ExitNotFound
+		if remoteErr != nil {
+			code = exitCodeFor(remoteErr)
+		}
+		failCodef(code, "No Go versions match %s", pattern)
+		return
+	}
+	if !jsonOutput {
+		term.Infof("\n%d matching version(s). To install one: govm install <version>\n", len(matches))
+	}
+}
+
+// matchVersionPattern reports whether version matches a search pattern; see
+// Search for the pattern forms.
+func matchVersionPattern(pattern, version string) bool {
+	if strings.ContainsAny(pattern, "*?[") {
+		matched, _ := path.Match(pattern, version)
+		return matched
+	}
+	if pattern != "" && unicode.IsDigit(rune(pattern[0])) {
+		if !strings.HasPrefix(version, pattern) {
+			return false
+		}
+		rest := version[len(pattern):]
+		return rest == "" || strings.HasSuffix(pattern, ".") || !unicode.IsDigit(rune(rest[0]))
+	}
+	return strings.Contains(version, pattern)
+}
@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/melkeydev/govm/internal/clock"
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// statusSchemaVersion is bumped whenever a field of StatusResponse changes
+// meaning or is removed; adding fields does not change it.
+const statusSchemaVersion = 1
+
+// StatusResponse is the body of GET /v1/status. Editor integrations depend
+// on it, so it is documented in the README and only ever extended.
+type StatusResponse struct {
+	SchemaVersion int               `json:"schema_version"`
+	GovmVersion   string            `json:"govm_version"`
+	ActiveVersion string            `json:"active_version"`
+	ShimDir       string            `json:"shim_dir"`
+	ShimInPath    bool              `json:"shim_in_path"`
+	Installed     []InstalledStatus `json:"installed"`
+	Project       *ProjectStatus    `json:"project,omitempty"`
+}
+
+// InstalledStatus describes one installed version.
+type InstalledStatus struct {
+	Version string `json:"version"`
+	GOROOT  string `json:"goroot"`
+	Active  bool   `json:"active"`
+	// InstalledAt and LastUsed are when the version was installed and last
+	// switched to, in UTC, or missing when not known
+	InstalledAt *time.Time `json:"installed_at,omitempty"`
+	LastUsed    *time.Time `json:"last_used,omitempty"`
+}
+
+// ProjectStatus is the version pinned for the directory passed as ?dir=.
+type ProjectStatus struct {
+	Version   string `json:"version"`
+	Source    string `json:"source"`
+	Installed bool   `json:"installed"`
+}
+
+// progressPoll is how often /v1/events checks for install progress.
+const progressPoll = 250 * time.Millisecond
+
+// Serve runs a read-only HTTP server on addr that reports GoVM's state as
+// JSON for editor plugins, and streams the progress of installs run by any
+// govm process as server-sent events. It never changes anything on disk.
+// Only requests for a loopback host from no other origin are answered, so
+// a web page cannot read it through the browser.
+func Serve(addr string) {
+	if host, _, err := net.SplitHostPort(addr); err != nil || !isLoopbackHost(host) {
+		failCodef(ExitUsage, "serve only listens on a loopback address such as 127.0.0.1:7385, not %s", addr)
+		os.Exit(ExitCode())
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/status", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		status, err := currentStatus(r.URL.Query().Get("dir"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(status)
+	})
+	mux.HandleFunc("/v1/events", serveProgressEvents)
+	term.Printf("🌐 Serving GoVM status on http://%s/v1/status (Ctrl+C to stop)\n", addr)
+	if err := http.ListenAndServe(addr, localOnly(mux)); err != nil {
+		fail(err)
+		os.Exit(ExitCode())
+	}
+}
+
+// localOnly rejects requests whose Host is not a loopback address, which
+// a DNS rebinding attack would send, and requests a browser makes on
+// behalf of a page from another origin.
+func localOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+		if !isLoopbackHost(host) {
+			http.Error(w, "forbidden host", http.StatusForbidden)
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" {
+			u, err := url.Parse(origin)
+			if err != nil || !isLoopbackHost(u.Hostname()) {
+				http.Error(w, "cross-origin requests are not allowed", http.StatusForbidden)
+				return
+			}
+		}
+		if site := r.Header.Get("Sec-Fetch-Site"); site == "cross-site" || site == "same-site" {
+			http.Error(w, "cross-origin requests are not allowed", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// serveProgressEvents streams GET /v1/events: a "progress" event with an
+// InstallProgress for every install running when the client connects, and
+// one for each change after that until it disconnects.
+func serveProgressEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	seen := map[string]time.Time{}
+	for _, p := range utils.LoadInstallProgress() {
+		seen[p.Version] = p.UpdatedAt
+		if p.Phase == utils.PhaseDownloading || p.Phase == utils.PhaseExtracting {
+			writeProgressEvent(w, p)
+		}
+	}
+	flusher.Flush()
+	ticker := clock.NewTicker(progressPoll)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C():
+		}
+		for _, p := range utils.LoadInstallProgress() {
+			if p.UpdatedAt.After(seen[p.Version]) {
+				seen[p.Version] = p.UpdatedAt
+				writeProgressEvent(w, p)
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func writeProgressEvent(w http.ResponseWriter, p utils.InstallProgress) {
+	data, _ := json.Marshal(p)
+	fmt.Fprintf(w, "event: progress\ndata: %s\n\n", data)
+}
+
+func currentStatus(dir string) (StatusResponse, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return StatusResponse{}, fmt.Errorf("failed to get home directory: %v", err)
+	}
+	govmDir := filepath.Join(homeDir, ".govm")
+	status := StatusResponse{
+		SchemaVersion: statusSchemaVersion,
+		GovmVersion:   utils.GetVersion(),
+		ShimDir:       filepath.Join(govmDir, "shim"),
+		ShimInPath:    utils.IsShimInPath(),
+		Installed:     []InstalledStatus{},
+	}
+	status.ActiveVersion = activeVersion()
+	installed := map[string]bool{}
+	records, _ := utils.ReadSwitchHistory()
+	lastUsed := utils.LastUsed(records)
+	entries, _ := os.ReadDir(filepath.Join(govmDir, "versions"))
+	for _, entry := range entries {
+		versionPath := filepath.Join(govmDir, "versions", entry.Name())
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), "go") || !utils.IsCompleteInstall(versionPath) {
+			continue
+		}
+		version := strings.TrimPrefix(entry.Name(), "go")
+		installed[version] = true
+		item := InstalledStatus{
+			Version: version,
+			GOROOT:  versionPath,
+			Active:  version == status.ActiveVersion,
+		}
+		if at, ok := utils.InstalledAt(versionPath); ok {
+			item.InstalledAt = &at
+		}
+		if at, ok := lastUsed[version]; ok {
+			item.LastUsed = &at
+		}
+		status.Installed = append(status.Installed, item)
+	}
+	if dir != "" {
+		if version, source, err := utils.DetectProjectVersion(dir); err == nil {
+			status.Project = &ProjectStatus{Version: version, Source: source, Installed: installed[version]}
+		}
+	}
+	return status, nil
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+func TestLocalOnly(t *testing.T) {
+	handler := localOnly(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	tests := []struct {
+		name    string
+		host    string
+		headers map[string]string
+		want    int
+	}{
+		{"loopback", "127.0.0.1:7385", nil, http.StatusOK},
+		{"localhost", "localhost:7385", nil, http.StatusOK},
+		{"IPv6 loopback", "[::1]:7385", nil, http.StatusOK},
+		{"rebound DNS name", "attacker.example:7385", nil, http.StatusForbidden},
+		{"page on another origin", "127.0.0.1:7385", map[string]string{"Origin": "https://attacker.example"}, http.StatusForbidden},
+		{"page on a local origin", "127.0.0.1:7385", map[string]string{"Origin": "http://localhost:3000"}, http.StatusOK},
+		{"opaque origin", "127.0.0.1:7385", map[string]string{"Origin": "null"}, http.StatusForbidden},
+		{"cross-site fetch", "127.0.0.1:7385", map[string]string{"Sec-Fetch-Site": "cross-site"}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/status?dir=/home", nil)
+			req.Host = tt.host
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestProgressEvents(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeProgress := func(p utils.InstallProgress) {
+		t.Helper()
+		dir := filepath.Join(home, ".govm", "progress")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		content, _ := json.Marshal(p)
+		if err := os.WriteFile(filepath.Join(dir, "go"+p.Version+".json"), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	start := time.Now().UTC()
+	writeProgress(utils.InstallProgress{Version: "1.21.0", Phase: utils.PhaseDone, UpdatedAt: start})
+	writeProgress(utils.InstallProgress{Version: "1.22.4", Phase: utils.PhaseDownloading, Downloaded: 10, Total: 100, UpdatedAt: start})
+
+	srv := httptest.NewServer(http.HandlerFunc(serveProgressEvents))
+	defer srv.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", got)
+	}
+	events := bufio.NewScanner(resp.Body)
+	next := func() utils.InstallProgress {
+		t.Helper()
+		for events.Scan() {
+			if data, ok := strings.CutPrefix(events.Text(), "data: "); ok {
+				var p utils.InstallProgress
+				if err := json.Unmarshal([]byte(data), &p); err != nil {
+					t.Fatal(err)
+				}
+				return p
+			}
+		}
+		t.Fatalf("stream ended: %v", events.Err())
+		return utils.InstallProgress{}
+	}
+	// The running install is sent at once, the finished one is not
+	if p := next(); p.Version != "1.22.4" || p.Downloaded != 10 {
+		t.Fatalf("first event = %+v", p)
+	}
+	writeProgress(utils.InstallProgress{Version: "1.22.4", Phase: utils.PhaseExtracting, UpdatedAt: start.Add(time.Second)})
+	if p := next(); p.Version != "1.22.4" || p.Phase != utils.PhaseExtracting {
+		t.Fatalf("second event = %+v", p)
+	}
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// ShellEnv prints the single statement that puts the shim directory at the
+// front of PATH, for 'eval "$(govm shellenv)"' in rc files and CI scripts.
+// Without a shell argument, the shell is detected from the parent process,
+// then $SHELL.
+func ShellEnv(shell string) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		failf("Failed to get home directory: %v", err)
+		os.Exit(ExitCode())
+	}
+	shimDir := filepath.Join(homeDir, ".govm", "shim")
+	if shell == "" {
+		shell = detectShell()
+	}
+	switch shell {
+	case "bash", "zsh", "sh", "dash", "ksh":
+		term.Printf("export PATH=%s\"${PATH:+:$PATH}\"\n", shQuote(shimDir))
+	case "fish":
+		term.Printf("set -gx PATH %s $PATH\n", shQuote(shimDir))
+	case "powershell", "pwsh":
+		term.Printf("$env:PATH = %s + [IO.Path]::PathSeparator + $env:PATH\n", psQuote(shimDir))
+	case "cmd":
+		term.Printf("set \"PATH=%s;%%PATH%%\"\n", shimDir)
+	default:
+		failCodef(ExitUsage, "Unsupported shell %s (expected bash, zsh, sh, fish, powershell or cmd)", strconv.Quote(shell))
+		os.Exit(ExitCode())
+	}
+}
+
+// detectShell guesses the shell that will evaluate our output: the parent
+// process where the OS exposes it, otherwise $SHELL.
+func detectShell() string {
+	if runtime.GOOS == "windows" {
+		return "powershell"
+	}
+	if comm, err := os.ReadFile(fmt.Sprintf("/proc/%d/comm", os.Getppid())); err == nil {
+		name := strings.TrimPrefix(strings.TrimSpace(string(comm)), "-")
+		switch name {
+		case "bash", "zsh", "sh", "dash", "ksh", "fish", "pwsh":
+			return name
+		}
+	}
+	if shell := filepath.Base(os.Getenv("SHELL")); shell != "." && shell != "" {
+		return strings.TrimPrefix(shell, "-")
+	}
+	return "sh"
+}
+
+// shQuote single-quotes s for POSIX shells and fish.
+func shQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// psQuote single-quotes s for PowerShell.
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// Shell prints the statement that selects an installed version for the
+// current shell session only, for 'eval "$(govm shell 1.22)"'. The session
+// version sits between GOVM_GO_VERSION and project pins in the resolution
+// order. With unset it prints the statement that clears it again.
+func Shell(version string, unset bool) {
+	shell := detectShell()
+	if unset {
+		switch shell {
+		case "fish":
+			term.Printf("set -e %s\n", utils.ShellVersionEnv)
+		case "powershell", "pwsh":
+			term.Printf("Remove-Item Env:%s -ErrorAction SilentlyContinue\n", utils.ShellVersionEnv)
+		case "cmd":
+			term.Printf("set %s=\n", utils.ShellVersionEnv)
+		default:
+			term.Printf("unset %s\n", utils.ShellVersionEnv)
+		}
+		return
+	}
+	version = strings.TrimPrefix(utils.ResolveAlias(version), "go")
+	matchedVersion, err := findInstalledVersion(version)
+	if err != nil {
+		fail(err)
+		os.Exit(ExitCode())
+	}
+	if err := checkPolicy(matchedVersion.Version); err != nil {
+		fail(err)
+		os.Exit(ExitCode())
+	}
+	switch shell {
+	case "fish":
+		term.Printf("set -gx %s %s\n", utils.ShellVersionEnv, shQuote(matchedVersion.Version))
+	case "powershell", "pwsh":
+		term.Printf("$env:%s = %s\n", utils.ShellVersionEnv, psQuote(matchedVersion.Version))
+	case "cmd":
+		term.Printf("set \"%s=%s\"\n", utils.ShellVersionEnv, matchedVersion.Version)
+	default:
+		term.Printf("export %s=%s\n", utils.ShellVersionEnv, shQuote(matchedVersion.Version))
+	}
+}
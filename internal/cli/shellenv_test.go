@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/melkeydev/govm/internal/term"
+)
+
+func TestPrintEnvQuoting(t *testing.T) {
+	// A home directory a double-quoted value would expand or cut short
+	home := filepath.Join(t.TempDir(), `it's $HOME "here"`)
+	t.Setenv("HOME", home)
+	var out bytes.Buffer
+	term.Stdout = &out
+	t.Cleanup(func() { term.Stdout = os.Stdout })
+	PrintEnv("shell")
+
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("no sh to evaluate the exports with")
+	}
+	got, err := exec.Command(sh, "-c", out.String()+`printf %s "$GOVM_SHIM_DIR"`).Output()
+	if err != nil {
+		t.Fatalf("sh rejected %q: %v", out.String(), err)
+	}
+	if want := filepath.Join(home, ".govm", "shim"); string(got) != want {
+		t.Errorf("GOVM_SHIM_DIR = %q, want %q", got, want)
+	}
+}
+
+func TestPSQuote(t *testing.T) {
+	if got, want := psQuote(`C:\Users\o'brien\.govm\shim`), `'C:\Users\o''brien\.govm\shim'`; got != want {
+		t.Errorf("psQuote = %s, want %s", got, want)
+	}
+}
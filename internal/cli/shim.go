@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+func ListShims() {
+	shims, err := utils.ListShims()
+	if err != nil {
+		fail(err)
+		return
+	}
+	if len(shims) == 0 {
+		term.Println("  No shims found. Run 'govm use <version>' to create them.")
+		return
+	}
+	term.Println("📋 Shims:")
+	for _, shim := range shims {
+		status := "✓"
+		target := shim.Target
+		if target == "" {
+			status = "✗"
+			target = "(unreadable)"
+		} else if !shim.TargetExists {
+			status = "✗"
+			target += " (missing)"
+		}
+		if shim.Modified {
+			target += " (edited outside govm)"
+		}
+		term.Printf("  %s %-10s -> %s\n", status, shim.Tool, target)
+	}
+}
+
+// TraceShim prints each step of resolving which binary a tool's shim runs.
+func TraceShim(tool string) {
+	shimPath, err := utils.ShimPath(tool)
+	if err != nil {
+		fail(err)
+		return
+	}
+	term.Printf("🔍 Resolving %s\n", tool)
+	if _, err := os.Stat(shimPath); err != nil {
+		term.Printf("  shim:           %s (not found)\n", shimPath)
+		return
+	}
+	term.Printf("  shim:           %s\n", shimPath)
+
+	term.Printf("  global default: %s\n", orNone(activeVersion()))
+
+	cwd, _ := os.Getwd()
+	res, err := utils.ResolveShim(tool, cwd)
+	if err != nil {
+		term.Printf("  target:         %s\n", err)
+		return
+	}
+	label := "project pin:   "
+	switch res.PinSource {
+	case utils.VersionEnv:
+		label = "override:      "
+	case utils.ShellVersionEnv:
+		label = "shell session: "
+	}
+	switch {
+	case res.PinSource == "":
+		term.Println("  project pin:    (none)")
+	case res.PinInstalled:
+		term.Printf("  %s Go %s (%s)\n", label, res.Pin, res.PinSource)
+	default:
+		term.Printf("  %s Go %s (%s, not installed, using the global default)\n", label, res.Pin, res.PinSource)
+	}
+	target := res.Target
+	if _, err := os.Stat(target); err != nil {
+		term.Printf("  target:         %s (missing)\n", target)
+		return
+	}
+	term.Printf("  target:         %s\n", target)
+	term.Printf("  version:        %s\n", utils.VersionFromPath(target))
+}
@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/melkeydev/govm/internal/events"
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// Status prints a one-screen summary of the GoVM setup, meant to be pasted
+// into bug reports when debugging environment issues. With iso set its
+// timestamps are ISO-8601 in UTC.
+func Status(iso bool) {
+	if jsonOutput {
+		cwd, _ := os.Getwd()
+		status, err := currentStatus(cwd)
+		if err != nil {
+			fail(err)
+			return
+		}
+		printJSON(status)
+		return
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		failf("Failed to get home directory: %v", err)
+		return
+	}
+	govmDir := filepath.Join(homeDir, ".govm")
+	term.Printf("govm %s\n\n", utils.GetVersion())
+	term.Printf("  root:            %s\n", govmDir)
+
+	defaultVersion := activeVersion()
+	term.Printf("  global default:  %s\n", orNone(defaultVersion))
+	if override := os.Getenv(utils.VersionEnv); override != "" {
+		term.Printf("  %s: %s (overrides pins and the global default)\n", utils.VersionEnv, override)
+	}
+	if shellVersion := os.Getenv(utils.ShellVersionEnv); shellVersion != "" {
+		term.Printf("  shell session:   %s (%s, overrides pins and the global default)\n", shellVersion, utils.ShellVersionEnv)
+	}
+	term.Printf("  go on PATH:      %s\n", orNone(utils.GetCurrentGoVersion()))
+	if goPath, err := exec.LookPath("go"); err == nil {
+		term.Printf("  go resolves to:  %s\n", goPath)
+	}
+
+	cwd, _ := os.Getwd()
+	if version, source, err := utils.DetectProjectVersion(cwd); err == nil {
+		term.Printf("  project pin:     %s (%s)\n", version, source)
+	} else {
+		term.Printf("  project pin:     (none)\n")
+	}
+
+	installed := 0
+	newest, newestAt := "", time.Time{}
+	entries, _ := os.ReadDir(filepath.Join(govmDir, "versions"))
+	for _, entry := range entries {
+		versionPath := filepath.Join(govmDir, "versions", entry.Name())
+		if !entry.IsDir() || !utils.IsCompleteInstall(versionPath) {
+			continue
+		}
+		installed++
+		if at, ok := utils.InstalledAt(versionPath); ok && at.After(newestAt) {
+			newest, newestAt = strings.TrimPrefix(entry.Name(), "go"), at
+		}
+	}
+	if newest != "" {
+		term.Printf("  installed:       %d version(s), newest install %s on %s\n", installed, newest, utils.FormatTimestamp(newestAt, iso))
+	} else {
+		term.Printf("  installed:       %d version(s)\n", installed)
+	}
+	if records, _ := utils.ReadSwitchHistory(); len(records) > 0 {
+		term.Printf("  last switch:     %s on %s\n", records[0].Version, utils.FormatTimestamp(records[0].Time, iso))
+	}
+
+	shims, err := utils.ListShims()
+	broken := 0
+	for _, shim := range shims {
+		if !shim.TargetExists {
+			broken++
+		}
+	}
+	switch {
+	case err != nil:
+		term.Printf("  shims:           %v\n", err)
+	case broken > 0:
+		term.Printf("  shims:           %d, %d broken (see 'govm shim list')\n", len(shims), broken)
+	default:
+		term.Printf("  shims:           %d, all healthy\n", len(shims))
+	}
+	if utils.IsShimInPath() {
+		term.Printf("  shim in PATH:    yes\n")
+	} else {
+		term.Printf("  shim in PATH:    no (%s)\n", utils.GetShimPathInstructions())
+	}
+
+	term.Printf("  latest stable:   ")
+	versions, ok := utils.FetchGoVersions().(events.VersionsMsg)
+	if !ok {
+		term.Println("(could not reach go.dev)")
+		return
+	}
+	latest := ""
+	newestPatch := ""
+	for _, v := range versions {
+		if v.Stable && latest == "" {
+			latest = v.Version
+		}
+		if defaultVersion != "" && newestPatch == "" && minorVersion(v.Version) == minorVersion(defaultVersion) {
+			newestPatch = v.Version
+		}
+	}
+	term.Println(orNone(latest))
+	if newestPatch != "" && compareVersions(newestPatch, defaultVersion) > 0 {
+		term.Printf("  outdated:        %s → %s available\n", defaultVersion, newestPatch)
+	}
+}
+
+// RemoteStatus runs 'govm status' on another machine over SSH. Nothing is
+// changed on the remote host.
+func RemoteStatus(host string) {
+	args := []string{host, "govm", "status"}
+	if jsonOutput {
+		args = append(args, "--json")
+	}
+	cmd := exec.Command("ssh", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		failf("Failed to get status from %s: %v", host, err)
+		os.Exit(ExitCode())
+	}
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+// Prompt prints the version the go shim runs in the current directory and
+// nothing else, for embedding in shell prompts. It never touches the network
+// and prints nothing when no version is active.
+func Prompt() {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	res, err := utils.ResolveShim("go", cwd)
+	if err != nil {
+		return
+	}
+	if version := utils.VersionFromPath(res.Target); version != "" {
+		os.Stdout.WriteString(version + "\n")
+	}
+}
+
+// GoRoot prints the GOROOT of the version the go shim runs in the current
+// directory, or of the named version, for Makefiles and editor settings. It
+// reports whether the version is installed.
+func GoRoot(version string) bool {
+	if version == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			failf("Failed to get current directory: %v", err)
+			return false
+		}
+		res, err := utils.ResolveShim("go", cwd)
+		if err != nil || utils.VersionFromPath(res.Target) == "" {
+			failf("No active version; run 'govm use <version>' or name a version")
+			return false
+		}
+		version = utils.VersionFromPath(res.Target)
+	}
+	version = strings.TrimPrefix(utils.ResolveAlias(version), "go")
+	installed, err := findInstalledVersion(version)
+	if err != nil || !utils.IsCompleteInstall(installed.Path) {
+		failCodef(ExitNotInstalled, "Go %s is not installed; run 'govm install %s'", version, version)
+		return false
+	}
+	term.Println(installed.Path)
+	return true
+}
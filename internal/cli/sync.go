@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"os"
+	"slices"
+
+	"github.com/melkeydev/govm/internal/events"
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// Sync converges this machine to a govm.versions manifest: every listed
+// version that is missing is installed and the default one is activated.
+// Without a path, the manifest is looked up from the current directory
+// upwards. Missing versions install up to jobs at a time. It reports
+// whether everything succeeded.
+func Sync(path string, jobs int) bool {
+	if path == "" {
+		cwd, _ := os.Getwd()
+		found, err := utils.FindManifest(cwd)
+		if err != nil {
+			fail(err)
+			return false
+		}
+		path = found
+	}
+	manifest, err := utils.ReadManifest(path)
+	if err != nil {
+		fail(err)
+		return false
+	}
+	term.Printf("📋 Syncing with %s\n", path)
+	ok := true
+	var missing []utils.GoVersion
+	for _, version := range manifest.Versions {
+		if installed, err := findInstalledVersion(version); err == nil && utils.IsCompleteInstall(installed.Path) {
+			term.Printf("✅ Go %s is installed\n", installed.Version)
+			continue
+		}
+		matchedVersion, err := resolveInstallTarget(version, false)
+		if err != nil {
+			fail(err)
+			ok = false
+			continue
+		}
+		if !slices.ContainsFunc(missing, func(v utils.GoVersion) bool { return v.Version == matchedVersion.Version }) {
+			missing = append(missing, matchedVersion)
+		}
+	}
+	if jobs > 1 && len(missing) > 1 {
+		for _, err := range installConcurrently(missing, jobs) {
+			if err != nil {
+				ok = false
+			}
+		}
+	} else {
+		for _, matchedVersion := range missing {
+			if err := installResolved(matchedVersion); err != nil {
+				ok = false
+			}
+		}
+	}
+	if manifest.Default == "" {
+		return ok
+	}
+	installed, err := findInstalledVersion(manifest.Default)
+	if err != nil {
+		failf("Cannot activate the default: %s", err)
+		return false
+	}
+	if err := checkPolicy(installed.Version); err != nil {
+		fail(err)
+		return false
+	}
+	term.Infof("🔄 Activating default Go %s...\n", installed.Version)
+	if msg, isErr := utils.SwitchVersion(installed)().(events.ErrMsg); isErr {
+		failf("Failed to switch version: %v", msg)
+		return false
+	}
+	term.Printf("✅ Switched to Go %s\n", installed.Version)
+	return ok
+}
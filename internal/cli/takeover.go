@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/events"
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// manualGoRoot is where the official installers and tarball instructions
+// put Go on Linux and macOS.
+const manualGoRoot = "/usr/local/go"
+
+var shellRCFiles = []string{".bashrc", ".bash_profile", ".profile", ".zshrc", ".zprofile", ".zshenv"}
+
+// Takeover migrates a manually installed Go under /usr/local/go to GoVM: it
+// installs the same version, activates it, comments out the old GOROOT and
+// PATH exports in the user's shell files (keeping backups) and validates the
+// resulting environment.
+func Takeover() {
+	if runtime.GOOS == "windows" {
+		failf("takeover is only supported on Linux and macOS")
+		return
+	}
+	goBin := filepath.Join(manualGoRoot, "bin", "go")
+	if _, err := os.Stat(goBin); err != nil {
+		term.Printf("✨ No manual Go installation found at %s\n", manualGoRoot)
+		return
+	}
+	version, err := utils.ToolchainVersion(goBin)
+	if err != nil {
+		fail(err)
+		return
+	}
+	term.Infof("🔍 Found Go %s at %s\n", version, manualGoRoot)
+
+	matchedVersion, err := findInstalledVersion(version)
+	if err != nil || matchedVersion.Version != version {
+		term.Infof("📥 Installing Go %s under GoVM...\n", version)
+		remote, err := findMatchingVersion(version, false)
+		if err != nil {
+			fail(err)
+			return
+		}
+		switch msg := utils.DownloadAndInstall(remote)().(type) {
+		case events.ErrMsg:
+			failf("Installation failed: %v", msg)
+			return
+		case events.DownloadCompleteMsg:
+			matchedVersion = utils.GoVersion{Version: msg.Version, Path: msg.Path, Installed: true}
+		}
+	} else {
+		term.Printf("✅ Go %s is already installed under GoVM\n", version)
+	}
+
+	term.Infof("🔄 Switching to Go %s...\n", matchedVersion.Version)
+	if msg, ok := utils.SwitchVersion(matchedVersion)().(events.ErrMsg); ok {
+		failf("Failed to switch version: %v", msg)
+		return
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		failf("Failed to get home directory: %v", err)
+		return
+	}
+	for _, name := range shellRCFiles {
+		rcFile := filepath.Join(homeDir, name)
+		changed, backup, err := commentOutManualGo(rcFile)
+		if err != nil {
+			term.Printf("⚠️  Could not update %s: %v\n", rcFile, err)
+			continue
+		}
+		if changed > 0 {
+			term.Printf("📝 Commented out %d line(s) in %s (backup: %s)\n", changed, rcFile, backup)
+		}
+	}
+	if configFile, err := configureShell(); err != nil {
+		term.Printf("⚠️  Could not configure shell automatically: %v\n", err)
+		term.Println(utils.GetShimPathInstructions())
+	} else {
+		term.Printf("📝 Ensured the GoVM shim directory is set in %s\n", configFile)
+	}
+
+	term.Infoln("🔍 Validating environment...")
+	if os.Getenv("GOROOT") == manualGoRoot {
+		term.Printf("⚠️  GOROOT is still set to %s in this shell; open a new terminal\n", manualGoRoot)
+	}
+	shimDir := filepath.Join(homeDir, ".govm", "shim")
+	for _, entry := range filepath.SplitList(os.Getenv("PATH")) {
+		if entry == shimDir {
+			break
+		}
+		if entry == filepath.Join(manualGoRoot, "bin") {
+			term.Printf("⚠️  %s comes before the GoVM shim in this shell's PATH; open a new terminal\n", entry)
+			break
+		}
+	}
+	term.Printf("✅ Go %s is now managed by GoVM\n", matchedVersion.Version)
+	term.Infof("👉 Once everything works, you can remove the old install with: sudo rm -rf %s\n", manualGoRoot)
+}
+
+// commentOutManualGo comments out lines in rcFile that export GOROOT or put
+// /usr/local/go on the PATH, keeping a backup first. It returns the number
+// of lines changed and the backup's name.
+func commentOutManualGo(path string) (int, string, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return 0, "", nil
+	}
+	rc, err := readRCFile(path)
+	if err != nil {
+		return 0, "", err
+	}
+	changed := 0
+	for i, line := range rc.lines {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "#") && strings.Contains(trimmed, manualGoRoot) &&
+			(strings.Contains(trimmed, "GOROOT") || strings.Contains(trimmed, "PATH")) {
+			rc.lines[i] = "# " + line + " # disabled by govm takeover"
+			changed++
+		}
+	}
+	if changed == 0 {
+		return 0, "", nil
+	}
+	backup, err := rc.save()
+	if err != nil {
+		return 0, "", err
+	}
+	return changed, backup, nil
+}
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/melkeydev/govm/internal/plugin"
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+func ListTools() {
+	term.Println("📋 Companion tools:")
+	for _, name := range plugin.Names() {
+		toolsDir, err := plugin.ToolsDir(name)
+		if err != nil {
+			fail(err)
+			return
+		}
+		term.Printf("  %s\n", name)
+		entries, _ := os.ReadDir(toolsDir)
+		for _, entry := range entries {
+			if entry.IsDir() {
+				term.Printf("    %s\n", entry.Name())
+			}
+		}
+	}
+	term.Println("\nOther tools can be added with a govm-plugin-<name> executable on PATH.")
+}
+
+// InstallTools installs and activates every tool pinned in the project's
+// tool pin file.
+func InstallTools() {
+	cwd, _ := os.Getwd()
+	pins, pinFile, err := plugin.ReadPins(cwd)
+	if err != nil {
+		fail(err)
+		return
+	}
+	term.Infof("🔍 Using %s\n", pinFile)
+	names := make([]string, 0, len(pins))
+	for name := range pins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !InstallTool(name, pins[name]) {
+			return
+		}
+	}
+}
+
+// InstallTool installs a version of a companion tool, if needed, and points
+// its shims at it. It reports whether it succeeded.
+func InstallTool(name, version string) bool {
+	tool, err := plugin.Lookup(name)
+	if err != nil {
+		fail(err)
+		return false
+	}
+	toolsDir, err := plugin.ToolsDir(name)
+	if err != nil {
+		fail(err)
+		return false
+	}
+	dir := filepath.Join(toolsDir, version)
+	if _, err := os.Stat(dir); err == nil {
+		term.Printf("✅ %s %s is already installed\n", name, version)
+	} else {
+		term.Infof("📥 Installing %s %s...\n", name, version)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			failf("Failed to create %s: %v", dir, err)
+			return false
+		}
+		if err := tool.Install(version, dir); err != nil {
+			os.RemoveAll(dir)
+			failf("Installation failed: %v", err)
+			return false
+		}
+	}
+	return useTool(tool, version, dir)
+}
+
+// UseTool points the shims of a companion tool at an installed version.
+func UseTool(name, version string) {
+	tool, err := plugin.Lookup(name)
+	if err != nil {
+		fail(err)
+		return
+	}
+	toolsDir, err := plugin.ToolsDir(name)
+	if err != nil {
+		fail(err)
+		return
+	}
+	dir := filepath.Join(toolsDir, version)
+	if _, err := os.Stat(dir); err != nil {
+		failCodef(ExitNotInstalled, "%s %s is not installed. Run 'govm tool install %s %s' first.", name, version, name, version)
+		return
+	}
+	useTool(tool, version, dir)
+}
+
+func useTool(tool plugin.Tool, version, dir string) bool {
+	if err := utils.SetupShimDirectory(); err != nil {
+		fail(err)
+		return false
+	}
+	homeDir, _ := os.UserHomeDir()
+	shimDir := filepath.Join(homeDir, ".govm", "shim")
+	for _, bin := range tool.Binaries() {
+		target := filepath.Join(dir, bin)
+		if err := utils.WriteShim(shimDir, filepath.Base(bin), target); err != nil {
+			fail(err)
+			return false
+		}
+	}
+	term.Printf("✅ Using %s %s\n", tool.Name(), version)
+	return true
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/events"
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// SelfUninstall removes everything GoVM created: shims, installed versions,
+// the ~/.govm directory and, if the user agrees, the PATH lines added to
+// shell config files. The govm binary itself is left for the package
+// manager or user to remove.
+func SelfUninstall() {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		failf("Failed to get home directory: %v", err)
+		return
+	}
+	govmDir := filepath.Join(homeDir, ".govm")
+	if _, err := os.Stat(govmDir); err == nil {
+		if err := utils.CheckNotInside(govmDir); err != nil {
+			fail(err)
+			return
+		}
+		size := dirSize(govmDir)
+		term.Printf("⚠️  This will delete %s (%s), including all installed Go versions.\n", govmDir, events.FormatBytes(size))
+		if !confirmDestructive("   Continue? (y/N): ") {
+			term.Println("🛑 Operation canceled.")
+			return
+		}
+		for _, sub := range []string{"shim", "versions"} {
+			if err := os.RemoveAll(filepath.Join(govmDir, sub)); err != nil {
+				failf("Failed to remove %s: %v", sub, err)
+				return
+			}
+		}
+		if err := os.RemoveAll(govmDir); err != nil {
+			failf("Failed to remove %s: %v", govmDir, err)
+			return
+		}
+		term.Printf("🗑️  Removed %s\n", govmDir)
+	} else {
+		term.Printf("ℹ️  %s does not exist\n", govmDir)
+	}
+
+	var configs []*rcFile
+	for _, name := range append(shellRCFiles, filepath.Join(".config", "fish", "config.fish")) {
+		rc, err := readRCFile(filepath.Join(homeDir, name))
+		if err != nil {
+			continue
+		}
+		diff, manual := rc.removeGovmPath()
+		for _, line := range manual {
+			term.Printf("⚠️  %s still mentions GoVM; edit it by hand: %s\n", rc.path, strings.TrimSpace(line))
+		}
+		if len(diff) == 0 {
+			continue
+		}
+		if len(configs) == 0 {
+			term.Println("\nThese shell config files still add GoVM to your PATH:")
+		}
+		term.Printf("  %s\n", rc.path)
+		for _, line := range diff {
+			term.Printf("    %s\n", line)
+		}
+		configs = append(configs, rc)
+	}
+	if len(configs) > 0 && confirm("Apply these changes? (y/N): ") {
+		for _, rc := range configs {
+			backup, err := rc.save()
+			if err != nil {
+				failf("Failed to update %s: %v", rc.path, err)
+				continue
+			}
+			term.Printf("📝 Updated %s (backup: %s)\n", rc.path, backup)
+		}
+	}
+	term.Println("\n✅ GoVM data removed. Delete the govm binary to finish uninstalling.")
+}
@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/events"
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// Upgrade installs the newest patch release for every installed minor
+// version, or only for the given one. When the active version is upgraded
+// the active pointer moves to the new patch; with prune the superseded
+// patches are deleted afterwards. With preflight the project in the current
+// directory is built and vetted with the new version first, and the active
+// version is left alone if that fails.
+func Upgrade(minor string, prune, preflight bool) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		failf("Failed to get home directory: %v", err)
+		return
+	}
+	active := activeVersion()
+	goVersionsDir := filepath.Join(homeDir, ".govm", "versions")
+	entries, err := os.ReadDir(goVersionsDir)
+	if err != nil || len(entries) == 0 {
+		term.Println("  No versions installed yet")
+		return
+	}
+	minor = strings.TrimPrefix(minor, "go")
+	installed := map[string][]utils.GoVersion{}
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), "go") {
+			continue
+		}
+		version := strings.TrimPrefix(entry.Name(), "go")
+		line := minorVersion(version)
+		if minor != "" && line != minor {
+			continue
+		}
+		installed[line] = append(installed[line], utils.GoVersion{
+			Version:   version,
+			Path:      filepath.Join(goVersionsDir, entry.Name()),
+			Installed: true,
+			Active:    version == active,
+		})
+	}
+	if len(installed) == 0 {
+		failf("No installed version matching '%s' found", minor)
+		return
+	}
+
+	term.Infoln("🔍 Checking go.dev for newer patch releases...")
+	versions, ok := utils.FetchGoVersions().(events.VersionsMsg)
+	if !ok {
+		failCodef(ExitNetwork, "Failed to fetch versions from go.dev")
+		return
+	}
+	lines := make([]string, 0, len(installed))
+	for line := range installed {
+		lines = append(lines, line)
+	}
+	sort.Slice(lines, func(i, j int) bool { return compareVersions(lines[i], lines[j]) < 0 })
+	upgraded := 0
+	for _, line := range lines {
+		current := installed[line]
+		var newest utils.GoVersion
+		for _, v := range versions {
+			if v.Stable && minorVersion(v.Version) == line &&
+				(newest.Version == "" || compareVersions(v.Version, newest.Version) > 0) {
+				newest = v
+			}
+		}
+		if newest.Version == "" {
+			continue
+		}
+		latestInstalled := current[0]
+		for _, v := range current[1:] {
+			if compareVersions(v.Version, latestInstalled.Version) > 0 {
+				latestInstalled = v
+			}
+		}
+		if compareVersions(newest.Version, latestInstalled.Version) <= 0 {
+			term.Printf("✅ Go %s is up to date (%s)\n", line, latestInstalled.Version)
+			continue
+		}
+
+		term.Infof("📥 Upgrading Go %s: %s → %s\n", line, latestInstalled.Version, newest.Version)
+		msg := utils.DownloadAndInstall(newest)()
+		done, ok := msg.(events.DownloadCompleteMsg)
+		if !ok {
+			failf("Installation failed: %v", msg)
+			continue
+		}
+		newest.Installed = true
+		newest.Path = done.Path
+		upgraded++
+
+		movedActive := false
+		for _, v := range current {
+			if v.Active && preflight {
+				term.Printf("🧪 Running preflight checks with Go %s...\n", newest.Version)
+				if err := runPreflight(newest.Path); err != nil {
+					failf("Preflight failed, staying on Go %s:\n%v", v.Version, err)
+					break
+				}
+				term.Println("✅ Preflight checks passed")
+			}
+			if v.Active {
+				if msg, ok := utils.SwitchVersion(newest)().(events.ErrMsg); ok {
+					failf("Failed to switch version: %v", msg)
+				} else {
+					term.Printf("🔄 Active version moved to Go %s\n", newest.Version)
+					term.Infof("👉 If Go %s breaks your build, run 'govm rollback' to return to Go %s\n", newest.Version, v.Version)
+					movedActive = true
+				}
+			}
+		}
+		if !prune {
+			continue
+		}
+		for _, v := range current {
+			if v.Active && !movedActive {
+				continue
+			}
+			v.Active = false
+			if msg, ok := utils.DeleteVersion(v)().(events.ErrMsg); ok {
+				failf("Failed to delete Go %s: %v", v.Version, msg)
+			} else {
+				term.Printf("🗑️  Deleted Go %s\n", v.Version)
+			}
+		}
+	}
+	if upgraded == 0 {
+		term.Println("✨ Everything is up to date")
+	}
+}
+
+// runPreflight builds and vets the project in the current directory with the
+// toolchain at goRoot, using a throwaway build cache so nothing leaks into
+// the user's environment.
+func runPreflight(goRoot string) error {
+	cwd, _ := os.Getwd()
+	if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err != nil {
+		return fmt.Errorf("no go.mod in %s", cwd)
+	}
+	cacheDir, err := os.MkdirTemp("", "govm-preflight-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(cacheDir)
+	goBin := filepath.Join(goRoot, "bin", "go")
+	env := append(os.Environ(),
+		"GOROOT="+goRoot,
+		"GOCACHE="+cacheDir,
+		"GOTOOLCHAIN=local",
+		"PATH="+filepath.Join(goRoot, "bin")+string(os.PathListSeparator)+os.Getenv("PATH"),
+	)
+	for _, args := range [][]string{{"build", "./..."}, {"vet", "./..."}} {
+		cmd := exec.Command(goBin, args...)
+		cmd.Env = env
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("go %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+	}
+	return nil
+}
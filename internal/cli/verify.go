@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/events"
+	"github.com/melkeydev/govm/internal/term"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// Verify re-validates an installed version: the expected directories exist,
+// the go binary runs and reports the version its directory is named for, and
+// a cached release archive, if any, still matches its published checksum.
+// It reports whether every check passed.
+func Verify(version string) bool {
+	version = strings.TrimPrefix(utils.ResolveAlias(version), "go")
+	installed, err := findInstalledVersion(version)
+	if err != nil {
+		fail(err)
+		return false
+	}
+	term.Infof("🔍 Verifying Go %s in %s\n", installed.Version, installed.Path)
+	ok := true
+	for _, dir := range []string{"bin", "pkg", "src"} {
+		if info, err := os.Stat(filepath.Join(installed.Path, dir)); err != nil || !info.IsDir() {
+			term.Printf("  ❌ missing %s/\n", dir)
+			ok = false
+		}
+	}
+
+	goBin := filepath.Join(installed.Path, "bin", "go")
+	if _, err := os.Stat(goBin + ".exe"); err == nil {
+		goBin += ".exe"
+	}
+	output, err := exec.Command(goBin, "version").CombinedOutput()
+	reported := strings.TrimSpace(string(output))
+	switch {
+	case err != nil:
+		term.Printf("  ❌ go version failed: %v\n", err)
+		ok = false
+	case installed.Version == utils.TipVersion:
+		term.Printf("  ✅ %s\n", reported)
+	case !strings.Contains(reported, "go"+installed.Version+" "):
+		term.Printf("  ❌ go version reports %q, expected go%s\n", reported, installed.Version)
+		ok = false
+	default:
+		term.Printf("  ✅ %s\n", reported)
+	}
+
+	if !verifyCachedArchive(installed.Version) {
+		ok = false
+	}
+	if ok {
+		term.Printf("✅ Go %s looks healthy\n", installed.Version)
+	} else {
+		failf("Go %s failed verification; reinstall it with: govm install %s", installed.Version, installed.Version)
+	}
+	return ok
+}
+
+// verifyCachedArchive compares any archive of version left in the downloads
+// directory against the checksum published on go.dev. Having no cached
+// archive, or no network, is not a failure.
+func verifyCachedArchive(version string) bool {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return true
+	}
+	downloadDir := filepath.Join(homeDir, ".govm", "downloads")
+	archives := cachedArchives(downloadDir, version)
+	if len(archives) == 0 {
+		term.Println("  ➖ no cached archive to checksum")
+		return true
+	}
+	versions, isVersions := utils.FetchGoVersions().(events.VersionsMsg)
+	if !isVersions {
+		term.Println("  ⚠️  could not fetch published checksums from go.dev")
+		return true
+	}
+	ok := true
+	for _, name := range archives {
+		expected := ""
+		for _, v := range versions {
+			if v.Filename == name {
+				expected = v.SHA256
+				break
+			}
+		}
+		if expected == "" {
+			term.Printf("  ➖ no published checksum for %s\n", name)
+			continue
+		}
+		sum, err := utils.FileSHA256(filepath.Join(downloadDir, name))
+		if err != nil {
+			term.Printf("  ❌ failed to read %s: %v\n", name, err)
+			ok = false
+			continue
+		}
+		if sum != expected {
+			term.Printf("  ❌ %s checksum mismatch: got %s, expected %s\n", name, sum, expected)
+			ok = false
+			continue
+		}
+		term.Printf("  ✅ %s matches its published SHA-256\n", name)
+	}
+	return ok
+}
+
+// cachedArchives returns the names of release archives for version in the
+// downloads directory.
+func cachedArchives(downloadDir, version string) []string {
+	entries, _ := os.ReadDir(downloadDir)
+	var archives []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasPrefix(entry.Name(), "go"+version+".") && utils.IsArchiveReference(entry.Name()) {
+			archives = append(archives, entry.Name())
+		}
+	}
+	return archives
+}
+
+// ReportWeakChecksum warns on stderr that the archive filename is being
+// installed without a checksum from go.dev, for reason. It is installed as
+// utils.OnWeakChecksum for CLI commands.
+func ReportWeakChecksum(filename, reason string) {
+	term.Fprintf(os.Stderr, "⚠️  %s: %s\n", filename, reason)
+}
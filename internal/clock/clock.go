@@ -0,0 +1,139 @@
+// Package clock abstracts the passage of time so that code which waits,
+// ticks or stamps records can be driven deterministically.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the source of time used throughout govm.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks on C until stopped.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Default is the clock govm uses. Replace it with a Fake to control time.
+var Default Clock = Real{}
+
+// Now returns the current time of the Default clock.
+func Now() time.Time { return Default.Now() }
+
+// Sleep pauses for d on the Default clock.
+func Sleep(d time.Duration) { Default.Sleep(d) }
+
+// NewTicker returns a ticker on the Default clock.
+func NewTicker(d time.Duration) Ticker { return Default.NewTicker(d) }
+
+// Since returns the time elapsed since t on the Default clock.
+func Since(t time.Time) time.Duration { return Default.Now().Sub(t) }
+
+// Real is the wall clock.
+type Real struct{}
+
+func (Real) Now() time.Time        { return time.Now() }
+func (Real) Sleep(d time.Duration) { time.Sleep(d) }
+func (Real) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+type realTicker struct{ t *time.Ticker }
+
+func (r realTicker) C() <-chan time.Time { return r.t.C }
+func (r realTicker) Stop()               { r.t.Stop() }
+
+// Fake is a clock that only moves when Advance is called. Sleep returns once
+// the clock has been advanced past its deadline, and tickers fire once for
+// every period crossed.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*fakeTicker
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	until time.Time
+	done  chan struct{}
+}
+
+// NewFake returns a Fake clock set to start.
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *Fake) Sleep(d time.Duration) {
+	f.mu.Lock()
+	if d <= 0 {
+		f.mu.Unlock()
+		return
+	}
+	w := fakeWaiter{until: f.now.Add(d), done: make(chan struct{})}
+	f.waiters = append(f.waiters, w)
+	f.mu.Unlock()
+	<-w.done
+}
+
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t := &fakeTicker{clock: f, c: make(chan time.Time, 1), period: d, next: f.now.Add(d)}
+	f.tickers = append(f.tickers, t)
+	return t
+}
+
+// Advance moves the clock forward by d, firing tickers and waking sleepers
+// whose time has come.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+	for _, t := range f.tickers {
+		for !t.stopped && !t.next.After(f.now) {
+			// Like time.Ticker, drop ticks nobody has received yet
+			select {
+			case t.c <- t.next:
+			default:
+			}
+			t.next = t.next.Add(t.period)
+		}
+	}
+	waiting := f.waiters[:0]
+	for _, w := range f.waiters {
+		if w.until.After(f.now) {
+			waiting = append(waiting, w)
+			continue
+		}
+		close(w.done)
+	}
+	f.waiters = waiting
+}
+
+type fakeTicker struct {
+	clock   *Fake
+	c       chan time.Time
+	period  time.Duration
+	next    time.Time
+	stopped bool
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.c }
+
+func (t *fakeTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.stopped = true
+}
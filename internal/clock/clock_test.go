@@ -0,0 +1,89 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var start = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func TestFakeNow(t *testing.T) {
+	f := NewFake(start)
+	if got := f.Now(); !got.Equal(start) {
+		t.Fatalf("Now() = %v, want %v", got, start)
+	}
+	f.Advance(90 * time.Second)
+	if got := f.Now(); !got.Equal(start.Add(90 * time.Second)) {
+		t.Fatalf("Now() after Advance = %v", got)
+	}
+}
+
+func TestFakeSleep(t *testing.T) {
+	f := NewFake(start)
+	woke := make(chan struct{})
+	go func() {
+		f.Sleep(time.Minute)
+		close(woke)
+	}()
+	// Advance until the sleeper has registered and its deadline has passed
+	for elapsed := time.Duration(0); ; elapsed += 10 * time.Second {
+		select {
+		case <-woke:
+			if elapsed < time.Minute {
+				t.Fatalf("Sleep(1m) returned after %v", elapsed)
+			}
+			return
+		case <-time.After(time.Millisecond):
+			f.Advance(10 * time.Second)
+		}
+	}
+}
+
+func TestFakeSleepZero(t *testing.T) {
+	// Must not block
+	NewFake(start).Sleep(0)
+}
+
+func TestFakeTicker(t *testing.T) {
+	f := NewFake(start)
+	ticker := f.NewTicker(time.Second)
+	select {
+	case <-ticker.C():
+		t.Fatal("ticked before the clock moved")
+	default:
+	}
+	f.Advance(time.Second)
+	if got := <-ticker.C(); !got.Equal(start.Add(time.Second)) {
+		t.Errorf("tick = %v, want %v", got, start.Add(time.Second))
+	}
+	// Like time.Ticker, ticks nobody received are dropped
+	f.Advance(5 * time.Second)
+	if got := <-ticker.C(); !got.Equal(start.Add(2 * time.Second)) {
+		t.Errorf("tick = %v, want %v", got, start.Add(2*time.Second))
+	}
+	select {
+	case got := <-ticker.C():
+		t.Errorf("unexpected extra tick %v", got)
+	default:
+	}
+	ticker.Stop()
+	f.Advance(time.Hour)
+	select {
+	case got := <-ticker.C():
+		t.Errorf("stopped ticker ticked at %v", got)
+	default:
+	}
+}
+
+func TestDefault(t *testing.T) {
+	f := NewFake(start)
+	Default = f
+	t.Cleanup(func() { Default = Real{} })
+	f.Advance(time.Hour)
+	if got := Since(start); got != time.Hour {
+		t.Errorf("Since = %v, want 1h", got)
+	}
+	if got := Now(); !got.Equal(start.Add(time.Hour)) {
+		t.Errorf("Now = %v", got)
+	}
+}
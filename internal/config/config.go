@@ -0,0 +1,166 @@
+// Package config loads user settings from ~/.govm/config.json.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Config holds all user settings. Zero values mean "use the default".
+type Config struct {
+	TUI TUIConfig `json:"tui"`
+	// TimeFormat selects how timestamps are printed: "local" (the default)
+	// shows local time with a relative hint, "iso" prints ISO-8601 in UTC.
+	TimeFormat string `json:"time_format"`
+	// Policy points at an organization-wide version policy.
+	Policy PolicyConfig `json:"policy"`
+	// Project decides where a project's pinned version comes from.
+	Project ProjectConfig `json:"project"`
+	// Download controls where releases are downloaded from.
+	Download DownloadConfig `json:"download"`
+}
+
+// DownloadConfig controls where the release index and archives come from.
+type DownloadConfig struct {
+	// Mirrors are base URLs serving the same files as https://go.dev/dl/,
+	// such as https://golang.google.cn/dl/. They are tried in order, moving
+	// on when one cannot be reached or fails. Empty means go.dev alone.
+	Mirrors []string `json:"mirrors"`
+	// BaseURL is an artifact repository, such as an Artifactory or Nexus
+	// remote proxying https://go.dev/dl/, that archives are fetched from
+	// instead of go.dev and the mirrors. The release index still comes from
+	// the mirrors, and each archive is checked against its checksums.
+	BaseURL string `json:"base_url"`
+	// Auth holds credentials for base_url and the mirrors.
+	Auth AuthConfig `json:"auth"`
+	// Proxy is the HTTP(S) proxy for every request govm makes, such as
+	// "http://proxy.corp:3128". It overrides HTTP_PROXY and HTTPS_PROXY,
+	// which are honored when it is empty; NO_PROXY applies either way.
+	Proxy string `json:"proxy"`
+	// CABundle is a PEM file of CA certificates to trust in addition to
+	// the system's, for proxies that intercept TLS. GOVM_CA_BUNDLE
+	// overrides it.
+	CABundle string `json:"ca_bundle"`
+	// TLSMinVersion is the oldest TLS version accepted: "1.2", the
+	// default, or "1.3".
+	TLSMinVersion string `json:"tls_min_version"`
+	// IPVersion restricts connections to IPv4 with "4" or IPv6 with "6",
+	// for networks where one of them is broken. Empty uses both. The
+	// --ipv4 and --ipv6 flags override it.
+	IPVersion string `json:"ip_version"`
+	// LimitRate caps archive downloads, in bytes per second with an
+	// optional K, M or G suffix, such as "5M". Empty means no cap. The
+	// --limit-rate flag overrides it.
+	LimitRate string `json:"limit_rate"`
+	// IndexTTL is how long the cached release index is used before the
+	// mirror is asked whether it changed, as a duration such as "10m", the
+	// default; "0" asks every time.
+	IndexTTL string `json:"index_ttl"`
+	// Timeouts bound how long requests may take.
+	Timeouts TimeoutsConfig `json:"timeouts"`
+}
+
+// TimeoutsConfig holds request timeouts as durations such as "30s" or
+// "5m"; "0" disables one and empty keeps the default.
+type TimeoutsConfig struct {
+	// Dial bounds connecting, including the TLS handshake. Default 10s.
+	Dial string `json:"dial"`
+	// Header bounds the wait for a response once the request is sent.
+	// Default 30s.
+	Header string `json:"header"`
+	// Metadata bounds a whole request for the release index, release
+	// notes or policy. Default 10s.
+	Metadata string `json:"metadata"`
+	// Download bounds a whole archive download. Default none, since
+	// archives are large and links slow.
+	Download string `json:"download"`
+}
+
+// AuthConfig holds credentials for a private mirror. They are sent only to
+// the hosts of base_url and the mirrors; other hosts get the matching
+// ~/.netrc entry, if any. $VARIABLES are expanded from the environment so
+// that secrets need not be stored in the file.
+type AuthConfig struct {
+	// Header is a whole Authorization header value, such as
+	// "Bearer $ARTIFACTORY_TOKEN".
+	Header string `json:"header"`
+	// Username and Password are sent as basic auth when Header is empty.
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// ProjectConfig controls how a project's pinned version is found.
+type ProjectConfig struct {
+	// PinFiles are the files that pin a version, checked in this order in
+	// each directory from the working directory up: ".go-version",
+	// ".tool-versions", "mise.toml", ".mise.toml" and "go.mod". Leaving one
+	// out ignores it. Empty means all of them in that order. The shims are
+	// written with this list, so they follow a change once regenerated.
+	PinFiles []string `json:"pin_files"`
+	// AutoInstall installs a pinned version that is missing when run or
+	// exec need it, instead of stopping.
+	AutoInstall bool `json:"auto_install"`
+	// AutoInstallOnCD makes the cd hook install a missing pinned version in
+	// the background instead of only warning. It is opt-in because it
+	// downloads whenever you enter a project.
+	AutoInstallOnCD bool `json:"auto_install_on_cd"`
+}
+
+// PolicyConfig locates a version policy and decides how strictly it applies.
+type PolicyConfig struct {
+	// URL of the policy JSON; empty disables policy checks.
+	URL string `json:"url"`
+	// Enforce refuses installs and switches that violate the policy. When
+	// false, violations are only warned about, so a policy can be rolled
+	// out gradually.
+	Enforce bool `json:"enforce"`
+	// TTL is how long a fetched policy is used before fetching it again,
+	// as a duration such as "1h"; empty means an hour.
+	TTL string `json:"ttl,omitempty"`
+}
+
+// ISOTime reports whether timestamps should be printed as ISO-8601.
+func (c Config) ISOTime() bool {
+	return c.TimeFormat == "iso"
+}
+
+// TUIConfig controls the layout of the interactive interface.
+type TUIConfig struct {
+	// Compact hides version descriptions so more rows fit on screen.
+	Compact bool `json:"compact"`
+	// ListHeight caps the height of the available versions list.
+	ListHeight int `json:"list_height"`
+	// TableHeight caps the height of the installed versions table.
+	TableHeight int `json:"table_height"`
+}
+
+// Path returns the location of the config file.
+func Path() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %v", err)
+	}
+	return filepath.Join(homeDir, ".govm", "config.json"), nil
+}
+
+// Load reads the config file. A missing file yields the defaults.
+func Load() (Config, error) {
+	var cfg Config
+	path, err := Path()
+	if err != nil {
+		return cfg, err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	if err := json.Unmarshal(content, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,84 @@
+// Package events defines the messages govm's asynchronous operations report
+// back with. The commands in package utils run under Bubble Tea and return
+// exactly one of the completion messages below; the CLI calls the same
+// commands synchronously and type-switches on the result. New operations
+// should add their own completion message here rather than reusing one.
+package events
+
+import (
+	"fmt"
+	"time"
+)
+
+// ErrMsg reports that a command failed.
+type ErrMsg struct {
+	Err error
+}
+
+// NewErrMsg wraps err as an ErrMsg.
+func NewErrMsg(err error) ErrMsg {
+	return ErrMsg{Err: err}
+}
+
+// ErrMsgf builds an ErrMsg from a format string, like fmt.Errorf.
+func ErrMsgf(format string, args ...any) ErrMsg {
+	return ErrMsg{Err: fmt.Errorf(format, args...)}
+}
+
+func (e ErrMsg) Error() string { return e.Err.Error() }
+
+func (e ErrMsg) Unwrap() error { return e.Err }
+
+// VersionsMsg is the full version list from utils.FetchGoVersions,
+// annotated with installed and active state.
+type VersionsMsg []GoVersion
+
+// RemoteVersionsMsg, InstalledVersionsMsg and ActiveVersionMsg carry the
+// three parts of the version list separately so the TUI can paint each as
+// soon as it arrives.
+type RemoteVersionsMsg []GoVersion
+
+// InstalledVersionsMsg maps each installed version to its directory.
+type InstalledVersionsMsg map[string]string
+
+// ActiveVersionMsg is the version currently in use.
+type ActiveVersionMsg string
+
+// DownloadProgressMsg reports how far an archive download has got. Unlike
+// the completion messages it is sent repeatedly, through the
+// utils.DownloadProgressFunc given to utils.DownloadAndInstallWithProgress,
+// before the command's own result.
+type DownloadProgressMsg struct {
+	Version    string
+	Downloaded int64
+	// Total is the archive's size, or 0 when the server did not send it.
+	Total          int64
+	BytesPerSecond float64
+	// ETA is the estimated time left, or 0 when it cannot be estimated.
+	ETA time.Duration
+}
+
+// DownloadCompleteMsg reports that utils.DownloadAndInstall finished.
+type DownloadCompleteMsg struct {
+	Version string
+	Path    string
+}
+
+// SwitchCompletedMsg reports that utils.SwitchVersion finished.
+type SwitchCompletedMsg struct {
+	Version    string
+	ShimInPath bool
+}
+
+// DeleteCompleteMsg reports that utils.DeleteVersion finished.
+type DeleteCompleteMsg struct {
+	Version string
+}
+
+// SwitchHistoryMsg is the switch history from utils.LoadSwitchHistory,
+// newest first.
+type SwitchHistoryMsg []SwitchRecord
+
+// ProjectsMsg is the list of known projects from utils.LoadProjects, most
+// recently seen first.
+type ProjectsMsg []Project
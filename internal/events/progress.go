@@ -0,0 +1,39 @@
+package events
+
+import "fmt"
+
+// Percent returns how much of the archive has downloaded, from 0 to 100, or
+// -1 when the server did not send its size.
+func (m DownloadProgressMsg) Percent() float64 {
+	if m.Total <= 0 {
+		return -1
+	}
+	return min(100, float64(m.Downloaded)*100/float64(m.Total))
+}
+
+// String describes the progress for a status line, e.g.
+// "42% · 31.2 MiB of 74.5 MiB · 8.1 MiB/s · 5s left".
+func (m DownloadProgressMsg) String() string {
+	if m.Total <= 0 {
+		return fmt.Sprintf("%s · %s/s", FormatBytes(m.Downloaded), FormatBytes(int64(m.BytesPerSecond)))
+	}
+	s := fmt.Sprintf("%.0f%% · %s of %s · %s/s", m.Percent(), FormatBytes(m.Downloaded), FormatBytes(m.Total), FormatBytes(int64(m.BytesPerSecond)))
+	if m.ETA > 0 {
+		s += fmt.Sprintf(" · %s left", m.ETA)
+	}
+	return s
+}
+
+// FormatBytes formats n bytes with a binary unit, e.g. "74.5 MiB".
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,27 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDownloadProgressString(t *testing.T) {
+	tests := []struct {
+		msg  DownloadProgressMsg
+		want string
+	}{
+		{DownloadProgressMsg{Downloaded: 512, BytesPerSecond: 256}, "512 B · 256 B/s"},
+		{
+			DownloadProgressMsg{Downloaded: 32 << 20, Total: 64 << 20, BytesPerSecond: 8 << 20, ETA: 4 * time.Second},
+			"50% · 32.0 MiB of 64.0 MiB · 8.0 MiB/s · 4s left",
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.msg.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+	if got := (DownloadProgressMsg{Downloaded: 10}).Percent(); got != -1 {
+		t.Errorf("Percent() without a size = %v, want -1", got)
+	}
+}
@@ -0,0 +1,34 @@
+package events
+
+import "time"
+
+// GoVersion is a Go release as go.dev lists it, annotated with whether and
+// where govm has installed it.
+type GoVersion struct {
+	Version   string
+	Filename  string
+	URL       string
+	Installed bool
+	Active    bool
+	Path      string
+	Stable    bool
+	// SHA256 is the published checksum of the release archive, when known.
+	SHA256 string
+	// Unsupported explains why govm cannot install this release; it is
+	// empty for installable releases.
+	Unsupported string
+}
+
+// SwitchRecord is one entry of the switch history. Times are stored in UTC
+// and converted to local time only for display.
+type SwitchRecord struct {
+	Time    time.Time
+	Version string
+}
+
+// Project is a known project with the version its pin file names now.
+type Project struct {
+	Dir     string
+	PinFile string
+	Version string
+}
@@ -0,0 +1,159 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/melkeydev/govm/internal/events"
+	"github.com/melkeydev/govm/internal/styles"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+// handleEvent applies the result of an asynchronous command to the model.
+// It reports false for messages that are not command results so Update can
+// pass them on to the list and table.
+func (m Model) handleEvent(msg tea.Msg) (Model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case events.ErrMsg:
+		m.Err = msg
+		m.Loading = false
+		m.Message = msg.Error()
+		m.MessageType = "error"
+		return m, nil, true
+	case events.VersionsMsg:
+		m.setVersions(msg)
+		m.Loading = false
+		return m, nil, true
+	case cachedRemoteVersionsMsg:
+		if len(msg) > 0 && m.RemoteVersions == nil {
+			m.RemoteVersions = events.RemoteVersionsMsg(msg)
+			m.Loading = false
+			m.mergeVersions()
+		}
+		m.refreshing = true
+		return m, fetchRemoteVersions(utils.FetchRemoteVersions), true
+	case events.RemoteVersionsMsg:
+		m.RemoteVersions = msg
+		m.staleIndexAt, _ = utils.StaleIndexTime()
+		m.offline = utils.IsOffline()
+		m.refreshing = false
+		m.Loading = false
+		m.mergeVersions()
+		return m, nil, true
+	case remoteVersionsFailedMsg:
+		m.refreshing = false
+		m.Loading = false
+		m.Message = fmt.Sprintf("Could not load the release list: %v. Installed versions can still be used and deleted.", msg.err)
+		m.MessageType = "error"
+		m.mergeVersions()
+		return m, nil, true
+	case events.InstalledVersionsMsg:
+		m.InstalledPaths = msg
+		m.mergeVersions()
+		return m, nil, true
+	case events.ActiveVersionMsg:
+		m.ActiveVersion = string(msg)
+		m.mergeVersions()
+		return m, nil, true
+	case events.SwitchHistoryMsg:
+		m.History = msg
+		m.updateHistoryTable()
+		// The installed table shows when each version was last used
+		m.updateInstalledTable()
+		return m, nil, true
+	case events.ProjectsMsg:
+		m.Projects = msg
+		m.updateProjectsTable()
+		return m, nil, true
+	case events.DownloadProgressMsg:
+		i := m.installIndex(msg.Version)
+		if i < 0 {
+			return m, nil, true
+		}
+		m.installs = slices.Clone(m.installs)
+		m.installs[i].progress = msg
+		return m, waitForDownloadProgress(m.installs[i].updates), true
+	case installFailedMsg:
+		next := m.finishInstall(msg.version)
+		m.Message = fmt.Sprintf("Could not install Go %s: %v", msg.version, msg.err)
+		m.MessageType = "error"
+		return m, next, true
+	case events.DownloadCompleteMsg:
+		next := m.finishInstall(msg.Version)
+		if m.InstalledPaths == nil {
+			m.InstalledPaths = map[string]string{}
+		}
+		m.InstalledPaths[msg.Version] = msg.Path
+		for i, v := range m.Versions {
+			if v.Version == msg.Version {
+				m.Versions[i].Installed = true
+				m.Versions[i].Path = msg.Path
+				break
+			}
+		}
+		items := m.List.Items()
+		for i, it := range items {
+			if it.(styles.Item).Name == msg.Version {
+				updatedItem := it.(styles.Item)
+				updatedItem.Installed = true
+				items[i] = updatedItem
+			}
+		}
+		m.List.SetItems(items)
+		m.updateInstalledTable()
+		m.Message = fmt.Sprintf("Successfully installed Go %s", msg.Version)
+		m.MessageType = "success"
+		return m, next, true
+	case events.SwitchCompletedMsg:
+		m.Loading = false
+		m.ActiveVersion = msg.Version
+		for i := range m.Versions {
+			m.Versions[i].Active = (m.Versions[i].Version == msg.Version)
+		}
+		items := m.List.Items()
+		for i, it := range items {
+			updatedItem := it.(styles.Item)
+			updatedItem.Active = (updatedItem.Name == msg.Version)
+			items[i] = updatedItem
+		}
+		m.List.SetItems(items)
+		m.updateInstalledTable()
+		if msg.ShimInPath {
+			m.Message = fmt.Sprintf("Switched to Go %s! Run 'go version' to verify.", msg.Version)
+		} else {
+			m.Message = fmt.Sprintf("Switched to Go %s!\n\n%s",
+				msg.Version, utils.GetShimPathInstructions())
+		}
+		m.MessageType = "success"
+		return m, utils.LoadSwitchHistory, true
+	case events.DeleteCompleteMsg:
+		m.Loading = false
+		delete(m.InstalledPaths, msg.Version)
+
+		for i, v := range m.Versions {
+			if v.Version == msg.Version {
+				m.Versions[i].Installed = false
+				m.Versions[i].Path = ""
+				break
+			}
+		}
+
+		items := m.List.Items()
+		for i, it := range items {
+			if it.(styles.Item).Name == msg.Version {
+				updatedItem := it.(styles.Item)
+				updatedItem.Installed = false
+				items[i] = updatedItem
+			}
+		}
+		m.List.SetItems(items)
+
+		m.updateInstalledTable()
+
+		m.Message = fmt.Sprintf("Successfully deleted Go %s", msg.Version)
+		m.MessageType = "success"
+		return m, nil, true
+	}
+	return m, nil, false
+}
@@ -2,37 +2,74 @@ package model
 
 import (
 	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/melkeydev/govm/internal/clock"
+	"github.com/melkeydev/govm/internal/events"
 	"github.com/melkeydev/govm/internal/styles"
+	"github.com/melkeydev/govm/internal/term"
 	"github.com/melkeydev/govm/internal/utils"
 )
 
 type Model struct {
-	List              list.Model
-	Versions          []utils.GoVersion
-	Err               error
-	Loading           bool
-	Spinner           spinner.Model
-	HomeDir           string
-	GoVersionsDir     string
-	CurrentTab        int
-	DownloadProgress  float64
-	InstallingVersion string
-	Message           string
-	MessageType       string // "success" or "error"
-	InstalledTable    table.Model
-	ConfirmingDelete  bool
-	DeleteVersion     string
+	List             list.Model
+	Versions         []utils.GoVersion
+	Err              error
+	Loading          bool
+	Spinner          spinner.Model
+	HomeDir          string
+	GoVersionsDir    string
+	CurrentTab       int
+	Message          string
+	MessageType      string // "success" or "error"
+	InstalledTable   table.Model
+	ConfirmingDelete bool
+	DeleteVersion    string
+	RemoteVersions   []utils.GoVersion
+	InstalledPaths   map[string]string
+	ActiveVersion    string
+	Delegate         list.DefaultDelegate
+	Compact          bool
+	ListHeight       int
+	TableHeight      int
+	HistoryTable     table.Model
+	History          []utils.SwitchRecord
+	ISOTime          bool
+	ProjectsTable    table.Model
+	Projects         []utils.Project
+	// installs are the versions queued for install with 'i', in order.
+	// Up to utils.DefaultInstallJobs of them install at once.
+	installs []installJob
+	// staleIndexAt is when the cached release list shown in place of a
+	// fresh one was fetched, or zero when it is fresh; offline tells whether
+	// that is because the network is out of reach
+	staleIndexAt time.Time
+	offline      bool
+	// refreshing is set while the release list is fetched behind the one
+	// already shown
+	refreshing bool
 }
 
+// The TUI's tabs, in the order tab cycles through them.
+var tabs = []string{"Available Versions", "Installed Versions", "Switch History", "Projects"}
+
+const projectsTab = 3
+
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
-		utils.FetchGoVersions,
+		loadCachedRemoteVersions,
+		utils.ScanInstalledVersions,
+		utils.DetectActiveVersion,
+		utils.LoadSwitchHistory,
+		utils.LoadProjects,
 		m.Spinner.Tick,
 	)
 }
@@ -46,21 +83,42 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		case tea.KeyTab.String():
 			// Switch between tabs
-			m.CurrentTab = (m.CurrentTab + 1) % 2
+			m.CurrentTab = (m.CurrentTab + 1) % len(tabs)
 			return m, nil
 		case "i":
+			if m.CurrentTab == projectsTab {
+				return m.installProjectVersion()
+			}
 			if m.CurrentTab == 0 {
 				selectedItem := m.List.SelectedItem().(styles.Item)
 				for _, v := range m.Versions {
 					if v.Version == selectedItem.Name && !v.Installed {
-						m.Loading = true
-						m.InstallingVersion = v.Version
-						m.Message = ""
-						return m, utils.DownloadAndInstall(v)
+						if v.Unsupported != "" {
+							m.Message = fmt.Sprintf("Go %s is not installable via govm: %s", v.Version, v.Unsupported)
+							m.MessageType = "error"
+							return m, nil
+						}
+						return m.install(v)
 					}
 				}
 			}
 		case "u":
+			if m.CurrentTab == 2 {
+				row := m.HistoryTable.SelectedRow()
+				if row == nil {
+					return m, nil
+				}
+				for _, v := range m.Versions {
+					if v.Version == row[1] && v.Installed {
+						m.Loading = true
+						m.Message = fmt.Sprintf("Switching to Go %s...", v.Version)
+						return m, utils.SwitchVersion(v)
+					}
+				}
+				m.Message = fmt.Sprintf("Go %s is no longer installed.", row[1])
+				m.MessageType = "error"
+				return m, nil
+			}
 			if m.CurrentTab == 0 {
 				selectedItem := m.List.SelectedItem().(styles.Item)
 				for _, v := range m.Versions {
@@ -73,10 +131,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.Message = "You need to install this version first. Press 'i' to install."
 				m.MessageType = "error"
 			}
+		case "z":
+			m.Compact = !m.Compact
+			m.applyDensity()
+			return m, nil
 		case "r":
-			m.Loading = true
+			m.refreshing = true
 			m.Message = ""
-			return m, utils.FetchGoVersions
+			return m, tea.Batch(
+				fetchRemoteVersions(utils.RefreshRemoteVersions),
+				utils.ScanInstalledVersions,
+				utils.DetectActiveVersion,
+				utils.LoadProjects,
+			)
 		case "d":
 			if m.CurrentTab == 0 || m.CurrentTab == 1 {
 				selectedItem := m.List.SelectedItem().(styles.Item)
@@ -129,127 +196,388 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	case tea.WindowSizeMsg:
 		h, v := styles.DocStyle.GetFrameSize()
-		m.List.SetSize(msg.Width-h, msg.Height-v-6)
-		m.InstalledTable.SetWidth(msg.Width - h)
-		m.InstalledTable.SetHeight(msg.Height - v - 10)
-		return m, nil
-	case utils.ErrMsg:
-		m.Err = msg
-		m.Loading = false
-		m.Message = msg.Error()
-		m.MessageType = "error"
-		return m, nil
-	case utils.VersionsMsg:
-		m.Versions = msg
-		items := make([]list.Item, len(m.Versions))
-		for i, v := range m.Versions {
-			items[i] = styles.Item{
-				Name:            v.Version,
-				DescriptionText: "go" + v.Version + " " + v.Filename,
-				Installed:       v.Installed,
-				Active:          v.Active,
-			}
+		listHeight := msg.Height - v - 6
+		if m.ListHeight > 0 && m.ListHeight < listHeight {
+			listHeight = m.ListHeight
 		}
-		m.List.SetItems(items)
-		m.Loading = false
-		m.updateInstalledTable()
+		tableHeight := msg.Height - v - 10
+		if m.TableHeight > 0 && m.TableHeight < tableHeight {
+			tableHeight = m.TableHeight
+		}
+		m.List.SetSize(msg.Width-h, listHeight)
+		m.InstalledTable.SetWidth(msg.Width - h)
+		m.InstalledTable.SetHeight(tableHeight)
+		m.HistoryTable.SetWidth(msg.Width - h)
+		m.HistoryTable.SetHeight(tableHeight)
+		m.ProjectsTable.SetWidth(msg.Width - h)
+		m.ProjectsTable.SetHeight(tableHeight)
 		return m, nil
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		m.Spinner, cmd = m.Spinner.Update(msg)
 		return m, cmd
-	case utils.DownloadCompleteMsg:
-		m.Loading = false
-		m.InstallingVersion = ""
-		for i, v := range m.Versions {
-			if v.Version == msg.Version {
-				m.Versions[i].Installed = true
-				m.Versions[i].Path = msg.Path
-				break
-			}
+	default:
+		if updated, cmd, ok := m.handleEvent(msg); ok {
+			return updated, cmd
 		}
-		items := m.List.Items()
-		for i, it := range items {
-			if it.(styles.Item).Name == msg.Version {
-				updatedItem := it.(styles.Item)
-				updatedItem.Installed = true
-				items[i] = updatedItem
-			}
-		}
-		m.List.SetItems(items)
-		m.updateInstalledTable()
-		m.Message = fmt.Sprintf("Successfully installed Go %s", msg.Version)
-		m.MessageType = "success"
+	}
+	newListModel, cmd := m.List.Update(msg)
+	m.List = newListModel
+	cmds = append(cmds, cmd)
+	newTableModel, tableCmd := m.InstalledTable.Update(msg)
+	m.InstalledTable = newTableModel
+	cmds = append(cmds, tableCmd)
+	newHistoryModel, historyCmd := m.HistoryTable.Update(msg)
+	m.HistoryTable = newHistoryModel
+	cmds = append(cmds, historyCmd)
+	newProjectsModel, projectsCmd := m.ProjectsTable.Update(msg)
+	m.ProjectsTable = newProjectsModel
+	cmds = append(cmds, projectsCmd)
+	return m, tea.Batch(cmds...)
+}
+
+// installProjectVersion installs the release the selected project pins when
+// no installed version satisfies it.
+func (m Model) installProjectVersion() (tea.Model, tea.Cmd) {
+	row := m.ProjectsTable.SelectedRow()
+	if row == nil {
 		return m, nil
-	case utils.SwitchCompletedMsg:
-		m.Loading = false
-		for i := range m.Versions {
-			m.Versions[i].Active = (m.Versions[i].Version == msg.Version)
-		}
-		items := m.List.Items()
-		for i, it := range items {
-			updatedItem := it.(styles.Item)
-			updatedItem.Active = (updatedItem.Name == msg.Version)
-			items[i] = updatedItem
-		}
-		m.List.SetItems(items)
-		m.updateInstalledTable()
-		if msg.ShimInPath {
-			m.Message = fmt.Sprintf("Switched to Go %s! Run 'go version' to verify.", msg.Version)
+	}
+	pin := row[2]
+	if installed := m.installedForPin(pin); installed != "" {
+		m.Message = fmt.Sprintf("Go %s is already installed for this project.", installed)
+		m.MessageType = "info"
+		return m, nil
+	}
+	v, ok := m.releaseForPin(pin)
+	if !ok {
+		if m.RemoteVersions == nil {
+			m.Message = "Still loading the available versions, try again in a moment."
 		} else {
-			m.Message = fmt.Sprintf("Switched to Go %s!\n\n%s",
-				msg.Version, utils.GetShimPathInstructions())
+			m.Message = fmt.Sprintf("No release matches Go %s.", pin)
 		}
-		m.MessageType = "success"
+		m.MessageType = "error"
 		return m, nil
-	case utils.DeleteCompleteMsg:
-		m.Loading = false
+	}
+	if v.Unsupported != "" {
+		m.Message = fmt.Sprintf("Go %s is not installable via govm: %s", v.Version, v.Unsupported)
+		m.MessageType = "error"
+		return m, nil
+	}
+	return m.install(v)
+}
 
-		for i, v := range m.Versions {
-			if v.Version == msg.Version {
-				m.Versions[i].Installed = false
-				m.Versions[i].Path = ""
-				break
+// installJob is a version queued for install from the TUI.
+type installJob struct {
+	version  utils.GoVersion
+	progress events.DownloadProgressMsg
+	// updates carries the install's progress reports; it is nil while the
+	// job waits for its turn
+	updates <-chan events.DownloadProgressMsg
+}
+
+// installFailedMsg reports that installing version failed.
+type installFailedMsg struct {
+	version string
+	err     error
+}
+
+// install queues v for install and starts it if fewer than
+// utils.DefaultInstallJobs installs are running.
+func (m Model) install(v utils.GoVersion) (tea.Model, tea.Cmd) {
+	if m.installIndex(v.Version) >= 0 {
+		m.Message = fmt.Sprintf("Go %s is already queued for install.", v.Version)
+		m.MessageType = "info"
+		return m, nil
+	}
+	m.installs = append(slices.Clone(m.installs), installJob{version: v})
+	m.Message = ""
+	return m, m.startQueuedInstalls()
+}
+
+// installIndex returns the index of version's job in installs, or -1.
+func (m Model) installIndex(version string) int {
+	return slices.IndexFunc(m.installs, func(job installJob) bool {
+		return job.version.Version == version
+	})
+}
+
+// startQueuedInstalls starts the queued installs that fit within
+// utils.DefaultInstallJobs running at once. Each downloads and installs in
+// the background, its progress arriving as DownloadProgressMsg values read
+// from its updates one at a time.
+func (m *Model) startQueuedInstalls() tea.Cmd {
+	running := 0
+	for _, job := range m.installs {
+		if job.updates != nil {
+			running++
+		}
+	}
+	var cmds []tea.Cmd
+	for i := range m.installs {
+		if running >= utils.DefaultInstallJobs {
+			break
+		}
+		if m.installs[i].updates != nil {
+			continue
+		}
+		running++
+		v := m.installs[i].version
+		progress := make(chan events.DownloadProgressMsg, 1)
+		m.installs[i].updates = progress
+		install := utils.DownloadAndInstallWithProgress(v, func(p events.DownloadProgressMsg) {
+			select {
+			case progress <- p:
+			default:
 			}
+		})
+		cmds = append(cmds, func() tea.Msg {
+			defer close(progress)
+			msg := install()
+			if err, ok := msg.(events.ErrMsg); ok {
+				return installFailedMsg{version: v.Version, err: err}
+			}
+			return msg
+		}, waitForDownloadProgress(progress))
+	}
+	return tea.Batch(cmds...)
+}
+
+// finishInstall takes version's job off the queue and starts the next
+// queued install in its place.
+func (m *Model) finishInstall(version string) tea.Cmd {
+	if i := m.installIndex(version); i >= 0 {
+		m.installs = slices.Delete(slices.Clone(m.installs), i, i+1)
+	}
+	return m.startQueuedInstalls()
+}
+
+// cachedRemoteVersionsMsg is the release list as last cached, shown at
+// once while the fresh one loads in the background. It is empty when
+// nothing is cached.
+type cachedRemoteVersionsMsg events.RemoteVersionsMsg
+
+func loadCachedRemoteVersions() tea.Msg {
+	versions, _ := utils.CachedRemoteVersions().(events.RemoteVersionsMsg)
+	return cachedRemoteVersionsMsg(versions)
+}
+
+// remoteVersionsFailedMsg reports that no release list could be loaded,
+// not even a cached one. Unlike other errors it leaves the TUI running on
+// the installed versions, which need no network.
+type remoteVersionsFailedMsg struct {
+	err error
+}
+
+// fetchRemoteVersions runs fetch, turning a failure into a
+// remoteVersionsFailedMsg.
+func fetchRemoteVersions(fetch tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		msg := fetch()
+		if err, ok := msg.(events.ErrMsg); ok {
+			return remoteVersionsFailedMsg{err: err}
 		}
+		return msg
+	}
+}
 
-		items := m.List.Items()
-		for i, it := range items {
-			if it.(styles.Item).Name == msg.Version {
-				updatedItem := it.(styles.Item)
-				updatedItem.Installed = false
-				items[i] = updatedItem
-			}
+// waitForDownloadProgress reads the next progress report of an install,
+// returning nil once the install has finished.
+func waitForDownloadProgress(progress <-chan events.DownloadProgressMsg) tea.Cmd {
+	return func() tea.Msg {
+		if p, ok := <-progress; ok {
+			return p
 		}
-		m.List.SetItems(items)
+		return nil
+	}
+}
 
-		m.updateInstalledTable()
+// installStatus is the status line shown while installs are queued. A
+// single install gets a progress bar; several share the line, each with
+// its own progress.
+func (m Model) installStatus() string {
+	if len(m.installs) == 1 {
+		job := m.installs[0]
+		p := job.progress
+		if p.Downloaded == 0 || p.Downloaded == p.Total {
+			return fmt.Sprintf("%s [installing Go %s]", m.Spinner.View(), job.version.Version)
+		}
+		if percent := p.Percent(); percent >= 0 {
+			const width = 20
+			filled := int(percent * width / 100)
+			bar := strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+			return fmt.Sprintf("%s [downloading Go %s] %s %s", m.Spinner.View(), job.version.Version, bar, p)
+		}
+		return fmt.Sprintf("%s [downloading Go %s] %s", m.Spinner.View(), job.version.Version, p)
+	}
+	parts := make([]string, len(m.installs))
+	for i, job := range m.installs {
+		p := job.progress
+		status := ""
+		switch {
+		case job.updates == nil:
+			status = "queued"
+		case p.Downloaded == 0:
+			status = "starting"
+		case p.Downloaded == p.Total:
+			status = "extracting"
+		case p.Percent() >= 0:
+			status = fmt.Sprintf("%.0f%%", p.Percent())
+		default:
+			status = events.FormatBytes(p.Downloaded)
+		}
+		parts[i] = fmt.Sprintf("%s %s", job.version.Version, status)
+	}
+	return fmt.Sprintf("%s [installing %d versions] %s", m.Spinner.View(), len(m.installs), strings.Join(parts, " · "))
+}
 
-		m.Message = fmt.Sprintf("Successfully deleted Go %s", msg.Version)
-		m.MessageType = "success"
-		return m, nil
+// installedForPin returns the installed version the shims would run for pin:
+// an exact match, else the newest installed patch of that line.
+func (m *Model) installedForPin(pin string) string {
+	if _, ok := m.InstalledPaths[pin]; ok {
+		return pin
 	}
-	newListModel, cmd := m.List.Update(msg)
-	m.List = newListModel
-	cmds = append(cmds, cmd)
-	newTableModel, tableCmd := m.InstalledTable.Update(msg)
-	m.InstalledTable = newTableModel
-	cmds = append(cmds, tableCmd)
-	return m, tea.Batch(cmds...)
+	best := ""
+	for version := range m.InstalledPaths {
+		if strings.HasPrefix(version, pin+".") && !utils.IsPreRelease(version) &&
+			(best == "" || utils.CompareVersions(version, best) > 0) {
+			best = version
+		}
+	}
+	return best
+}
+
+// releaseForPin returns the release to install for pin: an exact match, else
+// the newest final release of that line.
+func (m *Model) releaseForPin(pin string) (utils.GoVersion, bool) {
+	var best utils.GoVersion
+	found := false
+	for _, v := range m.Versions {
+		if v.Version == pin {
+			return v, true
+		}
+		if strings.HasPrefix(v.Version, pin+".") && !utils.IsPreRelease(v.Version) &&
+			(!found || utils.CompareVersions(v.Version, best.Version) > 0) {
+			best, found = v, true
+		}
+	}
+	return best, found
+}
+
+// applyDensity shows or hides version descriptions in the list.
+func (m *Model) applyDensity() {
+	m.Delegate.ShowDescription = !m.Compact
+	if m.Compact {
+		m.Delegate.SetSpacing(0)
+	} else {
+		m.Delegate.SetSpacing(1)
+	}
+	m.List.SetDelegate(m.Delegate)
+}
+
+// mergeVersions rebuilds the version list from whichever of the remote
+// list, installed scan and active version have arrived so far. Until the
+// cached or fresh release list arrives only the installed versions are
+// shown.
+func (m *Model) mergeVersions() {
+	if m.RemoteVersions != nil {
+		m.setVersions(utils.MergeVersions(m.RemoteVersions, m.InstalledPaths, m.ActiveVersion))
+		return
+	}
+	versions := []utils.GoVersion{}
+	for version, path := range m.InstalledPaths {
+		versions = append(versions, utils.GoVersion{
+			Version:   version,
+			Path:      path,
+			Installed: true,
+			Active:    version == m.ActiveVersion,
+		})
+	}
+	utils.SortVersions(versions)
+	m.setVersions(versions)
+}
+
+func (m *Model) setVersions(versions []utils.GoVersion) {
+	m.Versions = versions
+	items := make([]list.Item, len(m.Versions))
+	for i, v := range m.Versions {
+		description := "go" + v.Version + " " + v.Filename
+		if v.Unsupported != "" {
+			description = "not installable via govm: " + v.Unsupported
+		}
+		if v.Version == utils.TipVersion {
+			description = "unstable build from the Go master branch"
+		}
+		items[i] = styles.Item{
+			Name:            v.Version,
+			DescriptionText: description,
+			Installed:       v.Installed,
+			Active:          v.Active,
+		}
+	}
+	// Keep the cursor on the same version when a refresh reorders the list
+	selected, _ := m.List.SelectedItem().(styles.Item)
+	m.List.SetItems(items)
+	if m.List.FilterState() == list.Unfiltered {
+		for i, v := range m.Versions {
+			if v.Version == selected.Name {
+				m.List.Select(i)
+				break
+			}
+		}
+	}
+	m.updateInstalledTable()
+}
+
+func (m *Model) updateHistoryTable() {
+	rows := []table.Row{}
+	for _, record := range m.History {
+		rows = append(rows, table.Row{utils.FormatTimestamp(record.Time, m.ISOTime), record.Version})
+	}
+	m.HistoryTable.SetRows(rows)
 }
 
 func (m *Model) updateInstalledTable() {
 	rows := []table.Row{}
+	lastUsed := utils.LastUsed(m.History)
 	for _, v := range m.Versions {
 		if v.Installed {
 			status := ""
 			if v.Active {
 				status = "active"
 			}
-			rows = append(rows, table.Row{v.Version, v.Path, status})
+			installed, used := "", ""
+			if at, ok := utils.InstalledAt(v.Path); ok {
+				installed = utils.FormatTimestamp(at, m.ISOTime)
+			}
+			if at, ok := lastUsed[v.Version]; ok {
+				used = utils.FormatTimestamp(at, m.ISOTime)
+			}
+			rows = append(rows, table.Row{v.Version, v.Path, status, installed, used})
 		}
 	}
 	m.InstalledTable.SetRows(rows)
+	// Whether a project's pin is satisfied depends on what is installed
+	m.updateProjectsTable()
+}
+
+func (m *Model) updateProjectsTable() {
+	rows := []table.Row{}
+	for _, p := range m.Projects {
+		dir := p.Dir
+		if rel, err := filepath.Rel(m.HomeDir, dir); err == nil && !strings.HasPrefix(rel, "..") {
+			dir = filepath.Join("~", rel)
+		}
+		status := "missing"
+		if installed := m.installedForPin(p.Version); installed != "" {
+			status = "installed"
+			if installed != p.Version {
+				status += " (" + installed + ")"
+			}
+		}
+		rows = append(rows, table.Row{dir, filepath.Base(p.PinFile), p.Version, status})
+	}
+	m.ProjectsTable.SetRows(rows)
 }
 
 func (m Model) View() string {
@@ -268,10 +596,9 @@ func (m Model) View() string {
 			Padding(1, 2).
 			Align(lipgloss.Left)
 		instructions := utils.GetShimPathInstructions()
-		warningBanner := warningStyle.Render("⚠️  GoVM is not in your PATH  ⚠️\n\n" + instructions)
+		warningBanner := warningStyle.Render(term.Plain("⚠️  GoVM is not in your PATH  ⚠️\n\n") + instructions)
 		components = append(components, warningBanner)
 	}
-	tabs := []string{"Available Versions", "Installed Versions"}
 	tabContent := ""
 	for i, tab := range tabs {
 		if i == m.CurrentTab {
@@ -282,21 +609,37 @@ func (m Model) View() string {
 	}
 	components = append(components, tabContent)
 	if m.CurrentTab == 0 {
+		if !m.staleIndexAt.IsZero() {
+			cached := utils.RelativeTime(m.staleIndexAt, clock.Now())
+			banner := fmt.Sprintf("⚠️  Could not refresh the release list: showing the copy cached %s, which may be stale", cached)
+			if m.offline {
+				banner = fmt.Sprintf("📴 Offline: showing the release list cached %s", cached)
+			}
+			components = append(components, styles.ErrorStyle.Render(term.Plain(banner)))
+		}
 		listView := m.List.View()
 		components = append(components, listView)
-		if m.Loading {
-			spinnerDisplay := ""
-			if m.InstallingVersion != "" {
-				progressBar := fmt.Sprintf("[downloading Go %s]", m.InstallingVersion)
-				spinnerDisplay = fmt.Sprintf("%s %s", m.Spinner.View(), progressBar)
-			} else {
-				spinnerDisplay = fmt.Sprintf("%s Loading versions...", m.Spinner.View())
-			}
-			components = append(components, spinnerDisplay)
+		if len(m.installs) > 0 {
+			components = append(components, m.installStatus())
+		} else if m.Loading {
+			components = append(components, fmt.Sprintf("%s Loading versions...", m.Spinner.View()))
+		} else if m.refreshing {
+			components = append(components, styles.HelpStyle(fmt.Sprintf("%s Refreshing the release list...", m.Spinner.View())))
 		}
-	} else {
+	} else if m.CurrentTab == 1 {
 		tableView := m.InstalledTable.View()
 		components = append(components, tableView)
+	} else if m.CurrentTab == 2 {
+		components = append(components, m.HistoryTable.View())
+	} else {
+		if len(m.Projects) == 0 {
+			components = append(components, "No projects yet. GoVM lists directories here once it finds a .go-version,\n.tool-versions, mise.toml or go.mod pin in them, e.g. through govm pin, govm run\nor the cd hook.")
+		} else {
+			components = append(components, m.ProjectsTable.View())
+		}
+		if len(m.installs) > 0 {
+			components = append(components, m.installStatus())
+		}
 	}
 	if m.Message != "" {
 		if m.MessageType == "success" {
@@ -306,9 +649,13 @@ func (m Model) View() string {
 		}
 	}
 	if m.CurrentTab == 0 {
-		components = append(components, styles.HelpStyle("\nPress 'i' to install, 'u' to use/switch, 'd' to delete, 'r' to refresh, 'tab' to switch tabs, 'q' to quit"))
-	} else {
+		components = append(components, styles.HelpStyle("\nPress 'i' to install, 'u' to use/switch, 'd' to delete, 'r' to refresh, 'z' for compact view, 'tab' to switch tabs, 'q' to quit"))
+	} else if m.CurrentTab == 1 {
 		components = append(components, styles.HelpStyle("\nPress 'u' to use/switch, 'd' to delete, 'tab' to switch tabs, 'q' to quit"))
+	} else if m.CurrentTab == 2 {
+		components = append(components, styles.HelpStyle("\nPress 'u' to switch back to the selected version, 'tab' to switch tabs, 'q' to quit"))
+	} else {
+		components = append(components, styles.HelpStyle("\nPress 'i' to install the selected project's missing version, 'r' to refresh, 'tab' to switch tabs, 'q' to quit"))
 	}
 	return styles.AppStyle.Render(lipgloss.JoinVertical(lipgloss.Left, components...))
 }
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/melkeydev/govm/internal/events"
+	"github.com/melkeydev/govm/internal/utils"
+)
+
+func TestInstallQueue(t *testing.T) {
+	m := Model{List: list.New(nil, list.NewDefaultDelegate(), 80, 20)}
+	versions := make([]string, utils.DefaultInstallJobs+2)
+	for i := range versions {
+		versions[i] = fmt.Sprintf("1.2%d.0", i)
+		next, _ := m.install(utils.GoVersion{Version: versions[i]})
+		m = next.(Model)
+	}
+	running := func() []string {
+		var started []string
+		for _, job := range m.installs {
+			if job.updates != nil {
+				started = append(started, job.version.Version)
+			}
+		}
+		return started
+	}
+	if got := running(); len(got) != utils.DefaultInstallJobs {
+		t.Fatalf("running = %v, want the first %d", got, utils.DefaultInstallJobs)
+	}
+
+	// Queueing a version twice does not install it twice
+	next, _ := m.install(utils.GoVersion{Version: versions[0]})
+	if got := len(next.(Model).installs); got != len(versions) {
+		t.Errorf("queue has %d installs after a repeat, want %d", got, len(versions))
+	}
+
+	// A finished install and a failed one each make room for the next
+	m, _, _ = m.handleEvent(events.DownloadCompleteMsg{Version: versions[0], Path: "/tmp/go"})
+	m, _, _ = m.handleEvent(installFailedMsg{version: versions[1], err: errors.New("boom")})
+	if got := running(); len(got) != utils.DefaultInstallJobs || got[len(got)-1] != versions[len(versions)-1] {
+		t.Errorf("running = %v, want the last %d queued", got, utils.DefaultInstallJobs)
+	}
+	if !strings.Contains(m.Message, versions[1]) || m.MessageType != "error" {
+		t.Errorf("message = %q (%s), want %s's failure", m.Message, m.MessageType, versions[1])
+	}
+	if m.InstalledPaths[versions[0]] != "/tmp/go" {
+		t.Errorf("InstalledPaths = %v, want %s installed", m.InstalledPaths, versions[0])
+	}
+
+	m, _, _ = m.handleEvent(events.DownloadProgressMsg{Version: versions[2], Downloaded: 50, Total: 100})
+	status := m.installStatus()
+	for _, want := range []string{"installing 4 versions", versions[2] + " 50%", versions[3] + " starting"} {
+		if !strings.Contains(status, want) {
+			t.Errorf("status %q does not mention %q", status, want)
+		}
+	}
+}
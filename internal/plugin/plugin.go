@@ -0,0 +1,154 @@
+// Package plugin is the extension point for companion tools (golangci-lint,
+// buf, ...) that GoVM installs, pins and shims next to Go itself.
+package plugin
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ToolPinFile is the per-project file listing companion tool versions, one
+// "<tool> <version>" pair per line.
+const ToolPinFile = ".govm-tools"
+
+// externalPrefix is the executable name prefix of out-of-tree plugins, e.g.
+// govm-plugin-sqlc.
+const externalPrefix = "govm-plugin-"
+
+// Tool installs versions of a companion tool.
+type Tool interface {
+	Name() string
+	// Install places the given version of the tool in dir.
+	Install(version, dir string) error
+	// Binaries lists the executables an install provides, relative to dir.
+	Binaries() []string
+}
+
+var registry = map[string]Tool{}
+
+// Register makes a tool available to the tool commands.
+func Register(t Tool) {
+	registry[t.Name()] = t
+}
+
+// Lookup returns the registered tool with the given name, falling back to an
+// external govm-plugin-<name> executable on PATH.
+func Lookup(name string) (Tool, error) {
+	if t, ok := registry[name]; ok {
+		return t, nil
+	}
+	if path, err := exec.LookPath(externalPrefix + name); err == nil {
+		return externalTool{name: name, path: path}, nil
+	}
+	return nil, fmt.Errorf("no plugin found for %s (expected a built-in tool or %s%s on PATH)", name, externalPrefix, name)
+}
+
+// Names returns the names of all built-in tools.
+func Names() []string {
+	var names []string
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ToolsDir returns where installed versions of a tool live.
+func ToolsDir(name string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %v", err)
+	}
+	return filepath.Join(homeDir, ".govm", "tools", name), nil
+}
+
+// ReadPins walks up from dir to the nearest tool pin file and returns the
+// tool versions it lists along with its path.
+func ReadPins(dir string) (map[string]string, string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, "", err
+	}
+	for {
+		pinFile := filepath.Join(dir, ToolPinFile)
+		if f, err := os.Open(pinFile); err == nil {
+			defer f.Close()
+			pins := map[string]string{}
+			scanner := bufio.NewScanner(f)
+			for scanner.Scan() {
+				line := strings.TrimSpace(scanner.Text())
+				if line == "" || strings.HasPrefix(line, "#") {
+					continue
+				}
+				fields := strings.Fields(line)
+				if len(fields) != 2 {
+					return nil, "", fmt.Errorf("invalid line in %s: %q", pinFile, line)
+				}
+				pins[fields[0]] = strings.TrimPrefix(fields[1], "v")
+			}
+			return pins, pinFile, scanner.Err()
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, "", fmt.Errorf("no %s found", ToolPinFile)
+		}
+		dir = parent
+	}
+}
+
+// goInstallTool is a tool installed with 'go install <module>@v<version>'
+// using the active Go toolchain.
+type goInstallTool struct {
+	name   string
+	module string
+}
+
+func (t goInstallTool) Name() string { return t.name }
+
+func (t goInstallTool) Install(version, dir string) error {
+	cmd := exec.Command("go", "install", fmt.Sprintf("%s@v%s", t.module, version))
+	cmd.Env = append(os.Environ(), "GOBIN="+filepath.Join(dir, "bin"))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("go install failed: %v\nOutput: %s", err, output)
+	}
+	return nil
+}
+
+func (t goInstallTool) Binaries() []string {
+	return []string{filepath.Join("bin", t.name)}
+}
+
+// externalTool delegates to a govm-plugin-<name> executable, which is called
+// as '<plugin> install <version> <dir>' and '<plugin> binaries'.
+type externalTool struct {
+	name string
+	path string
+}
+
+func (t externalTool) Name() string { return t.name }
+
+func (t externalTool) Install(version, dir string) error {
+	cmd := exec.Command(t.path, "install", version, dir)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s install failed: %v\nOutput: %s", t.path, err, output)
+	}
+	return nil
+}
+
+func (t externalTool) Binaries() []string {
+	output, err := exec.Command(t.path, "binaries").Output()
+	if err != nil {
+		return []string{filepath.Join("bin", t.name)}
+	}
+	return strings.Fields(string(output))
+}
+
+func init() {
+	Register(goInstallTool{name: "golangci-lint", module: "github.com/golangci/golangci-lint/cmd/golangci-lint"})
+	Register(goInstallTool{name: "buf", module: "github.com/bufbuild/buf/cmd/buf"})
+}
@@ -0,0 +1,145 @@
+// Package term decides how decorated govm's output may be. Every command
+// prints through it so that emoji, colors and spinners follow the same
+// policy: on by default, off when the user or the terminal asks for plain
+// output.
+package term
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-isatty"
+	"github.com/muesli/termenv"
+)
+
+var (
+	// Emoji reports whether output may contain emoji and other pictographs.
+	Emoji = true
+	// Color reports whether output may contain ANSI colors.
+	Color = true
+	// Quiet suppresses progress messages and hints, leaving only results,
+	// warnings and errors.
+	Quiet = false
+	// Verbose adds details of the underlying operations, such as URLs,
+	// paths and external commands.
+	Verbose = false
+	// Stdout is where Printf, Println and Print write. Machine-readable
+	// output modes point it at stderr so human-oriented text never mixes
+	// with the data on stdout.
+	Stdout io.Writer = os.Stdout
+)
+
+// Init sets the output policy from the command-line switches and the
+// environment: NO_COLOR disables colors, GOVM_NO_EMOJI disables emoji and
+// TERM=dumb disables both.
+func Init(noEmoji, noColor bool) {
+	dumb := os.Getenv("TERM") == "dumb"
+	Emoji = !noEmoji && os.Getenv("GOVM_NO_EMOJI") == "" && !dumb
+	Color = !noColor && os.Getenv("NO_COLOR") == "" && !dumb
+	if !Color {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
+
+// Interactive reports whether stdin is a terminal, so that a question can
+// be answered.
+func Interactive() bool {
+	fd := os.Stdin.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// SpinnerFrames returns the animation frames for progress spinners.
+func SpinnerFrames() []string {
+	if Emoji {
+		return []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	}
+	return []string{"|", "/", "-", `\`}
+}
+
+// Printf is fmt.Printf following the output policy.
+func Printf(format string, a ...any) {
+	fmt.Fprint(Stdout, Plain(fmt.Sprintf(format, a...)))
+}
+
+// Println is fmt.Println following the output policy.
+func Println(a ...any) {
+	fmt.Fprint(Stdout, Plain(fmt.Sprintln(a...)))
+}
+
+// Print is fmt.Print following the output policy.
+func Print(a ...any) {
+	fmt.Fprint(Stdout, Plain(fmt.Sprint(a...)))
+}
+
+// Infof prints a progress message or hint with Printf, unless quiet output
+// was requested.
+func Infof(format string, a ...any) {
+	if !Quiet {
+		Printf(format, a...)
+	}
+}
+
+// Infoln is Println for progress messages and hints.
+func Infoln(a ...any) {
+	if !Quiet {
+		Println(a...)
+	}
+}
+
+// Debugf prints a detail of an underlying operation when verbose output was
+// requested. Details go to stderr so they never mix with results.
+func Debugf(format string, a ...any) {
+	if Verbose {
+		fmt.Fprint(os.Stderr, Plain(fmt.Sprintf("   » "+format+"\n", a...)))
+	}
+}
+
+// Fprintf is fmt.Fprintf following the output policy.
+func Fprintf(w io.Writer, format string, a ...any) {
+	fmt.Fprint(w, Plain(fmt.Sprintf(format, a...)))
+}
+
+// Fprintln is fmt.Fprintln following the output policy.
+func Fprintln(w io.Writer, a ...any) {
+	fmt.Fprint(w, Plain(fmt.Sprintln(a...)))
+}
+
+// Plain removes emoji from s when they are disabled, along with the spacing
+// that followed them, so "✅ Done" becomes "Done". It returns s unchanged
+// otherwise.
+func Plain(s string) string {
+	if Emoji {
+		return s
+	}
+	var b strings.Builder
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		if !isPictograph(runes[i]) {
+			b.WriteRune(runes[i])
+			continue
+		}
+		for i+1 < len(runes) && (runes[i+1] == '\uFE0F' || runes[i+1] == ' ') {
+			i++
+		}
+	}
+	return b.String()
+}
+
+func isPictograph(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // emoji
+		return true
+	case r >= 0x2300 && r <= 0x23FF: // technical symbols such as ⏪
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // miscellaneous symbols and dingbats
+		return true
+	case r >= 0x2800 && r <= 0x28FF: // braille spinner frames
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF, r == 0x2139:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// VersionKeywords name releases by their role rather than their number, so
+// scripts don't hardcode versions that go stale:
+//
+//	latest     the newest stable release
+//	oldstable  the newest release of the minor line before latest's
+//	stable     the newest stable patch of the active version's minor line
+var VersionKeywords = []string{"latest", "stable", "oldstable"}
+
+// isReservedName reports whether name already means something wherever a
+// version is accepted, so an alias by that name would be ignored.
+func isReservedName(name string) bool {
+	return name == TipVersion || slices.Contains(VersionKeywords, name)
+}
+
+func aliasDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %v", err)
+	}
+	return filepath.Join(homeDir, ".govm", "aliases"), nil
+}
+
+// ResolveAlias returns the version an alias points to, or name unchanged if
+// it is not an alias.
+func ResolveAlias(name string) string {
+	dir, err := aliasDir()
+	if err != nil || strings.ContainsAny(name, `/\`) || isReservedName(name) {
+		return name
+	}
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return name
+	}
+	return strings.TrimSpace(string(content))
+}
+
+// SetAlias creates or updates an alias.
+func SetAlias(name, version string) error {
+	if name == "" || strings.ContainsAny(name, `/\.`) || strings.HasPrefix(name, "-") {
+		return fmt.Errorf("invalid alias name %q", name)
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		return fmt.Errorf("alias %q would shadow a version number", name)
+	}
+	if isReservedName(name) {
+		return fmt.Errorf("%q is a reserved version name and cannot be an alias", name)
+	}
+	dir, err := aliasDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create aliases directory: %v", err)
+	}
+	return os.WriteFile(filepath.Join(dir, name), []byte(version), 0644)
+}
+
+// RemoveAlias deletes an alias.
+func RemoveAlias(name string) error {
+	dir, err := aliasDir()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(dir, name)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("alias %q does not exist", name)
+		}
+		return err
+	}
+	return nil
+}
+
+// ListAliases returns all aliases mapped to their versions, with the names
+// in sorted order.
+func ListAliases() ([]string, map[string]string, error) {
+	dir, err := aliasDir()
+	if err != nil {
+		return nil, nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("failed to read aliases directory: %v", err)
+	}
+	var names []string
+	aliases := map[string]string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		names = append(names, entry.Name())
+		aliases[entry.Name()] = ResolveAlias(entry.Name())
+	}
+	sort.Strings(names)
+	return names, aliases, nil
+}
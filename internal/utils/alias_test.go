@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetAlias(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	tests := []struct {
+		name string
+		ok   bool
+	}{
+		{"lts", true},
+		{"work-1", true},
+		{"latest", false},
+		{"stable", false},
+		{"oldstable", false},
+		{"tip", false},
+		{"1.22", false},
+		{"../escape", false},
+		{"-", false},
+	}
+	for _, tt := range tests {
+		if err := SetAlias(tt.name, "1.21.13"); (err == nil) != tt.ok {
+			t.Errorf("SetAlias(%q) error = %v, want ok: %v", tt.name, err, tt.ok)
+		}
+	}
+	if got := ResolveAlias("lts"); got != "1.21.13" {
+		t.Errorf("ResolveAlias(lts) = %q", got)
+	}
+	// An alias by a reserved name left from an older govm is ignored
+	if err := os.WriteFile(filepath.Join(home, ".govm", "aliases", "latest"), []byte("1.20.0"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := ResolveAlias("latest"); got != "latest" {
+		t.Errorf("ResolveAlias(latest) = %q, want the keyword unchanged", got)
+	}
+}
@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+var archiveExtensions = []string{".tar.gz", ".zip"}
+
+// minInstallableMinor is the oldest Go 1.x release govm can install. Earlier
+// toolchains were built with C compilers, ship a different pkg/tool layout
+// and only run from the GOROOT they were built for.
+const minInstallableMinor = 5
+
+// UnsupportedReason returns why govm cannot install the given release, or an
+// empty string when it can.
+func UnsupportedReason(version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 || parts[0] != "1" {
+		return ""
+	}
+	minor := parts[1]
+	// Pre-releases such as 1.4beta1 carry their suffix on the minor part
+	if i := strings.IndexFunc(minor, func(r rune) bool { return r < '0' || r > '9' }); i >= 0 {
+		minor = minor[:i]
+	}
+	if n, err := strconv.Atoi(minor); err == nil && n < minInstallableMinor {
+		return fmt.Sprintf("releases before Go 1.%d use a toolchain layout govm does not support", minInstallableMinor)
+	}
+	return ""
+}
+
+// IsArchiveReference reports whether the install argument names a release
+// archive (e.g. go1.22.4.linux-amd64.tar.gz) or a URL rather than a version.
+func IsArchiveReference(arg string) bool {
+	if strings.HasPrefix(arg, "https://") || strings.HasPrefix(arg, "http://") {
+		return true
+	}
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(arg, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseArchiveReference turns an archive filename or URL into a GoVersion
+// that can be passed to DownloadAndInstall. The archive must target the
+// current platform.
+func ParseArchiveReference(ref string) (GoVersion, error) {
+	downloadURL := ""
+	filename := ref
+	if strings.HasPrefix(ref, "https://") || strings.HasPrefix(ref, "http://") {
+		u, err := url.Parse(ref)
+		if err != nil {
+			return GoVersion{}, fmt.Errorf("invalid URL %q: %v", ref, err)
+		}
+		downloadURL = ref
+		filename = path.Base(u.Path)
+	}
+	version, goos, goarch, err := parseArchiveFilename(filename)
+	if err != nil {
+		return GoVersion{}, err
+	}
+	if goos != runtime.GOOS || goarch != runtime.GOARCH {
+		return GoVersion{}, fmt.Errorf("archive %s is for %s/%s, but this machine is %s/%s",
+			filename, goos, goarch, runtime.GOOS, runtime.GOARCH)
+	}
+	if downloadURL == "" {
+		downloadURL = "https://go.dev/dl/" + filename
+	}
+	return GoVersion{
+		Version:     version,
+		Filename:    filename,
+		URL:         downloadURL,
+		Stable:      !strings.Contains(version, "rc") && !strings.Contains(version, "beta"),
+		Unsupported: UnsupportedReason(version),
+	}, nil
+}
+
+// parseArchiveFilename splits go<version>.<os>-<arch>.<ext> into its parts.
+func parseArchiveFilename(filename string) (version, goos, goarch string, err error) {
+	name := filename
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(name, ext) {
+			name = strings.TrimSuffix(name, ext)
+			break
+		}
+	}
+	if name == filename || !strings.HasPrefix(name, "go") {
+		return "", "", "", fmt.Errorf("unrecognized release archive name: %s", filename)
+	}
+	name = strings.TrimPrefix(name, "go")
+	dot := strings.LastIndex(name, ".")
+	if dot < 0 {
+		return "", "", "", fmt.Errorf("unrecognized release archive name: %s", filename)
+	}
+	version = name[:dot]
+	platform := strings.SplitN(name[dot+1:], "-", 2)
+	if version == "" || len(platform) != 2 {
+		return "", "", "", fmt.Errorf("unrecognized release archive name: %s", filename)
+	}
+	return version, platform[0], platform[1], nil
+}
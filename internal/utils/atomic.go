@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// writeFileAtomic writes data to path through a temporary file in the same
+// directory that is renamed over it, so a reader running at the same time
+// sees either the old content or the new, never part of it.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	return writeFileAtomicAt(path, data, perm, time.Time{})
+}
+
+// writeFileAtomicAt is writeFileAtomic, also setting the file's mtime to
+// modTime, unless it is zero, before the file replaces path.
+func writeFileAtomicAt(path string, data []byte, perm os.FileMode, modTime time.Time) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), perm)
+	}
+	if err == nil && !modTime.IsZero() {
+		err = os.Chtimes(tmp.Name(), modTime, modTime)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
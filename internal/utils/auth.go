@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"bufio"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/config"
+	"github.com/melkeydev/govm/internal/term"
+)
+
+// authorize adds credentials for the mirror req goes to. Credentials in the
+// URL itself win; then the download.auth setting, sent only to the hosts of
+// download.base_url and download.mirrors; then a matching ~/.netrc entry,
+// falling back to its default entry only for those same hosts.
+// Secrets are never logged.
+func authorize(req *http.Request) {
+	if req.URL.User != nil {
+		return
+	}
+	cfg, _ := config.Load()
+	if auth := cfg.Download.Auth; (auth.Header != "" || auth.Username != "") && configuredHost(cfg.Download, req.URL.Host) {
+		if auth.Header != "" {
+			req.Header.Set("Authorization", os.ExpandEnv(auth.Header))
+		} else {
+			req.SetBasicAuth(os.ExpandEnv(auth.Username), os.ExpandEnv(auth.Password))
+		}
+		term.Debugf("sending the download.auth credentials to %s", req.URL.Host)
+		return
+	}
+	useDefault := configuredHost(cfg.Download, req.URL.Host) && !officialHost(req.URL.Hostname())
+	if login, password, path, ok := netrcCredentials(req.URL.Hostname(), useDefault); ok {
+		req.SetBasicAuth(login, password)
+		term.Debugf("sending the credentials for %s from %s", req.URL.Hostname(), path)
+	}
+}
+
+// configuredHost reports whether host is that of download.base_url or one
+// of download.mirrors, the only places the download.auth credentials go.
+func configuredHost(cfg config.DownloadConfig, host string) bool {
+	for _, mirror := range append([]string{cfg.BaseURL}, cfg.Mirrors...) {
+		if u, err := url.Parse(strings.TrimSpace(mirror)); err == nil && u.Host != "" && strings.EqualFold(u.Host, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// officialHost reports whether host belongs to go.dev or golang.org, which
+// never need credentials and so are never sent a netrc default entry.
+func officialHost(host string) bool {
+	host = strings.ToLower(host)
+	for _, domain := range []string{"go.dev", "golang.org"} {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// netrcPath returns the netrc file curl and the go command read: $NETRC, or
+// ~/.netrc (~/_netrc on Windows).
+func netrcPath() string {
+	if path := os.Getenv("NETRC"); path != "" {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(homeDir, "_netrc")
+	}
+	return filepath.Join(homeDir, ".netrc")
+}
+
+// netrcCredentials returns the login and password the netrc file gives for
+// host, falling back to its default entry when useDefault is set.
+func netrcCredentials(host string, useDefault bool) (login, password, path string, ok bool) {
+	path = netrcPath()
+	f, err := os.Open(path)
+	if err != nil {
+		return "", "", path, false
+	}
+	defer f.Close()
+
+	type entry struct{ login, password string }
+	var (
+		match, fallback *entry
+		current         *entry
+		inMacro         bool
+	)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		// A macro definition runs until the next blank line
+		if inMacro {
+			inMacro = strings.TrimSpace(line) != ""
+			continue
+		}
+		fields := strings.Fields(line)
+		for i := 0; i < len(fields); i++ {
+			switch fields[i] {
+			case "machine":
+				current = nil
+				if i+1 < len(fields) {
+					i++
+					if match == nil && strings.EqualFold(fields[i], host) {
+						match = &entry{}
+						current = match
+					}
+				}
+			case "default":
+				current = nil
+				if fallback == nil {
+					fallback = &entry{}
+					current = fallback
+				}
+			case "login", "password", "account":
+				if i+1 < len(fields) {
+					i++
+					if current != nil && fields[i-1] == "login" {
+						current.login = fields[i]
+					} else if current != nil && fields[i-1] == "password" {
+						current.password = fields[i]
+					}
+				}
+			case "macdef":
+				current = nil
+				inMacro = true
+				i = len(fields)
+			}
+		}
+	}
+	if match == nil && useDefault {
+		match = fallback
+	}
+	if match == nil || match.login == "" {
+		return "", "", path, false
+	}
+	return match.login, match.password, path, true
+}
+
+// redactURL hides any password in a URL before it is printed.
+func redactURL(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		return u.Redacted()
+	}
+	return rawURL
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAuthorizeNetrcDefault(t *testing.T) {
+	home := testHome(t, map[string]any{"mirrors": []string{"https://mirror.test/dl/", "https://go.dev/dl/"}})
+	netrc := filepath.Join(home, ".netrc")
+	t.Setenv("NETRC", netrc)
+	content := "machine named.test login alice password one\ndefault login bob password two\n"
+	if err := os.WriteFile(netrc, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		url, wantUser string
+	}{
+		{"https://named.test/go.tar.gz", "alice"},
+		{"https://mirror.test/dl/go.tar.gz", "bob"},
+		// The default entry never goes to a host nobody configured, nor to
+		// go.dev even when it is listed as a mirror
+		{"https://elsewhere.test/go.tar.gz", ""},
+		{"https://go.dev/dl/go.tar.gz", ""},
+		{"https://dl.golang.org/go.tar.gz", ""},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest("GET", tt.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		authorize(req)
+		if user, _, _ := req.BasicAuth(); user != tt.wantUser {
+			t.Errorf("%s: sent user %q, want %q", tt.url, user, tt.wantUser)
+		}
+	}
+}
@@ -2,16 +2,15 @@ package utils
 
 import "runtime"
 
-type ErrMsg error
-
-type VersionsMsg []GoVersion
+var goBinary = "go"
 
-type DeleteCompleteMsg struct {
-	Version string
+// archiveMirrors are the historical locations release archives were served
+// from, tried in order when go.dev no longer has a file.
+var archiveMirrors = []string{
+	"https://dl.google.com/go/",
+	"https://storage.googleapis.com/golang/",
 }
 
-var goBinary = "go"
-
 func init() {
 	if runtime.GOOS == "windows" {
 		goBinary = "go.exe"
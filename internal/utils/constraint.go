@@ -0,0 +1,182 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// VersionConstraint is a range of Go versions such as "~1.22" (any 1.22.x),
+// "^1.21" (1.21.0 or newer within Go 1), ">=1.21 <1.23" or "1.22.x".
+type VersionConstraint struct {
+	text   string
+	bounds []versionBound
+}
+
+type versionBound struct {
+	op      string
+	version string
+}
+
+var constraintOps = []string{">=", "<=", "!=", ">", "<", "="}
+
+// IsVersionConstraint reports whether s is a range rather than a plain, possibly
+// partial, version.
+func IsVersionConstraint(s string) bool {
+	if strings.ContainsAny(s, "~^<>=!* ,") {
+		return true
+	}
+	for _, part := range strings.Split(s, ".") {
+		if part == "x" || part == "X" {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseVersionConstraint parses a range. Bounds are separated by spaces or
+// commas and must all hold. A bare version inside a range, like 1.22, means
+// the whole 1.22 line.
+func ParseVersionConstraint(s string) (VersionConstraint, error) {
+	c := VersionConstraint{text: s}
+	var tokens []string
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' }) {
+		// Allow a space between an operator and its version, as in ">= 1.21"
+		if n := len(tokens); n > 0 && isConstraintOp(tokens[n-1]) {
+			tokens[n-1] += field
+			continue
+		}
+		tokens = append(tokens, field)
+	}
+	if len(tokens) == 0 {
+		return c, fmt.Errorf("empty version constraint")
+	}
+	for _, token := range tokens {
+		bounds, err := parseBound(token)
+		if err != nil {
+			return c, fmt.Errorf("invalid version constraint %q: %v", s, err)
+		}
+		c.bounds = append(c.bounds, bounds...)
+	}
+	return c, nil
+}
+
+func isConstraintOp(s string) bool {
+	for _, op := range constraintOps {
+		if s == op {
+			return true
+		}
+	}
+	return s == "~" || s == "^"
+}
+
+func parseBound(token string) ([]versionBound, error) {
+	switch {
+	case strings.HasPrefix(token, "~"):
+		// ~1.22 and ~1.22.3 stay within the 1.22 line; ~1 within Go 1
+		parts, err := constraintParts(token[1:])
+		if err != nil {
+			return nil, err
+		}
+		upper := nextLine(parts[:min(len(parts), 2)])
+		return []versionBound{{">=", token[1:]}, {"<", upper}}, nil
+	case strings.HasPrefix(token, "^"):
+		parts, err := constraintParts(token[1:])
+		if err != nil {
+			return nil, err
+		}
+		return []versionBound{{">=", token[1:]}, {"<", nextLine(parts[:1])}}, nil
+	}
+	for _, op := range constraintOps {
+		if version, ok := strings.CutPrefix(token, op); ok {
+			if _, err := constraintParts(version); err != nil {
+				return nil, err
+			}
+			return []versionBound{{op, strings.TrimPrefix(version, "go")}}, nil
+		}
+	}
+	// A bare or wildcard version covers its whole line: 1.22.x, 1.22.* and
+	// 1.22 all mean >=1.22 <1.23
+	version := strings.TrimPrefix(token, "go")
+	for _, wildcard := range []string{".x", ".X", ".*"} {
+		version = strings.TrimSuffix(version, wildcard)
+	}
+	parts, err := constraintParts(version)
+	if err != nil {
+		return nil, err
+	}
+	return []versionBound{{">=", version}, {"<", nextLine(parts)}}, nil
+}
+
+// constraintParts splits a version in a constraint into its numeric parts.
+func constraintParts(version string) ([]int, error) {
+	version = strings.TrimPrefix(version, "go")
+	if version == "" {
+		return nil, fmt.Errorf("missing version")
+	}
+	release, _ := SplitPreRelease(version)
+	var parts []int
+	for _, part := range strings.Split(release, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a version", version)
+		}
+		parts = append(parts, n)
+	}
+	return parts, nil
+}
+
+// nextLine returns the first version after the line parts names, e.g. 1.23
+// for [1 22] and 2 for [1].
+func nextLine(parts []int) string {
+	next := make([]string, len(parts))
+	for i, n := range parts {
+		next[i] = strconv.Itoa(n)
+	}
+	last := len(parts) - 1
+	next[last] = strconv.Itoa(parts[last] + 1)
+	return strings.Join(next, ".")
+}
+
+// padVersion writes a version with three release parts, so that 1.22 and
+// 1.22.0 compare equal: Go named its first releases of a line both ways.
+func padVersion(version string) string {
+	release, pre := SplitPreRelease(version)
+	for strings.Count(release, ".") < 2 {
+		release += ".0"
+	}
+	return release + pre
+}
+
+// Match reports whether version satisfies every bound. Pre-releases of an
+// excluded upper bound, like 1.23rc1 for <1.23, do not match.
+func (c VersionConstraint) Match(version string) bool {
+	v := padVersion(version)
+	for _, b := range c.bounds {
+		cmp := CompareVersions(v, padVersion(b.version))
+		ok := false
+		switch b.op {
+		case ">=":
+			ok = cmp >= 0
+		case "<=":
+			ok = cmp <= 0
+		case ">":
+			ok = cmp > 0
+		case "<":
+			release, _ := SplitPreRelease(v)
+			ok = cmp < 0 && !(IsPreRelease(version) && release == padVersion(b.version))
+		case "=":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (c VersionConstraint) String() string {
+	return c.text
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/melkeydev/govm/internal/clock"
+	"github.com/melkeydev/govm/internal/events"
+)
+
+// maxHistory is how many switches are kept in the history file.
+const maxHistory = 100
+
+// SwitchRecord is one entry of the switch history; it is defined with the
+// messages that carry it.
+type SwitchRecord = events.SwitchRecord
+
+func historyPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %v", err)
+	}
+	return filepath.Join(homeDir, ".govm", "history"), nil
+}
+
+// RecordSwitch appends a switch to the history file, trimming it to the
+// most recent entries.
+func RecordSwitch(version string) error {
+	path, err := historyPath()
+	if err != nil {
+		return err
+	}
+	records, _ := ReadSwitchHistory()
+	records = append([]SwitchRecord{{Time: clock.Now().UTC(), Version: version}}, records...)
+	if len(records) > maxHistory {
+		records = records[:maxHistory]
+	}
+	var b strings.Builder
+	for i := len(records) - 1; i >= 0; i-- {
+		fmt.Fprintf(&b, "%s %s\n", records[i].Time.Format(time.RFC3339), records[i].Version)
+	}
+	return os.WriteFile(path, []byte(b.String()), 0644)
+}
+
+// ReadSwitchHistory returns recorded switches, newest first.
+func ReadSwitchHistory() ([]SwitchRecord, error) {
+	path, err := historyPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	var records []SwitchRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, fields[0])
+		if err != nil {
+			continue
+		}
+		records = append([]SwitchRecord{{Time: t, Version: fields[1]}}, records...)
+	}
+	return records, scanner.Err()
+}
+
+// LastUsed returns when each version in records, a switch history newest
+// first, was last switched to. Versions not in the history are missing.
+func LastUsed(records []SwitchRecord) map[string]time.Time {
+	used := map[string]time.Time{}
+	for _, record := range records {
+		if _, ok := used[record.Version]; !ok {
+			used[record.Version] = record.Time
+		}
+	}
+	return used
+}
+
+// LoadSwitchHistory reads the switch history for the TUI.
+func LoadSwitchHistory() tea.Msg {
+	records, err := ReadSwitchHistory()
+	if err != nil {
+		return events.ErrMsgf("failed to read switch history: %v", err)
+	}
+	return events.SwitchHistoryMsg(records)
+}
@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/melkeydev/govm/internal/clock"
+)
+
+func TestSwitchHistory(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, ".govm"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	fake := clock.NewFake(start)
+	clock.Default = fake
+	t.Cleanup(func() { clock.Default = clock.Real{} })
+
+	for _, version := range []string{"1.21.5", "1.22.4", "1.21.5"} {
+		if err := RecordSwitch(version); err != nil {
+			t.Fatal(err)
+		}
+		fake.Advance(90 * time.Minute)
+	}
+	records, err := ReadSwitchHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SwitchRecord{
+		{Time: start.Add(3 * time.Hour).UTC(), Version: "1.21.5"},
+		{Time: start.Add(90 * time.Minute).UTC(), Version: "1.22.4"},
+		{Time: start.UTC(), Version: "1.21.5"},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("got %d records, want %d", len(records), len(want))
+	}
+	for i, r := range records {
+		if !r.Time.Equal(want[i].Time) || r.Version != want[i].Version || r.Time.Location() != time.UTC {
+			t.Errorf("record %d = %v %s, want %v %s", i, r.Time, r.Version, want[i].Time, want[i].Version)
+		}
+	}
+	if got := RelativeTime(records[2].Time, clock.Now()); got != "4 hours ago" {
+		t.Errorf("oldest switch was %q, want 4 hours ago", got)
+	}
+	used := LastUsed(records)
+	if !used["1.21.5"].Equal(want[0].Time) || !used["1.22.4"].Equal(want[1].Time) || len(used) != 2 {
+		t.Errorf("LastUsed = %v, want 1.21.5 at %v and 1.22.4 at %v", used, want[0].Time, want[1].Time)
+	}
+}
+
+func TestInstalledAt(t *testing.T) {
+	installed := time.Date(2024, 6, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	clock.Default = clock.NewFake(installed)
+	t.Cleanup(func() { clock.Default = clock.Real{} })
+	versionDir := filepath.Join(t.TempDir(), "go1.22.4")
+	if err := os.Mkdir(versionDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Unpacked directories carry the time they were packed with
+	packed := installed.Add(-30 * 24 * time.Hour)
+	if err := os.Chtimes(versionDir, packed, packed); err != nil {
+		t.Fatal(err)
+	}
+	markInstalled(versionDir)
+	at, ok := InstalledAt(versionDir)
+	if !ok || !at.Equal(installed) || at.Location() != time.UTC {
+		t.Errorf("InstalledAt = %v, %v; want %v in UTC", at, ok, installed)
+	}
+	if _, ok := InstalledAt(filepath.Join(t.TempDir(), "go1.23.0")); ok {
+		t.Error("InstalledAt reported a time for a missing install")
+	}
+}
+
+func TestSwitchHistoryTrimmed(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, ".govm"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	fake := clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	clock.Default = fake
+	t.Cleanup(func() { clock.Default = clock.Real{} })
+	for i := 0; i < maxHistory+5; i++ {
+		if err := RecordSwitch("1.22.4"); err != nil {
+			t.Fatal(err)
+		}
+		fake.Advance(time.Minute)
+	}
+	records, _ := ReadSwitchHistory()
+	if len(records) != maxHistory {
+		t.Errorf("kept %d records, want %d", len(records), maxHistory)
+	}
+}
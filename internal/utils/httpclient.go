@@ -0,0 +1,254 @@
+package utils
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/melkeydev/govm/internal/config"
+	"github.com/melkeydev/govm/internal/term"
+)
+
+// CABundleEnv names a PEM file of extra CA certificates to trust, for
+// networks whose proxy intercepts TLS. It overrides download.ca_bundle.
+const CABundleEnv = "GOVM_CA_BUNDLE"
+
+// IPVersion restricts connections to IPv4 ("4") or IPv6 ("6"). It is set
+// from the global --ipv4 and --ipv6 flags and overrides
+// download.ip_version; empty leaves the config in charge.
+var IPVersion string
+
+// requestKind separates the small metadata requests from archive
+// downloads, which take minutes on a slow link and get their own timeout.
+type requestKind int
+
+const (
+	// metadataRequest fetches the release index, release notes or policy.
+	metadataRequest requestKind = iota
+	// archiveRequest downloads a release archive.
+	archiveRequest
+)
+
+// The timeouts used when download.timeouts leaves one unset.
+const (
+	defaultDialTimeout     = 10 * time.Second
+	defaultHeaderTimeout   = 30 * time.Second
+	defaultMetadataTimeout = 10 * time.Second
+	// Archive downloads are bounded by the dial and header timeouts only
+	defaultDownloadTimeout = 0
+)
+
+// Transport, when set, carries every request govm makes in place of the
+// transport built from the download settings, e.g. an httptest server's
+// client transport in tests or a custom network stack when govm is
+// embedded. The metadata and download timeouts still apply, and offline
+// mode still refuses requests.
+var Transport http.RoundTripper
+
+// newHTTPClient returns the client every request to go.dev, the mirrors
+// and the policy server goes through, with the timeouts for kind. Its
+// transport is Transport if set, or else newTransport's. Offline it returns
+// ErrOffline.
+func newHTTPClient(kind requestKind) (*http.Client, error) {
+	if IsOffline() {
+		return nil, ErrOffline
+	}
+	cfg, _ := config.Load()
+	timeouts := cfg.Download.Timeouts
+	overall, err := parseTimeout("metadata", timeouts.Metadata, defaultMetadataTimeout)
+	if kind == archiveRequest {
+		overall, err = parseTimeout("download", timeouts.Download, defaultDownloadTimeout)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if Transport != nil {
+		return &http.Client{Transport: Transport, Timeout: overall}, nil
+	}
+	transport, err := newTransport(timeouts)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport, Timeout: overall}, nil
+}
+
+// newTransport builds the transport from the download settings. It uses
+// the download.proxy setting, or else HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+// like other tools do, trusts the extra CA certificates of GOVM_CA_BUNDLE
+// or download.ca_bundle, dials over the IP version asked for and applies
+// the dial and header timeouts.
+func newTransport(timeouts config.TimeoutsConfig) (*http.Transport, error) {
+	dial, err := parseTimeout("dial", timeouts.Dial, defaultDialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	header, err := parseTimeout("header", timeouts.Header, defaultHeaderTimeout)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxyFunc()
+	network, err := dialNetwork()
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{Timeout: dial, KeepAlive: 30 * time.Second}
+	transport.DialContext = func(ctx context.Context, _, addr string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, addr)
+	}
+	transport.TLSHandshakeTimeout = dial
+	transport.ResponseHeaderTimeout = header
+	tlsConfig, err := tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// dialNetwork returns the network connections are dialed on: "tcp4" or
+// "tcp6" when IPVersion or download.ip_version restricts it, else "tcp".
+func dialNetwork() (string, error) {
+	version := IPVersion
+	if version == "" {
+		cfg, _ := config.Load()
+		version = cfg.Download.IPVersion
+	}
+	switch version {
+	case "":
+		return "tcp", nil
+	case "4", "6":
+		term.Debugf("connecting over IPv%s only", version)
+		return "tcp" + version, nil
+	}
+	return "", fmt.Errorf("unsupported download.ip_version %q: use \"4\" or \"6\"", version)
+}
+
+// parseTimeout parses the download.timeouts setting name, a duration such
+// as "30s" or "5m", falling back to def when it is empty. "0" disables the
+// timeout.
+func parseTimeout(name, value string, def time.Duration) (time.Duration, error) {
+	if value == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(value)
+	if value == "0" {
+		d, err = 0, nil
+	}
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid download.timeouts.%s %q: expected a duration such as 30s or 5m", name, value)
+	}
+	return d, nil
+}
+
+// tlsConfig builds the TLS settings from GOVM_CA_BUNDLE and the download
+// settings. The bundle adds to the system's roots rather than replacing
+// them, so go.dev stays trusted alongside the intercepting proxy.
+func tlsConfig() (*tls.Config, error) {
+	cfg, _ := config.Load()
+	conf := &tls.Config{}
+	switch cfg.Download.TLSMinVersion {
+	case "", "1.2":
+		conf.MinVersion = tls.VersionTLS12
+	case "1.3":
+		conf.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("unsupported download.tls_min_version %q: use \"1.2\" or \"1.3\"", cfg.Download.TLSMinVersion)
+	}
+	source := CABundleEnv
+	bundle := os.Getenv(CABundleEnv)
+	if bundle == "" {
+		source, bundle = "download.ca_bundle", cfg.Download.CABundle
+	}
+	if bundle == "" {
+		return conf, nil
+	}
+	pem, err := os.ReadFile(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the CA bundle in %s: %v", source, err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("the CA bundle %s (%s) holds no PEM certificates", bundle, source)
+	}
+	term.Debugf("trusting the CA certificates in %s", bundle)
+	conf.RootCAs = pool
+	return conf, nil
+}
+
+// proxyFunc picks the proxy for each request. An explicit download.proxy
+// overrides HTTP_PROXY and HTTPS_PROXY, but hosts listed in NO_PROXY still
+// bypass it.
+func proxyFunc() func(*http.Request) (*url.URL, error) {
+	cfg, _ := config.Load()
+	setting := strings.TrimSpace(cfg.Download.Proxy)
+	if setting == "" {
+		return http.ProxyFromEnvironment
+	}
+	if !strings.Contains(setting, "://") {
+		setting = "http://" + setting
+	}
+	proxy, err := url.Parse(setting)
+	if err != nil || proxy.Host == "" {
+		term.Debugf("ignoring download.proxy %q: not a proxy URL", redactURL(setting))
+		return http.ProxyFromEnvironment
+	}
+	noProxy := os.Getenv("NO_PROXY")
+	if noProxy == "" {
+		noProxy = os.Getenv("no_proxy")
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		if bypassProxy(req.URL.Host, noProxy) {
+			return nil, nil
+		}
+		term.Debugf("via proxy %s", proxy.Redacted())
+		return proxy, nil
+	}
+}
+
+// bypassProxy reports whether host matches the NO_PROXY list noProxy:
+// comma-separated hosts, each matching itself and its subdomains, "*" for
+// every host, optionally with a port.
+func bypassProxy(host, noProxy string) bool {
+	hostname, port, err := net.SplitHostPort(host)
+	if err != nil {
+		hostname = host
+	}
+	hostname = strings.ToLower(hostname)
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			if p != port {
+				continue
+			}
+			entry = h
+		}
+		entry = strings.TrimPrefix(entry, "*")
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			if ip := net.ParseIP(hostname); ip != nil && network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if hostname == strings.TrimPrefix(entry, ".") || strings.HasSuffix(hostname, "."+strings.TrimPrefix(entry, ".")) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,202 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/melkeydev/govm/internal/clock"
+	"github.com/melkeydev/govm/internal/config"
+	"github.com/melkeydev/govm/internal/term"
+)
+
+// defaultIndexTTL is how long a cached release index is used without asking
+// the mirror again when download.index_ttl is unset.
+const defaultIndexTTL = 10 * time.Minute
+
+// indexCache is the release index as last fetched, with the validators the
+// mirror sent so a refresh can be a conditional request.
+type indexCache struct {
+	URL          string          `json:"url"`
+	ETag         string          `json:"etag,omitempty"`
+	LastModified string          `json:"last_modified,omitempty"`
+	FetchedAt    time.Time       `json:"fetched_at"`
+	Body         json.RawMessage `json:"body"`
+}
+
+var indexCacheMu sync.Mutex
+
+func indexCachePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".govm", "cache", "release_index.json"), nil
+}
+
+func readIndexCache() (indexCache, bool) {
+	var cache indexCache
+	path, err := indexCachePath()
+	if err != nil {
+		return cache, false
+	}
+	indexCacheMu.Lock()
+	defer indexCacheMu.Unlock()
+	content, err := os.ReadFile(path)
+	if err != nil || json.Unmarshal(content, &cache) != nil || len(cache.Body) == 0 {
+		return indexCache{}, false
+	}
+	return cache, true
+}
+
+func writeIndexCache(cache indexCache) {
+	path, err := indexCachePath()
+	if err != nil {
+		return
+	}
+	content, err := json.Marshal(cache)
+	if err != nil {
+		return
+	}
+	indexCacheMu.Lock()
+	defer indexCacheMu.Unlock()
+	os.MkdirAll(filepath.Dir(path), 0755)
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		term.Debugf("failed to cache the release index: %v", err)
+	}
+}
+
+// indexTTL returns the download.index_ttl setting.
+func indexTTL() (time.Duration, error) {
+	cfg, _ := config.Load()
+	if cfg.Download.IndexTTL == "" {
+		return defaultIndexTTL, nil
+	}
+	ttl, err := time.ParseDuration(cfg.Download.IndexTTL)
+	if cfg.Download.IndexTTL == "0" {
+		ttl, err = 0, nil
+	}
+	if err != nil || ttl < 0 {
+		return 0, fmt.Errorf("invalid download.index_ttl %q: expected a duration such as 10m or 1h", cfg.Download.IndexTTL)
+	}
+	return ttl, nil
+}
+
+// loadIndex returns the JSON release index. Unless refresh is set, a copy
+// cached from one of the current mirrors is used as is while it is younger
+// than download.index_ttl. Otherwise the mirrors are asked in turn,
+// conditionally when the cache came from the same one. Offline, or when no
+// mirror provides the index, the cached copy is used whatever its age.
+func loadIndex(refresh bool) ([]byte, error) {
+	ttl, err := indexTTL()
+	if err != nil {
+		return nil, err
+	}
+	mirrors := DownloadMirrors()
+	cache, cached := readIndexCache()
+	if refresh {
+		retryOnline()
+	}
+	if IsOffline() {
+		if !cached {
+			return nil, fmt.Errorf("%w and no release list is cached yet; run 'govm ls-remote' once while online", ErrOffline)
+		}
+		term.Debugf("working offline with the release index cached at %s", cache.FetchedAt.Format(time.RFC3339))
+		setStaleIndexTime(cache.FetchedAt)
+		return cache.Body, nil
+	}
+	if cached && !refresh && slices.Contains(mirrors, cache.URL) {
+		if age := clock.Since(cache.FetchedAt); age >= 0 && age < ttl {
+			term.Debugf("using the release index cached %s ago", age.Round(time.Second))
+			return cache.Body, nil
+		}
+	}
+	var body []byte
+	for _, mirror := range mirrors {
+		var previous *indexCache
+		if cached && cache.URL == mirror {
+			previous = &cache
+		}
+		if body, err = fetchIndex(mirror, previous); err == nil {
+			setStaleIndexTime(time.Time{})
+			return body, nil
+		}
+		term.Debugf("%v", err)
+	}
+	if !cached {
+		return nil, err
+	}
+	// A stale release list beats none: installed versions and cached
+	// archives stay usable while go.dev is down
+	if isUnreachable(err) {
+		goOffline(err, cache.FetchedAt)
+		return cache.Body, nil
+	}
+	term.Debugf("using the release index cached at %s", cache.FetchedAt.Format(time.RFC3339))
+	setStaleIndexTime(cache.FetchedAt)
+	if OnStaleIndex != nil {
+		OnStaleIndex(cache.FetchedAt, err)
+	}
+	return cache.Body, nil
+}
+
+// fetchIndex downloads the JSON release index from mirror and caches it.
+// With previous, the copy cached from this mirror, the request is
+// conditional and a 304 reuses it.
+func fetchIndex(mirror string, previous *indexCache) ([]byte, error) {
+	client, err := newHTTPClient(metadataRequest)
+	if err != nil {
+		return nil, err
+	}
+	indexURL := mirror + "?mode=json&include=all"
+	req, err := http.NewRequest(http.MethodGet, indexURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	authorize(req)
+	if previous != nil {
+		if previous.ETag != "" {
+			req.Header.Set("If-None-Match", previous.ETag)
+		}
+		if previous.LastModified != "" {
+			req.Header.Set("If-Modified-Since", previous.LastModified)
+		}
+	}
+	term.Debugf("GET %s", redactURL(indexURL))
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, classifyNetworkError(indexURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified && previous != nil {
+		term.Debugf("the release index has not changed")
+		previous.FetchedAt = clock.Now().UTC()
+		writeIndexCache(*previous)
+		return previous.Body, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(indexURL, resp)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	// A captive portal or a misconfigured mirror answers 200 with HTML
+	if !json.Valid(body) {
+		return nil, fmt.Errorf("%s did not return a JSON release index", mirrorHost(mirror))
+	}
+	writeIndexCache(indexCache{
+		URL:          mirror,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		FetchedAt:    clock.Now().UTC(),
+		Body:         body,
+	})
+	return body, nil
+}
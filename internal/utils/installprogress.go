@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/melkeydev/govm/internal/clock"
+	"github.com/melkeydev/govm/internal/events"
+	"github.com/melkeydev/govm/internal/term"
+)
+
+// The phases an install goes through, as InstallProgress.Phase.
+const (
+	PhaseDownloading = "downloading"
+	PhaseExtracting  = "extracting"
+	PhaseDone        = "done"
+	PhaseFailed      = "failed"
+)
+
+// InstallProgress is how far an install has got. Every install records it
+// in ~/.govm/progress, whichever govm process runs it, so that govm serve
+// can stream it to editors.
+type InstallProgress struct {
+	Version string `json:"version"`
+	Phase   string `json:"phase"`
+	// Downloaded and Total count archive bytes; Total is 0 when the
+	// server did not send the size.
+	Downloaded     int64   `json:"downloaded"`
+	Total          int64   `json:"total"`
+	BytesPerSecond float64 `json:"bytes_per_second"`
+	ETASeconds     int64   `json:"eta_seconds"`
+	// Error is set in the failed phase.
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func progressDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".govm", "progress"), nil
+}
+
+// recordProgress saves p for LoadInstallProgress. It is best effort: an
+// install never fails because its progress could not be recorded.
+func recordProgress(p InstallProgress) {
+	dir, err := progressDir()
+	if err != nil {
+		return
+	}
+	p.UpdatedAt = clock.Now().UTC()
+	content, err := json.Marshal(p)
+	if err != nil {
+		return
+	}
+	os.MkdirAll(dir, 0755)
+	if err := writeFileAtomic(filepath.Join(dir, "go"+p.Version+".json"), content, 0644); err != nil {
+		term.Debugf("failed to record install progress: %v", err)
+	}
+}
+
+// LoadInstallProgress returns the progress last recorded for each version
+// installed so far, including finished and failed installs.
+func LoadInstallProgress() []InstallProgress {
+	dir, err := progressDir()
+	if err != nil {
+		return nil
+	}
+	entries, _ := os.ReadDir(dir)
+	var progress []InstallProgress
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), "go") || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		var p InstallProgress
+		if json.Unmarshal(content, &p) == nil {
+			progress = append(progress, p)
+		}
+	}
+	return progress
+}
+
+// downloadProgress converts a download report into InstallProgress.
+func downloadProgress(msg events.DownloadProgressMsg) InstallProgress {
+	return InstallProgress{
+		Version:        msg.Version,
+		Phase:          PhaseDownloading,
+		Downloaded:     msg.Downloaded,
+		Total:          msg.Total,
+		BytesPerSecond: msg.BytesPerSecond,
+		ETASeconds:     int64(msg.ETA / time.Second),
+	}
+}
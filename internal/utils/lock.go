@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/melkeydev/govm/internal/clock"
+	"github.com/melkeydev/govm/internal/term"
+)
+
+// StaleLockAge is how old a lock file taken on another machine may get
+// before it is assumed to have been left behind by a crashed process.
+// Partial downloads and extraction directories younger than this may
+// belong to an install still running.
+const StaleLockAge = 30 * time.Minute
+
+// AcquireLock takes an exclusive lock on the GoVM directory so that several
+// processes sharing it (e.g. parallel CI jobs on one cache volume) do not
+// install over each other. It waits up to timeout and returns a function
+// that releases the lock. The lock file names the process holding it, so a
+// lock left by a process that has exited is broken right away.
+func AcquireLock(timeout time.Duration) (func(), error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %v", err)
+	}
+	govmDir := filepath.Join(homeDir, ".govm")
+	if err := os.MkdirAll(govmDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create govm directory: %v", err)
+	}
+	lockPath := filepath.Join(govmDir, ".lock")
+	deadline := clock.Now().Add(timeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			hostname, _ := os.Hostname()
+			fmt.Fprintf(f, "%d %s\n", os.Getpid(), hostname)
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to create lock file: %v", err)
+		}
+		if staleLock(lockPath) {
+			term.Debugf("breaking the stale lock %s", lockPath)
+			os.Remove(lockPath)
+			continue
+		}
+		if clock.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for lock %s", lockPath)
+		}
+		clock.Sleep(500 * time.Millisecond)
+	}
+}
+
+// staleLock reports whether the lock at lockPath was left behind: its
+// process has exited, or, when it was taken on another machine sharing the
+// directory and so cannot be checked, it is older than StaleLockAge.
+func staleLock(lockPath string) bool {
+	info, err := os.Stat(lockPath)
+	if err != nil {
+		return false
+	}
+	content, _ := os.ReadFile(lockPath)
+	var pid int
+	var host string
+	fmt.Sscan(string(content), &pid, &host)
+	if hostname, _ := os.Hostname(); pid > 0 && host != "" && host == hostname {
+		return !processRunning(pid)
+	}
+	return clock.Since(info.ModTime()) > StaleLockAge
+}
@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/melkeydev/govm/internal/clock"
+)
+
+// useFakeClock makes clock.Default a Fake at the current time for the test.
+func useFakeClock(t *testing.T) *clock.Fake {
+	t.Helper()
+	fake := clock.NewFake(time.Now())
+	clock.Default = fake
+	t.Cleanup(func() { clock.Default = clock.Real{} })
+	return fake
+}
+
+// runAdvancing calls fn while advancing fake by step until fn returns.
+func runAdvancing(fake *clock.Fake, step time.Duration, fn func()) {
+	done := make(chan struct{})
+	go func() {
+		fn()
+		close(done)
+	}()
+	for {
+		select {
+		case <-done:
+			return
+		case <-time.After(time.Millisecond):
+			fake.Advance(step)
+		}
+	}
+}
+
+func TestAcquireLock(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	release, err := AcquireLock(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lockPath := filepath.Join(home, ".govm", ".lock")
+	if _, err := os.Stat(lockPath); err != nil {
+		t.Fatalf("lock file missing: %v", err)
+	}
+	release()
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Fatalf("lock file left behind after release: %v", err)
+	}
+}
+
+func TestAcquireLockWaits(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	fake := useFakeClock(t)
+	release, err := AcquireLock(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	var second error
+	runAdvancing(fake, time.Second, func() {
+		_, second = AcquireLock(5 * time.Second)
+	})
+	if second == nil {
+		t.Fatal("second AcquireLock succeeded while the lock was held")
+	}
+}
+
+// writeLock leaves a lock file naming pid on host, as if taken age ago.
+func writeLock(t *testing.T, home string, pid int, host string, fake *clock.Fake, age time.Duration) {
+	t.Helper()
+	lockPath := filepath.Join(home, ".govm", ".lock")
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(lockPath, []byte(fmt.Sprintf("%d %s\n", pid, host)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(lockPath, fake.Now().Add(-age), fake.Now().Add(-age)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAcquireLockBreaksStaleLock(t *testing.T) {
+	hostname, _ := os.Hostname()
+	tests := []struct {
+		name   string
+		pid    int
+		host   string
+		age    time.Duration
+		broken bool
+	}{
+		// No process has a PID above the kernel's limit of 1<<22
+		{"exited process on this machine", 1 << 23, hostname, 0, true},
+		{"running process on this machine", os.Getpid(), hostname, StaleLockAge * 2, false},
+		{"recent lock from another machine", 1, "elsewhere.test", time.Minute, false},
+		{"old lock from another machine", 1, "elsewhere.test", StaleLockAge + time.Minute, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			fake := useFakeClock(t)
+			writeLock(t, home, tt.pid, tt.host, fake, tt.age)
+			var release func()
+			var err error
+			runAdvancing(fake, time.Second, func() {
+				release, err = AcquireLock(time.Second)
+			})
+			if tt.broken != (err == nil) {
+				t.Fatalf("AcquireLock() error = %v, want the lock broken: %v", err, tt.broken)
+			}
+			if release != nil {
+				release()
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ManifestFile is the name of the file listing the Go versions a team or
+// project expects to have installed.
+const ManifestFile = "govm.versions"
+
+// Manifest is the parsed contents of a govm.versions file: one version per
+// line, with the version to activate marked by a trailing "default", e.g.
+//
+//	# toolchains for this repository
+//	1.21
+//	1.22.5 default
+type Manifest struct {
+	Versions []string
+	Default  string
+}
+
+// FindManifest walks up from dir looking for a govm.versions file.
+func FindManifest(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		path := filepath.Join(dir, ManifestFile)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no %s found", ManifestFile)
+		}
+		dir = parent
+	}
+}
+
+// ReadManifest parses a govm.versions file.
+func ReadManifest(path string) (Manifest, error) {
+	var manifest Manifest
+	f, err := os.Open(path)
+	if err != nil {
+		return manifest, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case len(fields) == 2 && fields[1] == "default":
+			if manifest.Default != "" {
+				return manifest, fmt.Errorf("%s:%d: more than one default version", path, lineNum)
+			}
+			manifest.Default = strings.TrimPrefix(fields[0], "go")
+		case len(fields) != 1:
+			return manifest, fmt.Errorf("%s:%d: expected a version, optionally followed by \"default\"", path, lineNum)
+		}
+		manifest.Versions = append(manifest.Versions, strings.TrimPrefix(fields[0], "go"))
+	}
+	if err := scanner.Err(); err != nil {
+		return manifest, err
+	}
+	if len(manifest.Versions) == 0 {
+		return manifest, fmt.Errorf("%s lists no versions", path)
+	}
+	return manifest, nil
+}
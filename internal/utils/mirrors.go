@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/config"
+)
+
+// DefaultDownloadMirror serves the release index and archives unless the
+// download.mirrors setting lists others.
+const DefaultDownloadMirror = "https://go.dev/dl/"
+
+// DownloadMirrors returns the base URLs the release index and archives are
+// fetched from, in the order they are tried: the download.mirrors setting,
+// or go.dev alone. Each ends in a slash so a filename can be appended.
+func DownloadMirrors() []string {
+	cfg, err := config.Load()
+	if err != nil || len(cfg.Download.Mirrors) == 0 {
+		return []string{DefaultDownloadMirror}
+	}
+	mirrors := make([]string, 0, len(cfg.Download.Mirrors))
+	for _, mirror := range cfg.Download.Mirrors {
+		if mirror = strings.TrimSpace(mirror); mirror != "" {
+			mirrors = append(mirrors, strings.TrimSuffix(mirror, "/")+"/")
+		}
+	}
+	if len(mirrors) == 0 {
+		return []string{DefaultDownloadMirror}
+	}
+	return mirrors
+}
+
+// DownloadBaseURL returns the download.base_url setting with a trailing
+// slash, or "" when archives come from the mirrors.
+func DownloadBaseURL() string {
+	cfg, err := config.Load()
+	if err != nil {
+		return ""
+	}
+	if base := strings.TrimSpace(cfg.Download.BaseURL); base != "" {
+		return strings.TrimSuffix(base, "/") + "/"
+	}
+	return ""
+}
+
+// mirrorHost names a mirror in messages.
+func mirrorHost(mirror string) string {
+	if u, err := url.Parse(mirror); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return mirror
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/melkeydev/govm/internal/term"
+)
+
+// ErrOffline is returned in place of a request while govm works offline.
+var ErrOffline = errors.New("govm is working offline")
+
+// OnNetworkUnreachable, when set, is called once if govm finds the network
+// unreachable and carries on offline with the release index cached at
+// cachedAt. The CLI prints a notice from it; the TUI shows a banner instead.
+var OnNetworkUnreachable func(cachedAt time.Time)
+
+// OnStaleIndex, when set, is called when the mirrors answered but no fresh
+// release index could be had from them, with that error, and the copy
+// cached at cachedAt is used instead.
+var OnStaleIndex func(cachedAt time.Time, err error)
+
+var (
+	offlineMu sync.Mutex
+	// offlineFlag is set by --offline; unreachable once the network failed
+	offlineFlag bool
+	unreachable bool
+	// staleIndexAt is when the cached index used in place of a fresh one
+	// was fetched
+	staleIndexAt time.Time
+)
+
+// SetOffline keeps govm off the network: the release index comes from
+// ~/.govm/cache and only archives already in ~/.govm/downloads can be
+// installed. It is set by the global --offline flag.
+func SetOffline(on bool) {
+	offlineMu.Lock()
+	defer offlineMu.Unlock()
+	offlineFlag = on
+}
+
+// IsOffline reports whether govm is working offline, because --offline was
+// given or because the network turned out to be unreachable.
+func IsOffline() bool {
+	offlineMu.Lock()
+	defer offlineMu.Unlock()
+	return offlineFlag || unreachable
+}
+
+// StaleIndexTime returns when the release index in use was fetched, if it
+// is the cached copy standing in for one that could not be fetched, offline
+// or otherwise.
+func StaleIndexTime() (time.Time, bool) {
+	offlineMu.Lock()
+	defer offlineMu.Unlock()
+	return staleIndexAt, !staleIndexAt.IsZero()
+}
+
+func setStaleIndexTime(t time.Time) {
+	offlineMu.Lock()
+	defer offlineMu.Unlock()
+	staleIndexAt = t
+}
+
+// goOffline switches to offline mode after a request failed with err,
+// reporting it through OnNetworkUnreachable the first time.
+func goOffline(err error, cachedAt time.Time) {
+	term.Debugf("working offline: %v", err)
+	offlineMu.Lock()
+	wasOffline := offlineFlag || unreachable
+	unreachable = true
+	offlineMu.Unlock()
+	if !cachedAt.IsZero() {
+		setStaleIndexTime(cachedAt)
+	}
+	if !wasOffline && OnNetworkUnreachable != nil {
+		OnNetworkUnreachable(cachedAt)
+	}
+}
+
+// retryOnline forgets that the network was unreachable so the next request
+// tries it again. Offline mode requested with --offline stays on.
+func retryOnline() {
+	offlineMu.Lock()
+	defer offlineMu.Unlock()
+	unreachable = false
+}
+
+// isUnreachable reports whether err means the network or the server could
+// not be reached at all, as opposed to a server answering with an error.
+func isUnreachable(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/melkeydev/govm/internal/clock"
+	"github.com/melkeydev/govm/internal/config"
+	"github.com/melkeydev/govm/internal/term"
+)
+
+// Policy is an organization's version policy, published as JSON at a URL
+// configured in config.json:
+//
+//	{
+//	  "minimum": "1.21",
+//	  "allowed": ["1.21", "1.22"],
+//	  "blocked": [{"version": "1.22.0", "reason": "miscompiles our service"}]
+//	}
+//
+// Allowed and blocked entries match a version exactly or as a prefix, so
+// "1.22" covers every 1.22.x release.
+type Policy struct {
+	Minimum string           `json:"minimum"`
+	Allowed []string         `json:"allowed"`
+	Blocked []BlockedRelease `json:"blocked"`
+}
+
+// BlockedRelease is a version a Policy forbids, with the reason shown to
+// users who try to use it.
+type BlockedRelease struct {
+	Version string `json:"version"`
+	Reason  string `json:"reason"`
+}
+
+// defaultPolicyTTL is how long a fetched policy is used without fetching
+// it again when policy.ttl is unset.
+const defaultPolicyTTL = time.Hour
+
+// policyCache is the policy as last fetched from URL.
+type policyCache struct {
+	URL       string    `json:"url"`
+	FetchedAt time.Time `json:"fetched_at"`
+	Policy    Policy    `json:"policy"`
+}
+
+func policyCachePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".govm", "cache", "policy.json"), nil
+}
+
+// policyTTL returns the policy.ttl setting.
+func policyTTL() (time.Duration, error) {
+	cfg, _ := config.Load()
+	if cfg.Policy.TTL == "" {
+		return defaultPolicyTTL, nil
+	}
+	ttl, err := time.ParseDuration(cfg.Policy.TTL)
+	if cfg.Policy.TTL == "0" {
+		ttl, err = 0, nil
+	}
+	if err != nil || ttl < 0 {
+		return 0, fmt.Errorf("invalid policy.ttl %q: expected a duration such as 30m or 1h", cfg.Policy.TTL)
+	}
+	return ttl, nil
+}
+
+// FetchPolicy returns the policy at url. A copy cached from url is used as
+// is while it is younger than policy.ttl, and whatever its age when govm is
+// offline or url cannot be fetched.
+func FetchPolicy(url string) (Policy, error) {
+	ttl, err := policyTTL()
+	if err != nil {
+		return Policy{}, err
+	}
+	var cache policyCache
+	path, pathErr := policyCachePath()
+	cached := false
+	if pathErr == nil {
+		if content, err := os.ReadFile(path); err == nil && json.Unmarshal(content, &cache) == nil && cache.URL == url {
+			cached = true
+		}
+	}
+	if cached {
+		if age := clock.Since(cache.FetchedAt); IsOffline() || (age >= 0 && age < ttl) {
+			term.Debugf("using the version policy cached %s ago", age.Round(time.Second))
+			return cache.Policy, nil
+		}
+	}
+	policy, err := fetchPolicy(url)
+	if err != nil {
+		if cached {
+			term.Debugf("%v; using the copy cached at %s", err, cache.FetchedAt.Format(time.RFC3339))
+			return cache.Policy, nil
+		}
+		return policy, err
+	}
+	if pathErr == nil {
+		content, _ := json.Marshal(policyCache{URL: url, FetchedAt: clock.Now().UTC(), Policy: policy})
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			term.Debugf("failed to cache the version policy: %v", err)
+		}
+	}
+	return policy, nil
+}
+
+// fetchPolicy downloads and parses the policy at url.
+func fetchPolicy(url string) (Policy, error) {
+	var policy Policy
+	client, err := newHTTPClient(metadataRequest)
+	if err != nil {
+		return policy, fmt.Errorf("failed to fetch version policy: %w", err)
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		return policy, fmt.Errorf("failed to fetch version policy: %w", classifyNetworkError(url, err))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return policy, fmt.Errorf("failed to fetch version policy: %w", statusError(url, resp))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&policy); err != nil {
+		return policy, fmt.Errorf("failed to parse version policy from %s: %v", url, err)
+	}
+	return policy, nil
+}
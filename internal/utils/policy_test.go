@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestFetchPolicyCache(t *testing.T) {
+	testHome(t, nil)
+	fake := useFakeClock(t)
+	const url = "https://policy.test/go.json"
+	up := true
+	requests := fakeNetwork(t, func(w http.ResponseWriter, r *http.Request) {
+		if !up {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"minimum": "1.21"}`))
+	})
+	fetch := func(wantRequests int) {
+		t.Helper()
+		policy, err := FetchPolicy(url)
+		if err != nil || policy.Minimum != "1.21" {
+			t.Fatalf("FetchPolicy() = %+v, %v", policy, err)
+		}
+		if got := len(requests()); got != wantRequests {
+			t.Fatalf("%d requests made, want %d", got, wantRequests)
+		}
+	}
+	fetch(1)
+	// Within the TTL the cached copy answers alone
+	fake.Advance(defaultPolicyTTL / 2)
+	fetch(1)
+	// Offline the cached copy is used whatever its age
+	fake.Advance(defaultPolicyTTL)
+	SetOffline(true)
+	fetch(1)
+	SetOffline(false)
+	// Past the TTL it is fetched again, and kept when that fails
+	up = false
+	fetch(2)
+	up = true
+	fetch(3)
+}
+
+func TestFetchPolicyOfflineWithoutCache(t *testing.T) {
+	testHome(t, nil)
+	requests := fakeNetwork(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	})
+	SetOffline(true)
+	if _, err := FetchPolicy("https://policy.test/go.json"); err == nil {
+		t.Fatal("FetchPolicy() succeeded offline with nothing cached")
+	}
+	if got := requests(); len(got) != 0 {
+		t.Errorf("requests made offline: %v", got)
+	}
+}
@@ -0,0 +1,319 @@
+package utils
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/melkeydev/govm/internal/clock"
+)
+
+// exportMetadataFile is the first entry of an exported toolchain archive.
+const exportMetadataFile = "govm-export.json"
+
+// ExportMetadata describes the toolchain inside an exported archive.
+type ExportMetadata struct {
+	Version  string    `json:"version"`
+	GOOS     string    `json:"goos"`
+	GOARCH   string    `json:"goarch"`
+	Exported time.Time `json:"exported"`
+}
+
+// ExportVersion packages an installed toolchain, with metadata, into a
+// .tar.gz or .tar.zst archive at dest. Zstandard compression uses the zstd
+// command.
+func ExportVersion(version GoVersion, dest string) error {
+	if !IsCompleteInstall(version.Path) {
+		return fmt.Errorf("Go %s is not fully installed at %s", version.Version, version.Path)
+	}
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	var w io.WriteCloser
+	var compressor *exec.Cmd
+	switch {
+	case strings.HasSuffix(dest, ".tar.gz") || strings.HasSuffix(dest, ".tgz"):
+		w = gzip.NewWriter(out)
+	case strings.HasSuffix(dest, ".tar.zst"):
+		compressor = exec.Command("zstd", "-q", "-c")
+		compressor.Stdout = out
+		compressor.Stderr = os.Stderr
+		if w, err = compressor.StdinPipe(); err != nil {
+			return err
+		}
+		if err := compressor.Start(); err != nil {
+			return fmt.Errorf("zstd is required for .tar.zst archives: %v", err)
+		}
+	default:
+		return fmt.Errorf("unsupported archive extension for %s; use .tar.gz or .tar.zst", dest)
+	}
+	if err := writeExport(w, version); err != nil {
+		w.Close()
+		if compressor != nil {
+			compressor.Wait()
+		}
+		os.Remove(dest)
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if compressor != nil {
+		if err := compressor.Wait(); err != nil {
+			return fmt.Errorf("zstd failed: %v", err)
+		}
+	}
+	return out.Close()
+}
+
+func writeExport(w io.Writer, version GoVersion) error {
+	tw := tar.NewWriter(w)
+	metadata, err := json.MarshalIndent(ExportMetadata{
+		Version:  version.Version,
+		GOOS:     runtime.GOOS,
+		GOARCH:   runtime.GOARCH,
+		Exported: clock.Now().UTC(),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: exportMetadataFile, Mode: 0644, Size: int64(len(metadata))}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(metadata); err != nil {
+		return err
+	}
+	err = filepath.WalkDir(version.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(version.Path, path)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		link := ""
+		if info.Mode()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(filepath.Join("go", rel))
+		if d.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// ImportVersion installs a toolchain from an archive made by ExportVersion.
+// The archive must target this platform and its version must not already be
+// installed.
+func ImportVersion(src string) (GoVersion, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return GoVersion{}, err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return GoVersion{}, err
+	}
+	defer in.Close()
+	var r io.Reader
+	switch {
+	case strings.HasSuffix(src, ".tar.gz") || strings.HasSuffix(src, ".tgz"):
+		gz, err := gzip.NewReader(in)
+		if err != nil {
+			return GoVersion{}, err
+		}
+		defer gz.Close()
+		r = gz
+	case strings.HasSuffix(src, ".tar.zst"):
+		decompressor := exec.Command("zstd", "-q", "-d", "-c")
+		decompressor.Stdin = in
+		stdout, err := decompressor.StdoutPipe()
+		if err != nil {
+			return GoVersion{}, err
+		}
+		if err := decompressor.Start(); err != nil {
+			return GoVersion{}, fmt.Errorf("zstd is required for .tar.zst archives: %v", err)
+		}
+		// Stop zstd if the archive is rejected before it has been read fully
+		defer func() {
+			decompressor.Process.Kill()
+			decompressor.Wait()
+		}()
+		r = stdout
+	default:
+		return GoVersion{}, fmt.Errorf("unsupported archive extension for %s; use .tar.gz or .tar.zst", src)
+	}
+
+	goVersionsDir := filepath.Join(homeDir, ".govm", "versions")
+	if err := os.MkdirAll(goVersionsDir, 0755); err != nil {
+		return GoVersion{}, err
+	}
+	extractDir, err := os.MkdirTemp(goVersionsDir, ".extract-")
+	if err != nil {
+		return GoVersion{}, fmt.Errorf("failed to create extraction directory: %v", err)
+	}
+	defer os.RemoveAll(extractDir)
+
+	tr := tar.NewReader(r)
+	var metadata *ExportMetadata
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return GoVersion{}, fmt.Errorf("failed to read %s: %v", src, err)
+		}
+		if header.Name == exportMetadataFile {
+			metadata = &ExportMetadata{}
+			if err := json.NewDecoder(tr).Decode(metadata); err != nil {
+				return GoVersion{}, fmt.Errorf("invalid metadata in %s: %v", src, err)
+			}
+			// The version names the directory the toolchain is moved to
+			if metadata.Version != TipVersion && !IsReleaseVersion(metadata.Version) {
+				return GoVersion{}, fmt.Errorf("invalid version %q in %s", metadata.Version, src)
+			}
+			if metadata.GOOS != runtime.GOOS || metadata.GOARCH != runtime.GOARCH {
+				return GoVersion{}, fmt.Errorf("archive holds Go %s for %s/%s, but this machine is %s/%s",
+					metadata.Version, metadata.GOOS, metadata.GOARCH, runtime.GOOS, runtime.GOARCH)
+			}
+			continue
+		}
+		if metadata == nil {
+			return GoVersion{}, fmt.Errorf("%s was not created by 'govm export'", src)
+		}
+		if err := extractEntry(tr, header, extractDir); err != nil {
+			return GoVersion{}, err
+		}
+	}
+	if metadata == nil {
+		return GoVersion{}, fmt.Errorf("%s was not created by 'govm export'", src)
+	}
+	rootPath := filepath.Join(extractDir, "go")
+	if !IsCompleteInstall(rootPath) {
+		return GoVersion{}, fmt.Errorf("archive %s has no go/bin/%s", src, goBinary)
+	}
+	if metadata.Version != TipVersion {
+		content, err := os.ReadFile(filepath.Join(rootPath, "VERSION"))
+		if err != nil {
+			return GoVersion{}, fmt.Errorf("archive %s has no go/VERSION: %v", src, err)
+		}
+		if shipped, _, _ := strings.Cut(string(content), "\n"); strings.TrimSpace(shipped) != "go"+metadata.Version {
+			return GoVersion{}, fmt.Errorf("archive %s claims Go %s but holds %s", src, metadata.Version, strings.TrimSpace(shipped))
+		}
+	}
+	versionDir := filepath.Join(goVersionsDir, "go"+metadata.Version)
+	if _, err := os.Stat(versionDir); err == nil {
+		return GoVersion{}, fmt.Errorf("Go %s is already installed", metadata.Version)
+	}
+	if err := os.Rename(rootPath, versionDir); err != nil {
+		return GoVersion{}, fmt.Errorf("failed to rename directory: %v", err)
+	}
+	markInstalled(versionDir)
+	return GoVersion{Version: metadata.Version, Path: versionDir, Installed: true}, nil
+}
+
+// extractEntry writes one tar entry below dir, refusing paths that would
+// escape it, directly or through a symlink extracted earlier.
+func extractEntry(tr *tar.Reader, header *tar.Header, dir string) error {
+	target := filepath.Join(dir, filepath.FromSlash(header.Name))
+	if !strings.HasPrefix(target, dir+string(filepath.Separator)) {
+		return fmt.Errorf("archive entry %q escapes the extraction directory", header.Name)
+	}
+	if err := checkParents(dir, target); err != nil {
+		return fmt.Errorf("archive entry %q: %v", header.Name, err)
+	}
+	mode := header.FileInfo().Mode()
+	switch header.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(target, mode.Perm()|0700)
+	case tar.TypeSymlink:
+		if filepath.IsAbs(header.Linkname) {
+			return fmt.Errorf("archive entry %q links to the absolute path %s", header.Name, header.Linkname)
+		}
+		resolved := filepath.Join(filepath.Dir(target), filepath.FromSlash(header.Linkname))
+		if !strings.HasPrefix(resolved, dir+string(filepath.Separator)) {
+			return fmt.Errorf("archive entry %q links outside the extraction directory", header.Name)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		return os.Symlink(header.Linkname, target)
+	case tar.TypeReg:
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm())
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, tr); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+	return nil
+}
+
+// checkParents fails if any existing directory between dir and target, or
+// target itself, is a symlink, which a later entry could be written through.
+func checkParents(dir, target string) error {
+	rel, err := filepath.Rel(dir, target)
+	if err != nil {
+		return err
+	}
+	path := dir
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		path = filepath.Join(path, part)
+		info, err := os.Lstat(path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return fmt.Errorf("%s is a symlink", filepath.ToSlash(strings.TrimPrefix(path, dir+string(filepath.Separator))))
+		}
+	}
+	return nil
+}
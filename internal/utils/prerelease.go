@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// preReleaseTags are the suffixes go.dev uses for unstable releases, as in
+// 1.24rc1 or 1.22beta2.
+var preReleaseTags = []string{"beta", "rc"}
+
+// SplitPreRelease splits a version such as "1.24rc1" into its release
+// "1.24" and pre-release tag "rc1". Final releases have an empty tag.
+func SplitPreRelease(version string) (string, string) {
+	for _, tag := range preReleaseTags {
+		if i := strings.Index(version, tag); i > 0 {
+			return version[:i], version[i:]
+		}
+	}
+	return version, ""
+}
+
+// releaseVersion matches the version syntax of go.dev releases, such as
+// 1.22, 1.22.3, 1.24rc1 or 1.22beta2.
+var releaseVersion = regexp.MustCompile(`^[0-9]+\.[0-9]+(\.[0-9]+)?((beta|rc)[0-9]+)?$`)
+
+// IsReleaseVersion reports whether version is spelled like a Go release.
+func IsReleaseVersion(version string) bool {
+	return releaseVersion.MatchString(version)
+}
+
+// IsPreRelease reports whether version is a beta or release candidate.
+func IsPreRelease(version string) bool {
+	_, pre := SplitPreRelease(version)
+	return pre != ""
+}
+
+// CompareVersions compares two Go versions numerically, returning -1, 0 or
+// 1. A pre-release sorts before every release of the same line, so 1.24rc2
+// comes after 1.24rc1 and before 1.24.0.
+func CompareVersions(v1, v2 string) int {
+	release1, pre1 := SplitPreRelease(v1)
+	release2, pre2 := SplitPreRelease(v2)
+	parts1 := strings.Split(release1, ".")
+	parts2 := strings.Split(release2, ".")
+	for i := 0; i < len(parts1) && i < len(parts2); i++ {
+		p1, _ := strconv.Atoi(parts1[i])
+		p2, _ := strconv.Atoi(parts2[i])
+		if p1 < p2 {
+			return -1
+		}
+		if p1 > p2 {
+			return 1
+		}
+	}
+	if len(parts1) < len(parts2) {
+		return -1
+	}
+	if len(parts1) > len(parts2) {
+		return 1
+	}
+	return comparePreRelease(pre1, pre2)
+}
+
+func comparePreRelease(pre1, pre2 string) int {
+	switch {
+	case pre1 == pre2:
+		return 0
+	case pre1 == "":
+		return 1
+	case pre2 == "":
+		return -1
+	}
+	tag1 := strings.TrimRight(pre1, "0123456789")
+	tag2 := strings.TrimRight(pre2, "0123456789")
+	if tag1 != tag2 {
+		// beta sorts before rc
+		return strings.Compare(tag1, tag2)
+	}
+	n1, _ := strconv.Atoi(pre1[len(tag1):])
+	n2, _ := strconv.Atoi(pre2[len(tag2):])
+	if n1 < n2 {
+		return -1
+	}
+	if n1 > n2 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,15 @@
+//go:build windows
+
+package utils
+
+import "os"
+
+// processRunning reports whether a process with the given pid exists.
+func processRunning(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	p.Release()
+	return true
+}
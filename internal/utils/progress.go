@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"io"
+	"time"
+
+	"github.com/melkeydev/govm/internal/clock"
+	"github.com/melkeydev/govm/internal/events"
+)
+
+// progressInterval is the least time between two progress reports.
+const progressInterval = 200 * time.Millisecond
+
+// DownloadProgressFunc is called periodically while an archive downloads.
+type DownloadProgressFunc func(events.DownloadProgressMsg)
+
+// progressReader counts the bytes read through it and reports them to
+// progress at most once per progressInterval. offset is how much of the
+// archive an earlier, interrupted download already saved; it counts toward
+// the progress but not the speed.
+type progressReader struct {
+	r        io.Reader
+	msg      events.DownloadProgressMsg
+	offset   int64
+	progress DownloadProgressFunc
+	start    time.Time
+	reported time.Time
+}
+
+func newProgressReader(r io.Reader, version string, offset, total int64, progress DownloadProgressFunc) *progressReader {
+	if total < 0 {
+		total = 0
+	}
+	now := clock.Now()
+	return &progressReader{
+		r:        r,
+		msg:      events.DownloadProgressMsg{Version: version, Downloaded: offset, Total: total},
+		offset:   offset,
+		progress: progress,
+		start:    now,
+		reported: now,
+	}
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.msg.Downloaded += int64(n)
+	if err == io.EOF && p.msg.Total == 0 {
+		// The size is known now, so the last report reads as complete
+		p.msg.Total = p.msg.Downloaded
+	}
+	if now := clock.Now(); now.Sub(p.reported) >= progressInterval || err == io.EOF {
+		p.reported = now
+		p.report(now)
+	}
+	return n, err
+}
+
+func (p *progressReader) report(now time.Time) {
+	if elapsed := now.Sub(p.start).Seconds(); elapsed > 0 {
+		p.msg.BytesPerSecond = float64(p.msg.Downloaded-p.offset) / elapsed
+	}
+	p.msg.ETA = 0
+	if p.msg.Total > 0 && p.msg.BytesPerSecond > 0 {
+		remaining := float64(p.msg.Total - p.msg.Downloaded)
+		p.msg.ETA = time.Duration(remaining / p.msg.BytesPerSecond * float64(time.Second)).Round(time.Second)
+	}
+	p.progress(p.msg)
+}
@@ -0,0 +1,198 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/config"
+)
+
+// DefaultPinFiles are the files that pin a project's version, in order of
+// precedence, unless the config names others.
+var DefaultPinFiles = []string{".go-version", ".tool-versions", "mise.toml", ".mise.toml", "go.mod"}
+
+// pinReaders extract the Go version from each kind of pin file.
+var pinReaders = map[string]func(path string) (string, error){
+	".go-version":    goVersionFileVersion,
+	".tool-versions": ToolVersionsVersion,
+	"mise.toml":      MiseVersion,
+	".mise.toml":     MiseVersion,
+	"go.mod":         goModVersion,
+}
+
+// DetectProjectVersion walks up from dir looking for a pin file: a
+// .go-version file, a .tool-versions file with a golang line, a mise.toml
+// with go in its tools, or a go.mod with a toolchain or go directive. The nearest directory with a pin wins;
+// within a directory the order comes from the project.pin_files setting. It
+// returns the version and the file it came from.
+func DetectProjectVersion(dir string) (version string, source string, err error) {
+	return detectVersion(dir, PinFiles())
+}
+
+// PinFiles returns the pin files GoVM reads, in order of precedence: the
+// project.pin_files setting, or DefaultPinFiles. Names GoVM cannot read are
+// dropped.
+func PinFiles() []string {
+	pinFiles := DefaultPinFiles
+	if cfg, err := config.Load(); err == nil && len(cfg.Project.PinFiles) > 0 {
+		pinFiles = cfg.Project.PinFiles
+	}
+	var known []string
+	for _, name := range pinFiles {
+		if _, ok := pinReaders[name]; ok {
+			known = append(known, name)
+		}
+	}
+	return known
+}
+
+// DetectGoModVersion is DetectProjectVersion restricted to go.mod: it returns
+// the toolchain, or else go, directive of the nearest module.
+func DetectGoModVersion(dir string) (version string, source string, err error) {
+	return detectVersion(dir, []string{"go.mod"})
+}
+
+func detectVersion(dir string, pinFiles []string) (version string, source string, err error) {
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+	version, source = walkProject(dir, pinFiles, func(dir string) (string, string, bool) {
+		for _, name := range pinFiles {
+			read, ok := pinReaders[name]
+			if !ok {
+				continue
+			}
+			path := filepath.Join(dir, name)
+			if v, err := read(path); err == nil && v != "" {
+				return v, path, true
+			}
+		}
+		return "", "", false
+	})
+	if source == "" {
+		names := strings.Join(pinFiles, ", ")
+		if i := strings.LastIndex(names, ", "); i >= 0 {
+			names = names[:i] + " or " + names[i+2:]
+		}
+		return "", "", fmt.Errorf("no %s found", names)
+	}
+	return version, source, nil
+}
+
+// goVersionFileVersion returns the first line of a .go-version file, the
+// only one the shims read.
+func goVersionFileVersion(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	line, _, _ := strings.Cut(string(content), "\n")
+	return strings.TrimPrefix(strings.TrimSpace(line), "go"), nil
+}
+
+// ToolVersionsVersion returns the version on the golang line of an asdf
+// .tool-versions file, or the go line mise writes. "system", which defers to
+// the Go on PATH, counts as no version.
+func ToolVersionsVersion(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && (fields[0] == "golang" || fields[0] == "go") {
+			if fields[1] == "system" {
+				return "", nil
+			}
+			return strings.TrimPrefix(fields[1], "go"), nil
+		}
+	}
+	return "", nil
+}
+
+// MiseVersion returns the Go version in the [tools] table of a mise.toml or
+// .mise.toml file, written as go = "1.22", go = ["1.22", "1.21"] (the first
+// is the default) or go = { version = "1.22" }. mise's "prefix:" form is
+// accepted; "latest", "system", "path:" and "ref:" name no version GoVM can
+// pin and count as none.
+func MiseVersion(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	table := ""
+	for _, line := range strings.Split(string(content), "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			table = strings.TrimSpace(strings.Trim(line, "[]"))
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if table != "tools" || !ok {
+			continue
+		}
+		if key = strings.Trim(strings.TrimSpace(key), `"'`); key != "go" && key != "golang" {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if strings.HasPrefix(value, "{") {
+			if i := strings.Index(value, "version"); i >= 0 {
+				_, value, _ = strings.Cut(value[i:], "=")
+			}
+		}
+		version := strings.TrimPrefix(firstQuoted(value), "prefix:")
+		if version == "latest" || version == "system" || strings.Contains(version, ":") {
+			return "", nil
+		}
+		return strings.TrimPrefix(version, "go"), nil
+	}
+	return "", nil
+}
+
+// firstQuoted returns the contents of the first quoted string in s.
+func firstQuoted(s string) string {
+	start := strings.IndexAny(s, `"'`)
+	if start < 0 {
+		return ""
+	}
+	end := strings.IndexByte(s[start+1:], s[start])
+	if end < 0 {
+		return ""
+	}
+	return s[start+1 : start+1+end]
+}
+
+// goModVersion returns the toolchain directive of a go.mod file, such as
+// "toolchain go1.22.4", falling back to its go directive. "toolchain
+// default" names no version and is skipped.
+func goModVersion(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	goDirective := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "toolchain":
+			if fields[1] != "default" {
+				return strings.TrimPrefix(fields[1], "go"), nil
+			}
+		case "go":
+			goDirective = fields[1]
+		}
+	}
+	return goDirective, scanner.Err()
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDetectProjectVersionCache(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	project := filepath.Join(home, "src", "app")
+	deep := filepath.Join(project, "cmd", "server")
+	if err := os.MkdirAll(deep, 0755); err != nil {
+		t.Fatal(err)
+	}
+	pin := filepath.Join(project, ".go-version")
+	if err := os.WriteFile(pin, []byte("1.22.4\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Lookups racing each other leave an entry a shim can still read
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if version, source, err := DetectProjectVersion(deep); err != nil || version != "1.22.4" || source != pin {
+				t.Errorf("DetectProjectVersion() = %q, %q, %v", version, source, err)
+			}
+		}()
+	}
+	wg.Wait()
+	entry, err := projectCacheEntry(projectCacheKind(DefaultPinFiles), deep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version, source, ok := readProjectCacheEntry(entry); !ok || version != "1.22.4" || source != pin {
+		t.Fatalf("cache entry = %q, %q, %v; want the pin", version, source, ok)
+	}
+	// A lookup is read-only: only commands record known projects
+	if _, err := os.Stat(filepath.Join(home, ".govm", "projects.json")); !os.IsNotExist(err) {
+		t.Errorf("lookup recorded a known project: %v", err)
+	}
+
+	// Editing the pin invalidates the cached result
+	if err := os.WriteFile(pin, []byte("1.23.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(pin, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if version, _, _ := DetectProjectVersion(deep); version != "1.23.0" {
+		t.Errorf("after editing the pin, DetectProjectVersion() = %q, want 1.23.0", version)
+	}
+}
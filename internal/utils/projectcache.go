@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/clock"
+	"github.com/melkeydev/govm/internal/term"
+)
+
+// The project cache records where a walk up from a directory found its pin,
+// so that neither govm nor the shims have to repeat it in a deep tree. Each
+// directory has its own entry file under ~/.govm/cache/projects/<kind>/,
+// at the directory's own path, holding the version and the pin file on its
+// first two lines (both empty when no pin was found) and then every path the
+// walk depended on: the directories walked and the candidate files in them.
+// A pin file appearing or disappearing changes its directory's mtime, and
+// editing one changes its own, so an entry is only used while its own mtime,
+// the time the walk started, is newer than that of every path it lists. The
+// shims can check that with test -nt alone, without parsing anything.
+const projectCacheEntryName = ".govm-pin"
+
+func projectCacheDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".govm", "cache", "projects"), nil
+}
+
+// projectCacheKind names the cache directory of lookups for pinFiles, so
+// lookups for different sets of pin files never share entries.
+func projectCacheKind(pinFiles []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(pinFiles, "\n")))
+	return hex.EncodeToString(sum[:6])
+}
+
+// projectCacheEntry returns the path of dir's entry under the cache
+// directory of kind. A drive letter becomes a directory of its own.
+func projectCacheEntry(kind, dir string) (string, error) {
+	cacheDir, err := projectCacheDir()
+	if err != nil {
+		return "", err
+	}
+	if volume := filepath.VolumeName(dir); volume != "" {
+		dir = strings.TrimSuffix(volume, ":") + string(filepath.Separator) + dir[len(volume):]
+	}
+	return filepath.Join(cacheDir, kind, dir, projectCacheEntryName), nil
+}
+
+// ClearProjectCache removes every cached project lookup, including the
+// single-file cache older releases kept.
+func ClearProjectCache() error {
+	cacheDir, err := projectCacheDir()
+	if err != nil {
+		return err
+	}
+	legacy := filepath.Join(filepath.Dir(filepath.Dir(cacheDir)), "project_cache.json")
+	if err := os.Remove(legacy); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.RemoveAll(cacheDir)
+}
+
+// walkProject walks up from the absolute directory dir, calling find in each
+// directory until it reports a match, and returns the version and source it
+// found; both are empty when no directory matched. pinFiles are the files
+// find reads in each directory. The result is cached per directory, so
+// repeated lookups only stat the paths the walk depended on. A lookup writes
+// nothing but its own cache entry; commands that act on a project record it
+// with RecordProject.
+func walkProject(dir string, pinFiles []string, find func(dir string) (version, source string, ok bool)) (string, string) {
+	entryPath, cacheErr := projectCacheEntry(projectCacheKind(pinFiles), dir)
+	if cacheErr == nil {
+		if version, source, ok := readProjectCacheEntry(entryPath); ok {
+			return version, source
+		}
+	}
+
+	// File mtimes come from the real clock, so the entry's must too
+	start := clock.Real{}.Now()
+	version, source := "", ""
+	var watched []string
+	for {
+		for _, path := range append([]string{dir}, joinAll(dir, pinFiles)...) {
+			if _, err := os.Stat(path); err == nil {
+				watched = append(watched, path)
+			}
+		}
+		if v, s, ok := find(dir); ok {
+			version, source = v, s
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	if cacheErr == nil && !strings.ContainsAny(version+source+strings.Join(watched, ""), "\r\n") {
+		content := strings.Join(append([]string{version, source}, watched...), "\n") + "\n"
+		err := os.MkdirAll(filepath.Dir(entryPath), 0755)
+		if err == nil {
+			// Shims in other processes read the entry while this one writes
+			// it, so it is replaced whole rather than rewritten in place
+			err = writeFileAtomicAt(entryPath, []byte(content), 0644, start)
+		}
+		if err != nil {
+			term.Debugf("failed to cache the project lookup: %v", err)
+		}
+	}
+	return version, source
+}
+
+// readProjectCacheEntry returns the version and source recorded in the
+// entry at entryPath, reporting false when there is none or it is stale.
+func readProjectCacheEntry(entryPath string) (string, string, bool) {
+	info, err := os.Stat(entryPath)
+	if err != nil || !info.Mode().IsRegular() {
+		return "", "", false
+	}
+	content, err := os.ReadFile(entryPath)
+	if err != nil {
+		return "", "", false
+	}
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if len(lines) < 3 {
+		return "", "", false
+	}
+	for _, path := range lines[2:] {
+		watched, err := os.Stat(path)
+		if err != nil || !info.ModTime().After(watched.ModTime()) {
+			return "", "", false
+		}
+	}
+	return lines[0], lines[1], true
+}
+
+func joinAll(dir string, names []string) []string {
+	paths := make([]string, len(names))
+	for i, name := range names {
+		paths[i] = filepath.Join(dir, name)
+	}
+	return paths
+}
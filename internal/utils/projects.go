@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/melkeydev/govm/internal/clock"
+	"github.com/melkeydev/govm/internal/events"
+)
+
+// maxProjects is how many known projects are remembered; the ones seen least
+// recently are forgotten first.
+const maxProjects = 200
+
+// KnownProject is a directory GoVM has found a pin file in.
+type KnownProject struct {
+	Dir      string    `json:"dir"`
+	PinFile  string    `json:"pin_file"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// Project is a known project with the version its pin file names now; it
+// is defined with the messages that carry it.
+type Project = events.Project
+
+var projectsMu sync.Mutex
+
+func projectsPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %v", err)
+	}
+	return filepath.Join(homeDir, ".govm", "projects.json"), nil
+}
+
+func readKnownProjects(path string) []KnownProject {
+	var projects []KnownProject
+	if content, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(content, &projects)
+	}
+	return projects
+}
+
+// RecordProject remembers the directory of pinFile as a known project.
+func RecordProject(pinFile string) error {
+	path, err := projectsPath()
+	if err != nil {
+		return err
+	}
+	projectsMu.Lock()
+	defer projectsMu.Unlock()
+	dir := filepath.Dir(pinFile)
+	projects := []KnownProject{{Dir: dir, PinFile: pinFile, LastSeen: clock.Now().UTC()}}
+	for _, p := range readKnownProjects(path) {
+		if p.Dir != dir {
+			projects = append(projects, p)
+		}
+	}
+	sort.SliceStable(projects, func(i, j int) bool { return projects[i].LastSeen.After(projects[j].LastSeen) })
+	if len(projects) > maxProjects {
+		projects = projects[:maxProjects]
+	}
+	content, err := json.MarshalIndent(projects, "", "  ")
+	if err != nil {
+		return err
+	}
+	os.MkdirAll(filepath.Dir(path), 0755)
+	return writeFileAtomic(path, content, 0644)
+}
+
+// KnownProjects returns the remembered projects whose pin file still names
+// a version, most recently seen first.
+func KnownProjects() ([]Project, error) {
+	path, err := projectsPath()
+	if err != nil {
+		return nil, err
+	}
+	projectsMu.Lock()
+	known := readKnownProjects(path)
+	projectsMu.Unlock()
+	var projects []Project
+	for _, p := range known {
+		read, ok := pinReaders[filepath.Base(p.PinFile)]
+		if !ok {
+			continue
+		}
+		if version, err := read(p.PinFile); err == nil && version != "" {
+			projects = append(projects, Project{Dir: p.Dir, PinFile: p.PinFile, Version: version})
+		}
+	}
+	return projects, nil
+}
+
+// LoadProjects reads the known projects for the TUI.
+func LoadProjects() tea.Msg {
+	projects, err := KnownProjects()
+	if err != nil {
+		return events.ErrMsgf("failed to read known projects: %v", err)
+	}
+	return events.ProjectsMsg(projects)
+}
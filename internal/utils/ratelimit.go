@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/melkeydev/govm/internal/clock"
+	"github.com/melkeydev/govm/internal/config"
+)
+
+// LimitRate caps archive downloads at this many bytes per second. It is set
+// from the global --limit-rate flag and overrides download.limit_rate; 0
+// leaves the config in charge.
+var LimitRate int64
+
+// ParseRate parses a download rate such as "500K" or "5M": a number of
+// bytes per second with an optional K, M or G suffix in powers of 1024, as
+// curl's --limit-rate takes it.
+func ParseRate(rate string) (int64, error) {
+	s := strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(rate)), "/S")
+	s = strings.TrimSuffix(s, "B")
+	multiplier := int64(1)
+	if s != "" {
+		switch s[len(s)-1] {
+		case 'K':
+			multiplier = 1 << 10
+		case 'M':
+			multiplier = 1 << 20
+		case 'G':
+			multiplier = 1 << 30
+		}
+		if multiplier > 1 {
+			s = s[:len(s)-1]
+		}
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid rate %q: expected bytes per second such as 500K or 5M", rate)
+	}
+	return int64(n * float64(multiplier)), nil
+}
+
+// downloadRateLimit returns the bytes per second archive downloads are
+// capped at, or 0 for no cap.
+func downloadRateLimit() (int64, error) {
+	if LimitRate > 0 {
+		return LimitRate, nil
+	}
+	cfg, err := config.Load()
+	if err != nil || cfg.Download.LimitRate == "" {
+		return 0, nil
+	}
+	rate, err := ParseRate(cfg.Download.LimitRate)
+	if err != nil {
+		return 0, fmt.Errorf("download.limit_rate: %v", err)
+	}
+	return rate, nil
+}
+
+// rateLimitedReader reads no faster than rate bytes per second on average,
+// sleeping whenever it gets ahead.
+type rateLimitedReader struct {
+	r     io.Reader
+	rate  int64
+	read  int64
+	start time.Time
+}
+
+func newRateLimitedReader(r io.Reader, rate int64) *rateLimitedReader {
+	return &rateLimitedReader{r: r, rate: rate, start: clock.Now()}
+}
+
+func (l *rateLimitedReader) Read(b []byte) (int, error) {
+	// Small reads keep the pace even rather than bursting a buffer at a time
+	if chunk := max(l.rate/10, 1); int64(len(b)) > chunk {
+		b = b[:chunk]
+	}
+	n, err := l.r.Read(b)
+	l.read += int64(n)
+	due := time.Duration(float64(l.read) / float64(l.rate) * float64(time.Second))
+	if wait := due - clock.Since(l.start); wait > 0 {
+		clock.Sleep(wait)
+	}
+	return n, err
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const releaseHistoryURL = "https://go.dev/doc/devel/release"
+
+var (
+	releaseParagraph = regexp.MustCompile(`(?s)<p id="go([0-9][0-9.a-z]*)">(.*?)</p>`)
+	htmlTag          = regexp.MustCompile(`<[^>]+>`)
+)
+
+// ReleaseNotesURL returns the release notes page for a minor version.
+func ReleaseNotesURL(minor string) string {
+	return "https://go.dev/doc/go" + minor
+}
+
+// FetchReleaseSummaries returns the one-line summary go.dev publishes for
+// each release in its release history, keyed by version.
+func FetchReleaseSummaries() (map[string]string, error) {
+	client, err := newHTTPClient(metadataRequest)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Get(releaseHistoryURL)
+	if err != nil {
+		return nil, classifyNetworkError(releaseHistoryURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(releaseHistoryURL, resp)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	summaries := map[string]string{}
+	for _, match := range releaseParagraph.FindAllStringSubmatch(string(body), -1) {
+		text := html.UnescapeString(htmlTag.ReplaceAllString(match[2], ""))
+		text = strings.Join(strings.Fields(text), " ")
+		// Keep the first sentence; the rest points at the issue tracker
+		if i := strings.Index(text, ". "); i >= 0 {
+			text = text[:i+1]
+		}
+		summaries[match[1]] = text
+	}
+	return summaries, nil
+}
@@ -0,0 +1,692 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ShimPath returns the location of the shim script for the given tool.
+func ShimPath(tool string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %v", err)
+	}
+	shimPath := filepath.Join(homeDir, ".govm", "shim", tool)
+	if runtime.GOOS == "windows" {
+		shimPath += ".bat"
+	}
+	return shimPath, nil
+}
+
+// ShimFormat is the kind of script a shim is written as.
+type ShimFormat int
+
+const (
+	ShimBash ShimFormat = iota
+	ShimBatch
+	ShimPowerShell
+)
+
+// DefaultShimFormat returns the shim format used on the given GOOS.
+func DefaultShimFormat(goos string) ShimFormat {
+	if goos == "windows" {
+		return ShimBatch
+	}
+	return ShimBash
+}
+
+// Extension returns the file extension shims of this format need.
+func (f ShimFormat) Extension() string {
+	switch f {
+	case ShimBatch:
+		return ".bat"
+	case ShimPowerShell:
+		return ".ps1"
+	}
+	return ""
+}
+
+// ShimScript returns the contents of a shim that runs the same tool of the
+// first version named by, in order, GOVM_GO_VERSION, GOVM_SHELL_VERSION or
+// the nearest of pinFiles in the working directory or its parents, and
+// targetBin, the global version, otherwise. It has no side effects so the
+// exact bytes can be checked. targetBin must stay the first quoted string;
+// ShimTarget relies on it.
+func ShimScript(targetBin string, pinFiles []string, format ShimFormat) []byte {
+	tool := strings.TrimSuffix(filepath.Base(targetBin), ".exe")
+	kind := projectCacheKind(pinFiles)
+	switch format {
+	case ShimBatch:
+		return []byte(fmt.Sprintf(batchShim, targetBin, tool, strings.Join(pinFiles, " ")))
+	case ShimPowerShell:
+		quoted := make([]string, len(pinFiles))
+		for i, name := range pinFiles {
+			quoted[i] = `"` + name + `"`
+		}
+		return []byte(fmt.Sprintf(powerShellShim, targetBin, tool, strings.Join(quoted, ", "), kind))
+	}
+	return []byte(fmt.Sprintf(bashShim, targetBin, tool, strings.Join(pinFiles, " "), kind))
+}
+
+// VersionEnv names the environment variable that overrides every other way
+// of selecting a version in the shims.
+const VersionEnv = "GOVM_GO_VERSION"
+
+// ShellVersionEnv names the environment variable 'govm shell' sets for the
+// current shell session. It overrides project pins and the global version
+// but yields to VersionEnv.
+const ShellVersionEnv = "GOVM_SHELL_VERSION"
+
+// The shim templates take the global target, the tool name, the pin files
+// to look for, in order, and the project cache kind for those pin files. A
+// pin such as 1.22 selects the highest installed 1.22.x; a pin that is not
+// installed falls back to the global target with a warning on stderr. Each
+// reads the pin files the way DetectProjectVersion does, so the shims and
+// govm agree on the version. The bash and PowerShell shims first look for
+// the current directory's entry in the project cache govm keeps and only
+// walk up themselves without a fresh one; they never write the cache. The
+// batch shim cannot compare mtimes and always walks.
+const bashShim = `#!/usr/bin/env bash
+target="%[1]s"
+versions="$HOME/.govm/versions"
+# read_pin sets pin to the version the pin file $1 names, if any
+read_pin() {
+	local line key value rest quote table=""
+	pin=""
+	case "${1##*/}" in
+	.go-version)
+		read -r pin < "$1"
+		pin="${pin%%$'\r'}"
+		;;
+	.tool-versions)
+		while read -r key value _ || [ -n "$key" ]; do
+			value="${value%%%%#*}"
+			value="${value%%$'\r'}"
+			[ -n "$value" ] || continue
+			case "$key" in
+			golang | go)
+				[ "$value" = system ] || pin="$value"
+				break
+				;;
+			esac
+		done < "$1"
+		;;
+	mise.toml | .mise.toml)
+		while IFS= read -r line || [ -n "$line" ]; do
+			line="${line%%%%#*}"
+			line="${line#"${line%%%%[![:space:]]*}"}"
+			case "$line" in
+			"["*)
+				table="${line//[][[:space:]]/}"
+				continue
+				;;
+			*=*) ;;
+			*) continue ;;
+			esac
+			key="${line%%%%=*}"
+			key="${key//[[:space:]\"\']/}"
+			[ "$table" = tools ] || continue
+			case "$key" in go | golang) ;; *) continue ;; esac
+			value="${line#*=}"
+			value="${value#"${value%%%%[![:space:]]*}"}"
+			case "$value" in "{"*version*)
+				value="${value#*version}"
+				value="${value#*=}"
+				;;
+			esac
+			rest="${value#*[\"\']}"
+			if [ "$rest" != "$value" ]; then
+				quote="${value:$((${#value} - ${#rest} - 1)):1}"
+				case "$rest" in *"$quote"*) value="${rest%%%%"$quote"*}" ;; *) value="" ;; esac
+			else
+				value=""
+			fi
+			value="${value#prefix:}"
+			case "$value" in latest | system | *:*) ;; *) pin="$value" ;; esac
+			break
+		done < "$1"
+		;;
+	go.mod)
+		local directive=""
+		while read -r key value _ || [ -n "$key" ]; do
+			value="${value%%$'\r'}"
+			case "$key" in
+			toolchain) [ "$value" = default ] || [ -z "$value" ] || { directive="$value"; break; } ;;
+			go) [ -z "$value" ] || directive="$value" ;;
+			esac
+		done < "$1"
+		pin="$directive"
+		;;
+	esac
+	pin="${pin#go}"
+}
+pin="${GOVM_GO_VERSION#go}"
+origin="GOVM_GO_VERSION"
+if [ -z "$pin" ]; then
+	pin="${GOVM_SHELL_VERSION#go}"
+	origin="GOVM_SHELL_VERSION"
+fi
+cached=""
+if [ -z "$pin" ]; then
+	entry="$HOME/.govm/cache/projects/%[4]s$PWD/.govm-pin"
+	if [ -f "$entry" ]; then
+		{
+			IFS= read -r cached_pin
+			IFS= read -r cached_origin
+			while IFS= read -r path; do
+				if [ -e "$path" ] && [ "$entry" -nt "$path" ]; then
+					cached=1
+				else
+					cached=""
+					break
+				fi
+			done
+		} < "$entry"
+	fi
+	if [ -n "$cached" ] && [ -n "$cached_pin" ]; then
+		pin="${cached_pin#go}"
+		origin="$cached_origin"
+	fi
+fi
+if [ -z "$pin" ] && [ -z "$cached" ]; then
+	dir="$PWD"
+	while :; do
+		for name in %[3]s; do
+			if [ -f "$dir/$name" ]; then
+				read_pin "$dir/$name"
+				if [ -n "$pin" ]; then
+					origin="$dir/$name"
+					break 2
+				fi
+			fi
+		done
+		[ -n "$dir" ] || break
+		dir="${dir%%/*}"
+	done
+fi
+if [ -n "$pin" ]; then
+	if [ -x "$versions/go$pin/bin/%[2]s" ]; then
+		target="$versions/go$pin/bin/%[2]s"
+	else
+		best=-1
+		for candidate in "$versions/go$pin".*; do
+			patch="${candidate##*/go$pin.}"
+			case "$patch" in "" | *[!0-9]*) continue ;; esac
+			if [ "$patch" -gt "$best" ] && [ -x "$candidate/bin/%[2]s" ]; then
+				best="$patch"
+				target="$candidate/bin/%[2]s"
+			fi
+		done
+		if [ "$best" -lt 0 ]; then
+			echo "govm: $origin pins Go $pin, which is not installed; using $target" >&2
+		fi
+	fi
+fi
+exec "$target" "$@"
+`
+
+const batchShim = `@echo off
+setlocal
+set target="%[1]s"
+set "versions=%%USERPROFILE%%\.govm\versions"
+set "pin=%%GOVM_GO_VERSION%%"
+set "origin=GOVM_GO_VERSION"
+if defined pin goto resolve
+set "pin=%%GOVM_SHELL_VERSION%%"
+set "origin=GOVM_SHELL_VERSION"
+if defined pin goto resolve
+set "dir=%%CD%%"
+:walk
+for %%%%N in (%[3]s) do if not defined pin if exist "%%dir%%\%%%%N" call :readpin "%%dir%%\%%%%N"
+if defined pin goto resolve
+for %%%%D in ("%%dir%%\..") do set "parent=%%%%~fD"
+if "%%parent%%"=="%%dir%%" goto run
+set "dir=%%parent%%"
+goto walk
+:resolve
+if not defined pin goto run
+if "%%pin:~0,2%%"=="go" set "pin=%%pin:~2%%"
+if exist "%%versions%%\go%%pin%%\bin\%[2]s.exe" (
+	set target="%%versions%%\go%%pin%%\bin\%[2]s"
+	goto run
+)
+set best=-1
+for /d %%%%V in ("%%versions%%\go%%pin%%.*") do call :consider "%%%%V"
+if %%best%% LSS 0 echo govm: %%origin%% pins Go %%pin%%, which is not installed; using %%target%% 1>&2
+:run
+%%target%% %%*
+exit /b %%errorlevel%%
+:consider
+set "patch=%%~x1"
+set "patch=%%patch:~1%%"
+if not exist "%%~1\bin\%[2]s.exe" exit /b
+if %%patch%% GTR %%best%% (
+	set best=%%patch%%
+	set target="%%~1\bin\%[2]s"
+)
+exit /b
+:readpin
+set "pin="
+set "found="
+set "table="
+set "name=%%~nx1"
+if "%%name%%"==".go-version" goto readgoversion
+if "%%name%%"==".tool-versions" goto readtoolversions
+if "%%name%%"=="mise.toml" goto readmise
+if "%%name%%"==".mise.toml" goto readmise
+if "%%name%%"=="go.mod" goto readgomod
+exit /b
+:readgoversion
+set /p pin=<"%%~1"
+goto pinread
+:readtoolversions
+for /f "usebackq eol=# tokens=1,2" %%%%A in ("%%~1") do (
+	if not defined found if "%%%%A"=="golang" set "found=%%%%B"
+	if not defined found if "%%%%A"=="go" set "found=%%%%B"
+)
+if not "%%found%%"=="system" set "pin=%%found%%"
+goto pinread
+:readmise
+for /f "usebackq eol=# tokens=1,* delims==" %%%%A in ("%%~1") do if not defined found (
+	set "key=.%%%%A"
+	set "value=%%%%B"
+	call :miseline
+)
+if not defined pin goto pinread
+set "pin=%%pin:prefix:=%%"
+if "%%pin%%"=="latest" set "pin="
+if "%%pin%%"=="system" set "pin="
+if not defined pin goto pinread
+if not "%%pin::=%%"=="%%pin%%" set "pin="
+goto pinread
+:miseline
+set "key=%%key: =%%"
+set "key=%%key:	=%%"
+set "key=%%key:"=%%"
+set "key=%%key:'=%%"
+if defined value goto misekey
+set "table=%%key:[=%%"
+set "table=%%table:]=%%"
+exit /b
+:misekey
+if not "%%table%%"==".tools" exit /b
+if not "%%key%%"==".go" if not "%%key%%"==".golang" exit /b
+set "found=1"
+setlocal EnableDelayedExpansion
+set "value=!value:'="!"
+set "quoted="
+for /f tokens^=2^ delims^=^" %%%%Q in (".!value!") do set "quoted=%%%%Q"
+endlocal & set "pin=%%quoted%%"
+exit /b
+:readgomod
+for /f "usebackq tokens=1,2" %%%%A in ("%%~1") do (
+	if not defined found if "%%%%A"=="toolchain" if not "%%%%B"=="default" set "found=%%%%B"
+	if "%%%%A"=="go" set "pin=%%%%B"
+)
+if defined found set "pin=%%found%%"
+goto pinread
+:pinread
+if not defined pin exit /b
+if "%%pin:~0,2%%"=="go" set "pin=%%pin:~2%%"
+if defined pin set "origin=%%~1"
+exit /b
+`
+
+const powerShellShim = `$target = "%[1]s"
+$versions = Join-Path $HOME ".govm\versions"
+# Read-Pin returns the version the pin file $path names, if any
+function Read-Pin($path) {
+    $name = Split-Path -Leaf $path
+    $pin = ""
+    if ($name -eq ".go-version") {
+        $pin = ([string](Get-Content $path -TotalCount 1)).Trim()
+    } elseif ($name -eq ".tool-versions") {
+        foreach ($line in Get-Content $path) {
+            $fields = -split ($line -replace '#.*', '')
+            if ($fields.Count -ge 2 -and ($fields[0] -eq "golang" -or $fields[0] -eq "go")) {
+                if ($fields[1] -ne "system") { $pin = $fields[1] }
+                break
+            }
+        }
+    } elseif ($name -eq "mise.toml" -or $name -eq ".mise.toml") {
+        $table = ""
+        foreach ($line in Get-Content $path) {
+            $line = ($line -replace '#.*', '').Trim()
+            if ($line.StartsWith("[")) {
+                $table = $line.Trim("[]").Trim()
+                continue
+            }
+            if ($table -ne "tools" -or $line -cnotmatch '^["'']?(go|golang)["'']?\s*=(.*)$') { continue }
+            $value = $Matches[2].Trim()
+            if ($value.StartsWith("{") -and $value -match 'version\s*=(.*)$') { $value = $Matches[1] }
+            if ($value -match '(["''])(.*?)\1') { $pin = $Matches[2] -replace '^prefix:', '' }
+            if ($pin -eq "latest" -or $pin -eq "system" -or $pin.Contains(":")) { $pin = "" }
+            break
+        }
+    } elseif ($name -eq "go.mod") {
+        foreach ($line in Get-Content $path) {
+            $fields = -split ($line -replace '//.*', '')
+            if ($fields.Count -lt 2) { continue }
+            if ($fields[0] -eq "toolchain" -and $fields[1] -ne "default") {
+                $pin = $fields[1]
+                break
+            }
+            if ($fields[0] -eq "go") { $pin = $fields[1] }
+        }
+    }
+    return $pin -replace '^go', ''
+}
+$pin = $env:GOVM_GO_VERSION -replace '^go', ''
+$origin = "GOVM_GO_VERSION"
+if (-not $pin) {
+    $pin = $env:GOVM_SHELL_VERSION -replace '^go', ''
+    $origin = "GOVM_SHELL_VERSION"
+}
+if (-not $pin) {
+    $dir = (Get-Location).ProviderPath
+    $cached = $false
+    if ($dir -match '^([A-Za-z]):(.*)$') {
+        $entry = Join-Path $HOME ".govm\cache\projects\%[4]s\$($Matches[1])$($Matches[2])\.govm-pin"
+        if (Test-Path -LiteralPath $entry -PathType Leaf) {
+            $lines = @(Get-Content -LiteralPath $entry)
+            $written = (Get-Item -LiteralPath $entry).LastWriteTimeUtc
+            $cached = $lines.Count -ge 3
+            foreach ($path in ($lines | Select-Object -Skip 2)) {
+                if (-not (Test-Path -LiteralPath $path) -or (Get-Item -LiteralPath $path).LastWriteTimeUtc -ge $written) {
+                    $cached = $false
+                    break
+                }
+            }
+            if ($cached -and $lines[0]) {
+                $pin = $lines[0] -replace '^go', ''
+                $origin = $lines[1]
+            }
+        }
+    }
+    while (-not $cached -and $dir -and -not $pin) {
+        foreach ($name in @(%[3]s)) {
+            $pinFile = Join-Path $dir $name
+            if (Test-Path -PathType Leaf $pinFile) {
+                $pin = Read-Pin $pinFile
+                if ($pin) {
+                    $origin = $pinFile
+                    break
+                }
+            }
+        }
+        $dir = Split-Path -Parent $dir
+    }
+}
+if ($pin) {
+    $exact = Join-Path $versions "go$pin\bin\%[2]s"
+    if (Test-Path "$exact.exe") {
+        $target = $exact
+    } else {
+        $best = Get-ChildItem $versions -Directory -Filter "go$pin.*" -ErrorAction SilentlyContinue |
+            Where-Object { $_.Name.Substring($pin.Length + 3) -match '^\d+$' -and (Test-Path (Join-Path $_.FullName "bin\%[2]s.exe")) } |
+            Sort-Object { [int]$_.Name.Substring($pin.Length + 3) } |
+            Select-Object -Last 1
+        if ($best) {
+            $target = Join-Path $best.FullName "bin\%[2]s"
+        } else {
+            [Console]::Error.WriteLine("govm: $origin pins Go $pin, which is not installed; using $target")
+        }
+    }
+}
+& $target @args
+exit $LASTEXITCODE
+`
+
+// WriteShim creates or replaces the shim for binName in shimDir so that it
+// forwards to targetBin. A shim that is already correct is left untouched.
+func WriteShim(shimDir, binName, targetBin string) error {
+	if _, err := writeShim(shimDir, binName, targetBin); err != nil {
+		return err
+	}
+	return recordShims(map[string]string{binName: targetBin})
+}
+
+// writeShim is WriteShim, also reporting whether the shim had to be written.
+func writeShim(shimDir, binName, targetBin string) (bool, error) {
+	format := DefaultShimFormat(runtime.GOOS)
+	shimPath := filepath.Join(shimDir, binName) + format.Extension()
+	script := ShimScript(targetBin, PinFiles(), format)
+	// Rewriting is slow on network home directories, so skip shims that
+	// already have the right content and are executable
+	if info, err := os.Stat(shimPath); err == nil && info.Mode().Perm()&0100 != 0 {
+		if existing, err := os.ReadFile(shimPath); err == nil && bytes.Equal(existing, script) {
+			return false, nil
+		}
+	}
+	os.Remove(shimPath)
+	if err := os.WriteFile(shimPath, script, 0755); err != nil {
+		return false, fmt.Errorf("failed to create shim for %s: %v", binName, err)
+	}
+	if err := os.Chmod(shimPath, 0755); err != nil {
+		return false, fmt.Errorf("failed to make shim executable: %v", err)
+	}
+	return true, nil
+}
+
+// RewriteShims regenerates the shims for version's binaries without
+// recording a switch, recreating the shim directory if it was removed and
+// restoring the executable bit on any shim that lost it.
+func RewriteShims(version GoVersion) error {
+	if err := SetupShimDirectory(); err != nil {
+		return err
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get home directory: %v", err)
+	}
+	shimDir := filepath.Join(homeDir, ".govm", "shim")
+	return writeShims(shimDir, filepath.Join(version.Path, "bin"), nil)
+}
+
+// ShimProgressFunc is called as each shim is handled during a switch, with
+// the number handled so far, the total, the tool's name and whether its shim
+// had to be rewritten.
+type ShimProgressFunc func(done, total int, tool string, written bool)
+
+// maxShimWriters bounds how many shims are written at once.
+const maxShimWriters = 8
+
+// writeShims points a shim for every binary in binDir at that binary,
+// writing up to maxShimWriters shims concurrently. progress may be nil.
+func writeShims(shimDir, binDir string, progress ShimProgressFunc) error {
+	entries, err := os.ReadDir(binDir)
+	if err != nil {
+		return fmt.Errorf("failed to read bin directory: %v", err)
+	}
+	var binNames []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			binNames = append(binNames, strings.Trim(entry.Name(), ".exe"))
+		}
+	}
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		done     int
+		firstErr error
+		targets  = map[string]string{}
+	)
+	sem := make(chan struct{}, maxShimWriters)
+	for _, binName := range binNames {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(binName string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			targetBin := filepath.Join(binDir, binName)
+			written, err := writeShim(shimDir, binName, targetBin)
+			mu.Lock()
+			defer mu.Unlock()
+			done++
+			targets[binName] = targetBin
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			if progress != nil {
+				progress(done, len(binNames), binName, written)
+			}
+		}(binName)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return recordShims(targets)
+}
+
+// ShimTarget returns the binary a tool's shim forwards to.
+func ShimTarget(tool string) (string, error) {
+	shimPath, err := ShimPath(tool)
+	if err != nil {
+		return "", err
+	}
+	content, err := os.ReadFile(shimPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("no shim found for %s", tool)
+		}
+		return "", fmt.Errorf("failed to read shim for %s: %v", tool, err)
+	}
+	// Shims invoke their target as the first quoted string in the script
+	parts := strings.SplitN(string(content), `"`, 3)
+	if len(parts) < 3 || parts[1] == "" {
+		return "", fmt.Errorf("could not find target in shim %s", shimPath)
+	}
+	return parts[1], nil
+}
+
+// ShimResolution is what a shim runs when invoked from a given directory.
+type ShimResolution struct {
+	Target string
+	// PinSource is VersionEnv, ShellVersionEnv or the pin file that selects
+	// the version, empty when the global version applies.
+	PinSource string
+	Pin       string
+	// PinInstalled is false when the pinned version is missing and the shim
+	// falls back to the global target.
+	PinInstalled bool
+}
+
+// ResolveShim works out which binary tool's shim runs from dir, following
+// the same rules as the shim scripts written by ShimScript.
+func ResolveShim(tool, dir string) (ShimResolution, error) {
+	target, err := ShimTarget(tool)
+	if err != nil {
+		return ShimResolution{}, err
+	}
+	res := ShimResolution{Target: target}
+	if pin := strings.TrimPrefix(os.Getenv(VersionEnv), "go"); pin != "" {
+		res.Pin, res.PinSource = pin, VersionEnv
+	} else if pin := strings.TrimPrefix(os.Getenv(ShellVersionEnv), "go"); pin != "" {
+		res.Pin, res.PinSource = pin, ShellVersionEnv
+	} else if pin, source, err := DetectProjectVersion(dir); err == nil {
+		res.Pin, res.PinSource = pin, source
+	}
+	if res.PinSource == "" {
+		return res, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return res, fmt.Errorf("failed to get home directory: %v", err)
+	}
+	versionsDir := filepath.Join(homeDir, ".govm", "versions")
+	if bin := filepath.Join(versionsDir, "go"+res.Pin, "bin", tool); binaryExists(bin) {
+		res.Target, res.PinInstalled = bin, true
+		return res, nil
+	}
+	entries, _ := os.ReadDir(versionsDir)
+	best := -1
+	for _, entry := range entries {
+		patch, ok := strings.CutPrefix(entry.Name(), "go"+res.Pin+".")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(patch)
+		bin := filepath.Join(versionsDir, entry.Name(), "bin", tool)
+		if err != nil || n <= best || !binaryExists(bin) {
+			continue
+		}
+		best = n
+		res.Target, res.PinInstalled = bin, true
+	}
+	return res, nil
+}
+
+func binaryExists(path string) bool {
+	if _, err := os.Stat(path); err == nil {
+		return true
+	}
+	_, err := os.Stat(path + ".exe")
+	return err == nil
+}
+
+// VersionFromPath extracts the Go version from a path inside the versions
+// directory, e.g. ~/.govm/versions/go1.22.4/bin/go yields "1.22.4".
+func VersionFromPath(path string) string {
+	for _, part := range strings.Split(filepath.ToSlash(path), "/") {
+		if part == "go"+TipVersion {
+			return TipVersion
+		}
+		if strings.HasPrefix(part, "go") && len(part) > 2 && part[2] >= '0' && part[2] <= '9' {
+			return strings.TrimPrefix(part, "go")
+		}
+	}
+	return ""
+}
+
+// ShimInfo describes a single shim script.
+type ShimInfo struct {
+	Tool         string
+	Path         string
+	Target       string
+	TargetExists bool
+	// Modified is set when the shim no longer matches what govm wrote.
+	Modified bool
+}
+
+// ListShims returns every shim in the shim directory.
+func ListShims() ([]ShimInfo, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %v", err)
+	}
+	shimDir := filepath.Join(homeDir, ".govm", "shim")
+	entries, err := os.ReadDir(shimDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read shim directory: %v", err)
+	}
+	records := loadShimRecords()
+	var shims []ShimInfo
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		tool := strings.TrimSuffix(entry.Name(), ".bat")
+		info := ShimInfo{Tool: tool, Path: filepath.Join(shimDir, entry.Name())}
+		info.Modified = shimModified(records, tool, info.Path)
+		if target, err := ShimTarget(tool); err == nil {
+			info.Target = target
+			_, statErr := os.Stat(target)
+			info.TargetExists = statErr == nil
+		}
+		shims = append(shims, info)
+	}
+	return shims, nil
+}
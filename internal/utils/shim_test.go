@@ -0,0 +1,402 @@
+package utils
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestShimScriptGolden(t *testing.T) {
+	tests := []struct {
+		format ShimFormat
+		target string
+		golden string
+	}{
+		{ShimBash, "/home/gopher/.govm/versions/go1.22.4/bin/go", "shim.bash"},
+		{ShimBatch, `C:\Users\gopher\.govm\versions\go1.22.4\bin\go`, "shim.bat"},
+		{ShimPowerShell, `C:\Users\gopher\.govm\versions\go1.22.4\bin\go`, "shim.ps1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			got := ShimScript(tt.target, DefaultPinFiles, tt.format)
+			path := filepath.Join("testdata", tt.golden)
+			if *update {
+				if err := os.WriteFile(path, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("ShimScript(%q) differs from %s; run go test -update if the change is intended\n%s", tt.target, path, got)
+			}
+		})
+	}
+}
+
+// shimCase describes a home with some versions installed and a project tree,
+// and which version the go shim should run from the project's subdirectory.
+type shimCase struct {
+	name      string
+	installed []string
+	global    string
+	env       string
+	shellEnv  string
+	// files maps pin files, relative to the project root, to their content
+	files map[string]string
+	// pinFiles is the project.pin_files setting
+	pinFiles  []string
+	want      string
+	wantPin   string
+	wantFound bool
+}
+
+var shimCases = []shimCase{
+	{
+		name:      "global",
+		installed: []string{"1.21.5", "1.22.4"},
+		global:    "1.22.4",
+		want:      "1.22.4",
+	},
+	{
+		name:      "nearest go-version",
+		installed: []string{"1.21.5", "1.22.4"},
+		global:    "1.22.4",
+		files:     map[string]string{".go-version": "1.22.4", "sub/.go-version": "go1.21.5\n"},
+		want:      "1.21.5",
+		wantPin:   "1.21.5",
+		wantFound: true,
+	},
+	{
+		name:      "parent go-version",
+		installed: []string{"1.21.5", "1.22.4"},
+		global:    "1.22.4",
+		files:     map[string]string{".go-version": "1.21.5\r\n"},
+		want:      "1.21.5",
+		wantPin:   "1.21.5",
+		wantFound: true,
+	},
+	{
+		name:      "shell over go-version",
+		installed: []string{"1.20.1", "1.21.5", "1.22.4"},
+		global:    "1.22.4",
+		shellEnv:  "1.20.1",
+		files:     map[string]string{"sub/.go-version": "1.21.5"},
+		want:      "1.20.1",
+		wantPin:   "1.20.1",
+		wantFound: true,
+	},
+	{
+		name:      "env over shell",
+		installed: []string{"1.20.1", "1.21.5", "1.22.4"},
+		global:    "1.22.4",
+		env:       "go1.21.5",
+		shellEnv:  "1.20.1",
+		files:     map[string]string{"sub/.go-version": "1.22.4"},
+		want:      "1.21.5",
+		wantPin:   "1.21.5",
+		wantFound: true,
+	},
+	{
+		name:      "floating patch",
+		installed: []string{"1.21.3", "1.21.10", "1.21.9", "1.22.4"},
+		global:    "1.22.4",
+		files:     map[string]string{"sub/.go-version": "1.21"},
+		want:      "1.21.10",
+		wantPin:   "1.21",
+		wantFound: true,
+	},
+	{
+		name:      "floating patch ignores prereleases",
+		installed: []string{"1.21.2", "1.21rc1", "1.22.4"},
+		global:    "1.22.4",
+		files:     map[string]string{"sub/.go-version": "1.21"},
+		want:      "1.21.2",
+		wantPin:   "1.21",
+		wantFound: true,
+	},
+	{
+		name:      "tool-versions",
+		installed: []string{"1.21.5", "1.22.4"},
+		global:    "1.22.4",
+		files:     map[string]string{"sub/.tool-versions": "nodejs 20.1.0\ngolang 1.21.5 # CI uses this too\n"},
+		want:      "1.21.5",
+		wantPin:   "1.21.5",
+		wantFound: true,
+	},
+	{
+		name:      "tool-versions system",
+		installed: []string{"1.21.5", "1.22.4"},
+		global:    "1.22.4",
+		files:     map[string]string{"sub/.tool-versions": "golang system\n", ".go-version": "1.21.5\n"},
+		want:      "1.21.5",
+		wantPin:   "1.21.5",
+		wantFound: true,
+	},
+	{
+		name:      "mise string",
+		installed: []string{"1.21.5", "1.22.4"},
+		global:    "1.22.4",
+		files:     map[string]string{"sub/mise.toml": "[env]\ngo = \"ignored\"\n\n[tools]\nnode = \"20\"\ngo = \"1.21.5\"\n"},
+		want:      "1.21.5",
+		wantPin:   "1.21.5",
+		wantFound: true,
+	},
+	{
+		name:      "mise array and prefix",
+		installed: []string{"1.21.5", "1.22.4"},
+		global:    "1.22.4",
+		files:     map[string]string{"sub/.mise.toml": "[tools]\ngo = ['prefix:1.21', \"1.22\"]\n"},
+		want:      "1.21.5",
+		wantPin:   "1.21",
+		wantFound: true,
+	},
+	{
+		name:      "mise inline table",
+		installed: []string{"1.21.5", "1.22.4"},
+		global:    "1.22.4",
+		files:     map[string]string{"sub/mise.toml": "[tools]\n\"go\" = { version = \"1.21.5\", postinstall = \"x\" }\n"},
+		want:      "1.21.5",
+		wantPin:   "1.21.5",
+		wantFound: true,
+	},
+	{
+		name:      "mise latest is no pin",
+		installed: []string{"1.21.5", "1.22.4"},
+		global:    "1.22.4",
+		files:     map[string]string{"sub/mise.toml": "[tools]\ngo = \"latest\"\n", ".go-version": "1.21.5"},
+		want:      "1.21.5",
+		wantPin:   "1.21.5",
+		wantFound: true,
+	},
+	{
+		name:      "go.mod toolchain",
+		installed: []string{"1.21.5", "1.22.4"},
+		global:    "1.22.4",
+		files:     map[string]string{"sub/go.mod": "module example.com/m\n\ngo 1.21 // minimum\n\ntoolchain go1.21.5\n\nrequire (\n\tgo.uber.org/zap v1.27.0\n)\n"},
+		want:      "1.21.5",
+		wantPin:   "1.21.5",
+		wantFound: true,
+	},
+	{
+		name:      "go.mod go directive",
+		installed: []string{"1.21.3", "1.21.5", "1.22.4"},
+		global:    "1.22.4",
+		files:     map[string]string{"sub/go.mod": "module example.com/m\n\ngo 1.21\n\ntoolchain default\n"},
+		want:      "1.21.5",
+		wantPin:   "1.21",
+		wantFound: true,
+	},
+	{
+		name:      "precedence within a directory",
+		installed: []string{"1.20.1", "1.21.5", "1.22.4"},
+		global:    "1.22.4",
+		files:     map[string]string{"sub/go.mod": "module m\ngo 1.20.1\n", "sub/.tool-versions": "golang 1.21.5\n"},
+		want:      "1.21.5",
+		wantPin:   "1.21.5",
+		wantFound: true,
+	},
+	{
+		name:      "nearest directory beats precedence",
+		installed: []string{"1.20.1", "1.21.5", "1.22.4"},
+		global:    "1.22.4",
+		files:     map[string]string{"sub/go.mod": "module m\ngo 1.20.1\n", ".go-version": "1.21.5"},
+		want:      "1.20.1",
+		wantPin:   "1.20.1",
+		wantFound: true,
+	},
+	{
+		name:      "empty go-version is skipped",
+		installed: []string{"1.21.5", "1.22.4"},
+		global:    "1.22.4",
+		files:     map[string]string{"sub/.go-version": "\n", ".go-version": "1.21.5"},
+		want:      "1.21.5",
+		wantPin:   "1.21.5",
+		wantFound: true,
+	},
+	{
+		name:      "pin_files setting",
+		installed: []string{"1.20.1", "1.21.5", "1.22.4"},
+		global:    "1.22.4",
+		pinFiles:  []string{"go.mod", ".go-version"},
+		files:     map[string]string{"sub/.go-version": "1.21.5", "sub/go.mod": "module m\ngo 1.20.1\n", "sub/pkg/.tool-versions": "golang 1.22.4\n"},
+		want:      "1.20.1",
+		wantPin:   "1.20.1",
+		wantFound: true,
+	},
+	{
+		name:      "missing pin falls back to global",
+		installed: []string{"1.22.4"},
+		global:    "1.22.4",
+		files:     map[string]string{"sub/.go-version": "1.19.2"},
+		want:      "1.22.4",
+		wantPin:   "1.19.2",
+	},
+}
+
+// setupShimCase builds tc's home and project under a temporary directory
+// and returns the shim's path and the directory to resolve from.
+func setupShimCase(t *testing.T, tc shimCase) (shim, dir string) {
+	t.Helper()
+	root := t.TempDir()
+	home := filepath.Join(root, "home")
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv(VersionEnv, tc.env)
+	t.Setenv(ShellVersionEnv, tc.shellEnv)
+	for _, version := range tc.installed {
+		binDir := filepath.Join(home, ".govm", "versions", "go"+version, "bin")
+		if err := os.MkdirAll(binDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		script := "#!/bin/sh\necho " + version + "\n"
+		if err := os.WriteFile(filepath.Join(binDir, "go"), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	shimDir := filepath.Join(home, ".govm", "shim")
+	if err := os.MkdirAll(shimDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if tc.pinFiles != nil {
+		content, _ := json.Marshal(map[string]any{"project": map[string]any{"pin_files": tc.pinFiles}})
+		if err := os.WriteFile(filepath.Join(home, ".govm", "config.json"), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	target := filepath.Join(home, ".govm", "versions", "go"+tc.global, "bin", "go")
+	if _, err := writeShim(shimDir, "go", target); err != nil {
+		t.Fatal(err)
+	}
+	project := filepath.Join(root, "project")
+	dir = filepath.Join(project, "sub", "pkg")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for rel, content := range tc.files {
+		if err := os.WriteFile(filepath.Join(project, filepath.FromSlash(rel)), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return filepath.Join(shimDir, "go") + DefaultShimFormat(runtime.GOOS).Extension(), dir
+}
+
+func TestResolveShim(t *testing.T) {
+	for _, tc := range shimCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, dir := setupShimCase(t, tc)
+			res, err := ResolveShim("go", dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := VersionFromPath(res.Target); got != tc.want {
+				t.Errorf("Target = %s, want go%s", res.Target, tc.want)
+			}
+			if res.Pin != tc.wantPin || res.PinInstalled != tc.wantFound {
+				t.Errorf("Pin = %q, PinInstalled = %v; want %q, %v", res.Pin, res.PinInstalled, tc.wantPin, tc.wantFound)
+			}
+		})
+	}
+}
+
+// TestBashShim runs the bash shim against the same cases as ResolveShim, so
+// the two cannot drift apart.
+func TestBashShim(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("bash shims are not used on Windows")
+	}
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not found")
+	}
+	for _, tc := range shimCases {
+		t.Run(tc.name, func(t *testing.T) {
+			shim, dir := setupShimCase(t, tc)
+			cmd := exec.Command(shim)
+			cmd.Dir = dir
+			var stderr strings.Builder
+			cmd.Stderr = &stderr
+			out, err := cmd.Output()
+			if err != nil {
+				t.Fatalf("%v: %s", err, stderr.String())
+			}
+			if got := strings.TrimSpace(string(out)); got != tc.want {
+				t.Errorf("shim ran %s, want %s", got, tc.want)
+			}
+			if warned := stderr.Len() > 0; warned != (tc.wantPin != "" && !tc.wantFound) {
+				t.Errorf("unexpected stderr %q", stderr.String())
+			}
+		})
+	}
+}
+
+// TestBashShimProjectCache checks that the bash shim trusts a fresh cache
+// entry and walks again once a path the entry depends on has changed.
+func TestBashShimProjectCache(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("bash shims are not used on Windows")
+	}
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not found")
+	}
+	shim, dir := setupShimCase(t, shimCase{
+		installed: []string{"1.21.5", "1.22.4"},
+		global:    "1.22.4",
+		files:     map[string]string{"sub/.go-version": "1.22.4"},
+	})
+	pin := filepath.Join(filepath.Dir(dir), ".go-version")
+	if version, _, err := DetectProjectVersion(dir); err != nil || version != "1.22.4" {
+		t.Fatalf("DetectProjectVersion() = %q, %v", version, err)
+	}
+	entry, err := projectCacheEntry(projectCacheKind(DefaultPinFiles), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Point the entry elsewhere without touching its mtime, so only a shim
+	// that read it would run 1.21.5
+	content, err := os.ReadFile(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := strings.Replace(string(content), "1.22.4", "1.21.5", 1)
+	if err := os.WriteFile(entry, []byte(forged), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(entry, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	run := func() string {
+		t.Helper()
+		cmd := exec.Command(shim)
+		cmd.Dir = dir
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	if got := run(); got != "1.21.5" {
+		t.Errorf("with a fresh entry, shim ran %s, want the cached 1.21.5", got)
+	}
+
+	later := info.ModTime().Add(time.Minute)
+	if err := os.Chtimes(pin, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if got := run(); got != "1.22.4" {
+		t.Errorf("after the pin changed, shim ran %s, want 1.22.4", got)
+	}
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// shimRecord is what govm last wrote to a shim, kept so that shims edited by
+// hand can be noticed and restored.
+type shimRecord struct {
+	Target string `json:"target"`
+	SHA256 string `json:"sha256"`
+}
+
+var shimRecordsMu sync.Mutex
+
+func shimRecordsPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".govm", "shim_hashes.json"), nil
+}
+
+func loadShimRecords() map[string]shimRecord {
+	records := map[string]shimRecord{}
+	path, err := shimRecordsPath()
+	if err != nil {
+		return records
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return records
+	}
+	json.Unmarshal(data, &records)
+	return records
+}
+
+// recordShims stores the hash of the generated shim for each tool, keyed by
+// tool name with the target it forwards to as the value.
+func recordShims(targets map[string]string) error {
+	shimRecordsMu.Lock()
+	defer shimRecordsMu.Unlock()
+	path, err := shimRecordsPath()
+	if err != nil {
+		return err
+	}
+	records := loadShimRecords()
+	format := DefaultShimFormat(runtime.GOOS)
+	pinFiles := PinFiles()
+	for tool, target := range targets {
+		records[tool] = shimRecord{Target: target, SHA256: hashBytes(ShimScript(target, pinFiles, format))}
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// shimModified reports whether the shim at path differs from what govm last
+// wrote for tool. Shims govm has no record of are never reported.
+func shimModified(records map[string]shimRecord, tool, path string) bool {
+	record, ok := records[tool]
+	if !ok {
+		return false
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return hashBytes(content) != record.SHA256
+}
+
+// RestoreShim rewrites tool's shim with exactly what govm last generated for
+// it, undoing any manual edits.
+func RestoreShim(tool string) error {
+	record, ok := loadShimRecords()[tool]
+	if !ok {
+		return fmt.Errorf("no record of the shim govm generated for %s", tool)
+	}
+	shimPath, err := ShimPath(tool)
+	if err != nil {
+		return err
+	}
+	return WriteShim(filepath.Dir(shimPath), tool, record.Target)
+}
@@ -0,0 +1,137 @@
+#!/usr/bin/env bash
+target="/home/gopher/.govm/versions/go1.22.4/bin/go"
+versions="$HOME/.govm/versions"
+# read_pin sets pin to the version the pin file $1 names, if any
+read_pin() {
+	local line key value rest quote table=""
+	pin=""
+	case "${1##*/}" in
+	.go-version)
+		read -r pin < "$1"
+		pin="${pin%$'\r'}"
+		;;
+	.tool-versions)
+		while read -r key value _ || [ -n "$key" ]; do
+			value="${value%%#*}"
+			value="${value%$'\r'}"
+			[ -n "$value" ] || continue
+			case "$key" in
+			golang | go)
+				[ "$value" = system ] || pin="$value"
+				break
+				;;
+			esac
+		done < "$1"
+		;;
+	mise.toml | .mise.toml)
+		while IFS= read -r line || [ -n "$line" ]; do
+			line="${line%%#*}"
+			line="${line#"${line%%[![:space:]]*}"}"
+			case "$line" in
+			"["*)
+				table="${line//[][[:space:]]/}"
+				continue
+				;;
+			*=*) ;;
+			*) continue ;;
+			esac
+			key="${line%%=*}"
+			key="${key//[[:space:]\"\']/}"
+			[ "$table" = tools ] || continue
+			case "$key" in go | golang) ;; *) continue ;; esac
+			value="${line#*=}"
+			value="${value#"${value%%[![:space:]]*}"}"
+			case "$value" in "{"*version*)
+				value="${value#*version}"
+				value="${value#*=}"
+				;;
+			esac
+			rest="${value#*[\"\']}"
+			if [ "$rest" != "$value" ]; then
+				quote="${value:$((${#value} - ${#rest} - 1)):1}"
+				case "$rest" in *"$quote"*) value="${rest%%"$quote"*}" ;; *) value="" ;; esac
+			else
+				value=""
+			fi
+			value="${value#prefix:}"
+			case "$value" in latest | system | *:*) ;; *) pin="$value" ;; esac
+			break
+		done < "$1"
+		;;
+	go.mod)
+		local directive=""
+		while read -r key value _ || [ -n "$key" ]; do
+			value="${value%$'\r'}"
+			case "$key" in
+			toolchain) [ "$value" = default ] || [ -z "$value" ] || { directive="$value"; break; } ;;
+			go) [ -z "$value" ] || directive="$value" ;;
+			esac
+		done < "$1"
+		pin="$directive"
+		;;
+	esac
+	pin="${pin#go}"
+}
+pin="${GOVM_GO_VERSION#go}"
+origin="GOVM_GO_VERSION"
+if [ -z "$pin" ]; then
+	pin="${GOVM_SHELL_VERSION#go}"
+	origin="GOVM_SHELL_VERSION"
+fi
+cached=""
+if [ -z "$pin" ]; then
+	entry="$HOME/.govm/cache/projects/799d32e7cfbb$PWD/.govm-pin"
+	if [ -f "$entry" ]; then
+		{
+			IFS= read -r cached_pin
+			IFS= read -r cached_origin
+			while IFS= read -r path; do
+				if [ -e "$path" ] && [ "$entry" -nt "$path" ]; then
+					cached=1
+				else
+					cached=""
+					break
+				fi
+			done
+		} < "$entry"
+	fi
+	if [ -n "$cached" ] && [ -n "$cached_pin" ]; then
+		pin="${cached_pin#go}"
+		origin="$cached_origin"
+	fi
+fi
+if [ -z "$pin" ] && [ -z "$cached" ]; then
+	dir="$PWD"
+	while :; do
+		for name in .go-version .tool-versions mise.toml .mise.toml go.mod; do
+			if [ -f "$dir/$name" ]; then
+				read_pin "$dir/$name"
+				if [ -n "$pin" ]; then
+					origin="$dir/$name"
+					break 2
+				fi
+			fi
+		done
+		[ -n "$dir" ] || break
+		dir="${dir%/*}"
+	done
+fi
+if [ -n "$pin" ]; then
+	if [ -x "$versions/go$pin/bin/go" ]; then
+		target="$versions/go$pin/bin/go"
+	else
+		best=-1
+		for candidate in "$versions/go$pin".*; do
+			patch="${candidate##*/go$pin.}"
+			case "$patch" in "" | *[!0-9]*) continue ;; esac
+			if [ "$patch" -gt "$best" ] && [ -x "$candidate/bin/go" ]; then
+				best="$patch"
+				target="$candidate/bin/go"
+			fi
+		done
+		if [ "$best" -lt 0 ]; then
+			echo "govm: $origin pins Go $pin, which is not installed; using $target" >&2
+		fi
+	fi
+fi
+exec "$target" "$@"
@@ -0,0 +1,105 @@
+@echo off
+setlocal
+set target="C:\Users\gopher\.govm\versions\go1.22.4\bin\go"
+set "versions=%USERPROFILE%\.govm\versions"
+set "pin=%GOVM_GO_VERSION%"
+set "origin=GOVM_GO_VERSION"
+if defined pin goto resolve
+set "pin=%GOVM_SHELL_VERSION%"
+set "origin=GOVM_SHELL_VERSION"
+if defined pin goto resolve
+set "dir=%CD%"
+:walk
+for %%N in (.go-version .tool-versions mise.toml .mise.toml go.mod) do if not defined pin if exist "%dir%\%%N" call :readpin "%dir%\%%N"
+if defined pin goto resolve
+for %%D in ("%dir%\..") do set "parent=%%~fD"
+if "%parent%"=="%dir%" goto run
+set "dir=%parent%"
+goto walk
+:resolve
+if not defined pin goto run
+if "%pin:~0,2%"=="go" set "pin=%pin:~2%"
+if exist "%versions%\go%pin%\bin\C:\Users\gopher\.govm\versions\go1.22.4\bin\go.exe" (
+	set target="%versions%\go%pin%\bin\C:\Users\gopher\.govm\versions\go1.22.4\bin\go"
+	goto run
+)
+set best=-1
+for /d %%V in ("%versions%\go%pin%.*") do call :consider "%%V"
+if %best% LSS 0 echo govm: %origin% pins Go %pin%, which is not installed; using %target% 1>&2
+:run
+%target% %*
+exit /b %errorlevel%
+:consider
+set "patch=%~x1"
+set "patch=%patch:~1%"
+if not exist "%~1\bin\C:\Users\gopher\.govm\versions\go1.22.4\bin\go.exe" exit /b
+if %patch% GTR %best% (
+	set best=%patch%
+	set target="%~1\bin\C:\Users\gopher\.govm\versions\go1.22.4\bin\go"
+)
+exit /b
+:readpin
+set "pin="
+set "found="
+set "table="
+set "name=%~nx1"
+if "%name%"==".go-version" goto readgoversion
+if "%name%"==".tool-versions" goto readtoolversions
+if "%name%"=="mise.toml" goto readmise
+if "%name%"==".mise.toml" goto readmise
+if "%name%"=="go.mod" goto readgomod
+exit /b
+:readgoversion
+set /p pin=<"%~1"
+goto pinread
+:readtoolversions
+for /f "usebackq eol=# tokens=1,2" %%A in ("%~1") do (
+	if not defined found if "%%A"=="golang" set "found=%%B"
+	if not defined found if "%%A"=="go" set "found=%%B"
+)
+if not "%found%"=="system" set "pin=%found%"
+goto pinread
+:readmise
+for /f "usebackq eol=# tokens=1,* delims==" %%A in ("%~1") do if not defined found (
+	set "key=.%%A"
+	set "value=%%B"
+	call :miseline
+)
+if not defined pin goto pinread
+set "pin=%pin:prefix:=%"
+if "%pin%"=="latest" set "pin="
+if "%pin%"=="system" set "pin="
+if not defined pin goto pinread
+if not "%pin::=%"=="%pin%" set "pin="
+goto pinread
+:miseline
+set "key=%key: =%"
+set "key=%key:	=%"
+set "key=%key:"=%"
+set "key=%key:'=%"
+if defined value goto misekey
+set "table=%key:[=%"
+set "table=%table:]=%"
+exit /b
+:misekey
+if not "%table%"==".tools" exit /b
+if not "%key%"==".go" if not "%key%"==".golang" exit /b
+set "found=1"
+setlocal EnableDelayedExpansion
+set "value=!value:'="!"
+set "quoted="
+for /f tokens^=2^ delims^=^" %%Q in (".!value!") do set "quoted=%%Q"
+endlocal & set "pin=%quoted%"
+exit /b
+:readgomod
+for /f "usebackq tokens=1,2" %%A in ("%~1") do (
+	if not defined found if "%%A"=="toolchain" if not "%%B"=="default" set "found=%%B"
+	if "%%A"=="go" set "pin=%%B"
+)
+if defined found set "pin=%found%"
+goto pinread
+:pinread
+if not defined pin exit /b
+if "%pin:~0,2%"=="go" set "pin=%pin:~2%"
+if defined pin set "origin=%~1"
+exit /b
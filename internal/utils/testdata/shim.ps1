@@ -0,0 +1,103 @@
+$target = "C:\Users\gopher\.govm\versions\go1.22.4\bin\go"
+$versions = Join-Path $HOME ".govm\versions"
+# Read-Pin returns the version the pin file $path names, if any
+function Read-Pin($path) {
+    $name = Split-Path -Leaf $path
+    $pin = ""
+    if ($name -eq ".go-version") {
+        $pin = ([string](Get-Content $path -TotalCount 1)).Trim()
+    } elseif ($name -eq ".tool-versions") {
+        foreach ($line in Get-Content $path) {
+            $fields = -split ($line -replace '#.*', '')
+            if ($fields.Count -ge 2 -and ($fields[0] -eq "golang" -or $fields[0] -eq "go")) {
+                if ($fields[1] -ne "system") { $pin = $fields[1] }
+                break
+            }
+        }
+    } elseif ($name -eq "mise.toml" -or $name -eq ".mise.toml") {
+        $table = ""
+        foreach ($line in Get-Content $path) {
+            $line = ($line -replace '#.*', '').Trim()
+            if ($line.StartsWith("[")) {
+                $table = $line.Trim("[]").Trim()
+                continue
+            }
+            if ($table -ne "tools" -or $line -cnotmatch '^["'']?(go|golang)["'']?\s*=(.*)$') { continue }
+            $value = $Matches[2].Trim()
+            if ($value.StartsWith("{") -and $value -match 'version\s*=(.*)$') { $value = $Matches[1] }
+            if ($value -match '(["''])(.*?)\1') { $pin = $Matches[2] -replace '^prefix:', '' }
+            if ($pin -eq "latest" -or $pin -eq "system" -or $pin.Contains(":")) { $pin = "" }
+            break
+        }
+    } elseif ($name -eq "go.mod") {
+        foreach ($line in Get-Content $path) {
+            $fields = -split ($line -replace '//.*', '')
+            if ($fields.Count -lt 2) { continue }
+            if ($fields[0] -eq "toolchain" -and $fields[1] -ne "default") {
+                $pin = $fields[1]
+                break
+            }
+            if ($fields[0] -eq "go") { $pin = $fields[1] }
+        }
+    }
+    return $pin -replace '^go', ''
+}
+$pin = $env:GOVM_GO_VERSION -replace '^go', ''
+$origin = "GOVM_GO_VERSION"
+if (-not $pin) {
+    $pin = $env:GOVM_SHELL_VERSION -replace '^go', ''
+    $origin = "GOVM_SHELL_VERSION"
+}
+if (-not $pin) {
+    $dir = (Get-Location).ProviderPath
+    $cached = $false
+    if ($dir -match '^([A-Za-z]):(.*)$') {
+        $entry = Join-Path $HOME ".govm\cache\projects\799d32e7cfbb\$($Matches[1])$($Matches[2])\.govm-pin"
+        if (Test-Path -LiteralPath $entry -PathType Leaf) {
+            $lines = @(Get-Content -LiteralPath $entry)
+            $written = (Get-Item -LiteralPath $entry).LastWriteTimeUtc
+            $cached = $lines.Count -ge 3
+            foreach ($path in ($lines | Select-Object -Skip 2)) {
+                if (-not (Test-Path -LiteralPath $path) -or (Get-Item -LiteralPath $path).LastWriteTimeUtc -ge $written) {
+                    $cached = $false
+                    break
+                }
+            }
+            if ($cached -and $lines[0]) {
+                $pin = $lines[0] -replace '^go', ''
+                $origin = $lines[1]
+            }
+        }
+    }
+    while (-not $cached -and $dir -and -not $pin) {
+        foreach ($name in @(".go-version", ".tool-versions", "mise.toml", ".mise.toml", "go.mod")) {
+            $pinFile = Join-Path $dir $name
+            if (Test-Path -PathType Leaf $pinFile) {
+                $pin = Read-Pin $pinFile
+                if ($pin) {
+                    $origin = $pinFile
+                    break
+                }
+            }
+        }
+        $dir = Split-Path -Parent $dir
+    }
+}
+if ($pin) {
+    $exact = Join-Path $versions "go$pin\bin\C:\Users\gopher\.govm\versions\go1.22.4\bin\go"
+    if (Test-Path "$exact.exe") {
+        $target = $exact
+    } else {
+        $best = Get-ChildItem $versions -Directory -Filter "go$pin.*" -ErrorAction SilentlyContinue |
+            Where-Object { $_.Name.Substring($pin.Length + 3) -match '^\d+$' -and (Test-Path (Join-Path $_.FullName "bin\C:\Users\gopher\.govm\versions\go1.22.4\bin\go.exe")) } |
+            Sort-Object { [int]$_.Name.Substring($pin.Length + 3) } |
+            Select-Object -Last 1
+        if ($best) {
+            $target = Join-Path $best.FullName "bin\C:\Users\gopher\.govm\versions\go1.22.4\bin\go"
+        } else {
+            [Console]::Error.WriteLine("govm: $origin pins Go $pin, which is not installed; using $target")
+        }
+    }
+}
+& $target @args
+exit $LASTEXITCODE
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/melkeydev/govm/internal/clock"
+)
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		ago  time.Duration
+		want string
+	}{
+		{-time.Minute, "in the future"},
+		{0, "just now"},
+		{59 * time.Second, "just now"},
+		{time.Minute, "1 minute ago"},
+		{59 * time.Minute, "59 minutes ago"},
+		{time.Hour, "1 hour ago"},
+		{23 * time.Hour, "23 hours ago"},
+		{24 * time.Hour, "1 day ago"},
+		{29 * 24 * time.Hour, "29 days ago"},
+		{30 * 24 * time.Hour, "1 month ago"},
+		{364 * 24 * time.Hour, "12 months ago"},
+		{365 * 24 * time.Hour, "1 year ago"},
+		{3 * 365 * 24 * time.Hour, "3 years ago"},
+	}
+	for _, tt := range tests {
+		if got := RelativeTime(now.Add(-tt.ago), now); got != tt.want {
+			t.Errorf("RelativeTime(now-%v) = %q, want %q", tt.ago, got, tt.want)
+		}
+	}
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock.Default = clock.NewFake(now)
+	t.Cleanup(func() { clock.Default = clock.Real{} })
+	stamp := now.Add(-3 * time.Hour)
+	if got, want := FormatTimestamp(stamp, true), "2024-06-01T09:00:00Z"; got != want {
+		t.Errorf("FormatTimestamp(iso) = %q, want %q", got, want)
+	}
+	want := stamp.Local().Format("2006-01-02 15:04") + " (3 hours ago)"
+	if got := FormatTimestamp(stamp, false); got != want {
+		t.Errorf("FormatTimestamp = %q, want %q", got, want)
+	}
+}
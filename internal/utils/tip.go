@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/melkeydev/govm/internal/term"
+)
+
+// TipVersion is the pseudo-version of a toolchain built from the master
+// branch of the Go repository, the same snapshot gotip installs. It is never
+// offered unless asked for by name.
+const TipVersion = "tip"
+
+const tipRepository = "https://go.googlesource.com/go"
+
+// TipGoVersion returns the GoVersion describing an unstable tip build.
+func TipGoVersion() GoVersion {
+	return GoVersion{
+		Version: TipVersion,
+		URL:     tipRepository,
+		Stable:  false,
+	}
+}
+
+// BuildTip updates a checkout of the master branch of the Go repository and
+// builds it with make.bash, bootstrapping from the newest final release
+// installed with govm. The checkout is kept under ~/.govm/src so rebuilding only fetches new
+// commits. The result replaces any earlier tip build in the versions
+// directory.
+func BuildTip() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", fmt.Errorf("building tip requires git on PATH")
+	}
+	goVersionsDir := filepath.Join(homeDir, ".govm", "versions")
+	bootstrap, err := bootstrapToolchain(goVersionsDir)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(goVersionsDir, 0755); err != nil {
+		return "", err
+	}
+	buildDir, err := os.MkdirTemp(goVersionsDir, ".extract-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create build directory: %v", err)
+	}
+	defer os.RemoveAll(buildDir)
+	checkout, err := updateTipCheckout(filepath.Join(homeDir, ".govm", "src", "go"))
+	if err != nil {
+		return "", err
+	}
+	// Build from a local clone so a failed build never leaves the cached
+	// checkout half-built
+	sourceDir := filepath.Join(buildDir, "go")
+	clone := exec.Command("git", "clone", "--quiet", checkout, sourceDir)
+	term.Debugf("running %s", strings.Join(clone.Args, " "))
+	if output, err := clone.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to copy the tip checkout: %v\nOutput: %s", err, string(output))
+	}
+	script := "./make.bash"
+	if runtime.GOOS == "windows" {
+		script = "make.bat"
+	}
+	build := exec.Command(script)
+	build.Dir = filepath.Join(sourceDir, "src")
+	build.Env = append(os.Environ(), "GOROOT_BOOTSTRAP="+bootstrap)
+	term.Debugf("running %s in %s with GOROOT_BOOTSTRAP=%s", script, build.Dir, bootstrap)
+	if output, err := build.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to build tip: %v\nOutput: %s", err, string(output))
+	}
+	if !IsCompleteInstall(sourceDir) {
+		return "", fmt.Errorf("tip build finished without producing bin/%s", goBinary)
+	}
+	versionDir := filepath.Join(goVersionsDir, "go"+TipVersion)
+	if _, err := os.Stat(versionDir); err == nil {
+		if err := CheckNotInside(versionDir); err != nil {
+			return "", fmt.Errorf("cannot replace Go %s: %v", TipVersion, err)
+		}
+		if err := os.RemoveAll(versionDir); err != nil {
+			return "", fmt.Errorf("failed to remove existing installation: %v", err)
+		}
+	}
+	if err := os.Rename(sourceDir, versionDir); err != nil {
+		return "", fmt.Errorf("failed to rename directory: %v", err)
+	}
+	markInstalled(versionDir)
+	return versionDir, nil
+}
+
+// bootstrapToolchain returns the directory of the newest final release
+// installed in goVersionsDir, the toolchain tip is built with. Whatever go is
+// on PATH is not used: it may be a shim pointing at tip itself, or too old.
+func bootstrapToolchain(goVersionsDir string) (string, error) {
+	newest, newestDir := "", ""
+	entries, _ := os.ReadDir(goVersionsDir)
+	for _, entry := range entries {
+		version := strings.TrimPrefix(entry.Name(), "go")
+		versionDir := filepath.Join(goVersionsDir, entry.Name())
+		if !entry.IsDir() || !IsReleaseVersion(version) || IsPreRelease(version) || !IsCompleteInstall(versionDir) {
+			continue
+		}
+		if newest == "" || CompareVersions(version, newest) > 0 {
+			newest, newestDir = version, versionDir
+		}
+	}
+	if newest == "" {
+		return "", fmt.Errorf("building tip requires a final Go release installed with govm to bootstrap from; install one first, e.g. govm install latest")
+	}
+	term.Debugf("bootstrapping tip from Go %s", newest)
+	return newestDir, nil
+}
+
+// updateTipCheckout clones the Go repository into dir, or fast-forwards an
+// existing clone to the latest master commit.
+func updateTipCheckout(dir string) (string, error) {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+			return "", err
+		}
+		os.RemoveAll(dir)
+		clone := exec.Command("git", "clone", "--depth", "1", tipRepository, dir)
+		term.Debugf("running %s", strings.Join(clone.Args, " "))
+		if output, err := clone.CombinedOutput(); err != nil {
+			os.RemoveAll(dir)
+			return "", fmt.Errorf("failed to clone %s: %v\nOutput: %s", tipRepository, err, string(output))
+		}
+		return dir, nil
+	}
+	for _, args := range [][]string{
+		{"fetch", "--depth", "1", "origin", "master"},
+		{"reset", "--hard", "FETCH_HEAD"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		term.Debugf("running git %s in %s", strings.Join(args, " "), dir)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return "", fmt.Errorf("failed to update the tip checkout: git %s: %v\nOutput: %s", args[0], err, string(output))
+		}
+	}
+	return dir, nil
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBootstrapToolchain(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := bootstrapToolchain(dir); err == nil || !strings.Contains(err.Error(), "govm install") {
+		t.Errorf("with nothing installed, err = %v; want advice to install a release", err)
+	}
+	install := func(name string, complete bool) {
+		t.Helper()
+		binDir := filepath.Join(dir, name, "bin")
+		if err := os.MkdirAll(binDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if complete {
+			if err := os.WriteFile(filepath.Join(binDir, goBinary), nil, 0755); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	install("go"+TipVersion, true)
+	install("go1.25rc1", true)
+	if _, err := bootstrapToolchain(dir); err == nil {
+		t.Error("tip or a release candidate was picked to bootstrap from")
+	}
+	install("go1.9.7", true)
+	install("go1.22.4", true)
+	install("go1.23.0", false)
+	got, err := bootstrapToolchain(dir)
+	if want := filepath.Join(dir, "go1.22.4"); err != nil || got != want {
+		t.Errorf("bootstrapToolchain = %q, %v; want %q", got, err, want)
+	}
+}
@@ -0,0 +1,343 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/melkeydev/govm/internal/events"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// fakeNetwork sends every request govm makes to handler through Transport,
+// whatever host it names; r.Host tells the handler which one was meant. It
+// returns a function listing the requests seen so far as "host path".
+func fakeNetwork(t *testing.T, handler http.HandlerFunc) func() []string {
+	t.Helper()
+	var mu sync.Mutex
+	var seen []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.Host+" "+r.URL.Path)
+		mu.Unlock()
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	server, _ := url.Parse(srv.URL)
+	base := srv.Client().Transport
+	Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		out := req.Clone(req.Context())
+		out.Host = req.URL.Host
+		out.URL.Scheme, out.URL.Host = server.Scheme, server.Host
+		resp, err := base.RoundTrip(out)
+		if resp != nil {
+			resp.Request = req
+		}
+		return resp, err
+	})
+	t.Cleanup(func() { Transport = nil })
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), seen...)
+	}
+}
+
+// testHome points HOME at a fresh directory with the given download
+// settings and resets the offline state afterwards.
+func testHome(t *testing.T, download map[string]any) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if download != nil {
+		content, err := json.Marshal(map[string]any{"download": download})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Join(home, ".govm"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(home, ".govm", "config.json"), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() {
+		SetOffline(false)
+		retryOnline()
+		setStaleIndexTime(time.Time{})
+		goDevSumsOnce, goDevSums, goDevSumsErr = sync.Once{}, nil, nil
+	})
+	return home
+}
+
+// testIndex is a release index listing Go 1.22.4 and 1.21.5 for the
+// current platform.
+func testIndex() []byte {
+	var releases []map[string]any
+	for _, version := range []string{"1.22.4", "1.21.5"} {
+		releases = append(releases, map[string]any{
+			"version": "go" + version,
+			"stable":  true,
+			"files": []map[string]any{{
+				"filename": fmt.Sprintf("go%s.%s-%s.tar.gz", version, runtime.GOOS, runtime.GOARCH),
+				"os":       runtime.GOOS,
+				"arch":     runtime.GOARCH,
+				"kind":     "archive",
+				"sha256":   strings.Repeat("0", 64),
+			}},
+		})
+	}
+	body, _ := json.Marshal(releases)
+	return body
+}
+
+func TestIndexCacheETag(t *testing.T) {
+	testHome(t, map[string]any{"mirrors": []string{"https://mirror.test/dl/"}})
+	fake := useFakeClock(t)
+	index := testIndex()
+	var conditional int
+	requests := fakeNetwork(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditional++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write(index)
+	})
+
+	fetch := func() events.RemoteVersionsMsg {
+		t.Helper()
+		versions, ok := FetchRemoteVersions().(events.RemoteVersionsMsg)
+		if !ok || len(versions) != 2 {
+			t.Fatalf("FetchRemoteVersions() = %v", versions)
+		}
+		return versions
+	}
+	fetch()
+	if got := requests(); len(got) != 1 || got[0] != "mirror.test /dl/" {
+		t.Fatalf("first fetch made requests %v", got)
+	}
+	// Within download.index_ttl the cache answers alone
+	fetch()
+	if got := len(requests()); got != 1 {
+		t.Fatalf("fresh cache still made %d requests", got)
+	}
+	// Once it expires the mirror is asked conditionally and the 304 reuses it
+	fake.Advance(defaultIndexTTL + time.Minute)
+	fetch()
+	if got := len(requests()); got != 2 || conditional != 1 {
+		t.Fatalf("expired cache made %d requests, %d conditional", got, conditional)
+	}
+	cache, ok := readIndexCache()
+	if !ok || !cache.FetchedAt.Equal(fake.Now().UTC()) {
+		t.Errorf("304 did not renew the cache: %+v", cache.FetchedAt)
+	}
+	// A refresh asks again even while the cache is fresh
+	if _, ok := RefreshRemoteVersions().(events.RemoteVersionsMsg); !ok || conditional != 2 {
+		t.Errorf("refresh made %d conditional requests, want 2", conditional)
+	}
+}
+
+func TestIndexMirrorFallback(t *testing.T) {
+	testHome(t, map[string]any{"mirrors": []string{"https://down.test/", "https://up.test/"}})
+	index := testIndex()
+	requests := fakeNetwork(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Host == "down.test" {
+			http.Error(w, "maintenance", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(index)
+	})
+	if versions, ok := FetchRemoteVersions().(events.RemoteVersionsMsg); !ok || len(versions) != 2 {
+		t.Fatalf("FetchRemoteVersions() = %v", versions)
+	}
+	if got := requests(); len(got) != 2 || got[0] != "down.test /" || got[1] != "up.test /" {
+		t.Errorf("requests = %v", got)
+	}
+}
+
+// testArchive returns a fake release archive and the GoVersion listing it
+// with its checksum.
+func testArchive() ([]byte, GoVersion) {
+	content := []byte(strings.Repeat("not really a Go release archive\n", 512))
+	sum := sha256.Sum256(content)
+	filename := fmt.Sprintf("go1.22.4.%s-%s.tar.gz", runtime.GOOS, runtime.GOARCH)
+	return content, GoVersion{
+		Version:  "1.22.4",
+		Filename: filename,
+		URL:      DefaultDownloadMirror + filename,
+		SHA256:   hex.EncodeToString(sum[:]),
+	}
+}
+
+func serveArchive(content []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "archive.tar.gz", time.Time{}, strings.NewReader(string(content)))
+	}
+}
+
+func TestDownloadResumes(t *testing.T) {
+	home := testHome(t, nil)
+	content, version := testArchive()
+	var ranges []string
+	fakeNetwork(t, func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		serveArchive(content)(w, r)
+	})
+	downloadPath := filepath.Join(home, ".govm", "downloads", version.Filename)
+	if err := os.MkdirAll(filepath.Dir(downloadPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(downloadPath+".part", content[:1000], 0644); err != nil {
+		t.Fatal(err)
+	}
+	path, err := DownloadArchive(version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ranges) != 1 || ranges[0] != "bytes=1000-" {
+		t.Errorf("Range headers = %q, want bytes=1000-", ranges)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil || string(got) != string(content) {
+		t.Fatalf("resumed archive differs (%d bytes, %v)", len(got), err)
+	}
+	if _, err := os.Stat(downloadPath + ".part"); !os.IsNotExist(err) {
+		t.Errorf(".part left behind: %v", err)
+	}
+	// The complete archive is reused without another request
+	if _, err := DownloadArchive(version); err != nil || len(ranges) != 1 {
+		t.Errorf("cached archive was downloaded again (%d requests, %v)", len(ranges), err)
+	}
+}
+
+func TestDownloadChecksumMismatch(t *testing.T) {
+	home := testHome(t, nil)
+	content, version := testArchive()
+	fakeNetwork(t, serveArchive(append([]byte("tampered "), content...)))
+	_, err := DownloadArchive(version)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("DownloadArchive() error = %v, want a checksum mismatch", err)
+	}
+	downloadPath := filepath.Join(home, ".govm", "downloads", version.Filename)
+	for _, path := range []string{downloadPath, downloadPath + ".part"} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s kept after the mismatch", filepath.Base(path))
+		}
+	}
+}
+
+func TestDownloadMirrorFallback(t *testing.T) {
+	testHome(t, map[string]any{"mirrors": []string{"https://down.test/dl/", "https://missing.test/dl/", "https://up.test/dl/"}})
+	content, version := testArchive()
+	requests := fakeNetwork(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Host {
+		case "down.test":
+			http.Error(w, "maintenance", http.StatusServiceUnavailable)
+		case "missing.test":
+			http.NotFound(w, r)
+		case "go.dev":
+			w.Write(goDevIndex(version))
+		default:
+			serveArchive(content)(w, r)
+		}
+	})
+	if _, err := DownloadArchive(version); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"down.test /dl/" + version.Filename, "missing.test /dl/" + version.Filename, "up.test /dl/" + version.Filename, "go.dev /dl/"}
+	if got := requests(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("requests = %v, want %v", got, want)
+	}
+}
+
+// goDevIndex is go.dev's release index listing version's archive.
+func goDevIndex(version GoVersion) []byte {
+	body, _ := json.Marshal([]map[string]any{{
+		"version": "go" + version.Version,
+		"files":   []map[string]any{{"filename": version.Filename, "sha256": version.SHA256}},
+	}})
+	return body
+}
+
+// recordWeakChecksums collects the warnings OnWeakChecksum is given.
+func recordWeakChecksums(t *testing.T) *[]string {
+	t.Helper()
+	var warnings []string
+	OnWeakChecksum = func(filename, reason string) { warnings = append(warnings, reason) }
+	t.Cleanup(func() { OnWeakChecksum = nil })
+	return &warnings
+}
+
+func TestChecksumCheckedWithGoDev(t *testing.T) {
+	testHome(t, map[string]any{"mirrors": []string{"https://mirror.test/dl/"}})
+	content, published := testArchive()
+	// The mirror serves a tampered archive and lists its checksum
+	tampered := append([]byte("tampered "), content...)
+	sum := sha256.Sum256(tampered)
+	version := published
+	version.SHA256 = hex.EncodeToString(sum[:])
+	fakeNetwork(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Host == "go.dev" {
+			w.Write(goDevIndex(published))
+			return
+		}
+		serveArchive(tampered)(w, r)
+	})
+	_, err := DownloadArchive(version)
+	if err == nil || !strings.Contains(err.Error(), "go.dev lists "+published.SHA256) {
+		t.Fatalf("DownloadArchive() error = %v, want a mismatch with go.dev's checksum", err)
+	}
+}
+
+func TestChecksumGoDevUnreachable(t *testing.T) {
+	testHome(t, map[string]any{"mirrors": []string{"https://mirror.test/dl/"}})
+	warnings := recordWeakChecksums(t)
+	content, version := testArchive()
+	fakeNetwork(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Host == "go.dev" {
+			http.Error(w, "blocked", http.StatusForbidden)
+			return
+		}
+		serveArchive(content)(w, r)
+	})
+	if _, err := DownloadArchive(version); err != nil {
+		t.Fatal(err)
+	}
+	if len(*warnings) != 1 || !strings.Contains((*warnings)[0], "checked only against") {
+		t.Errorf("warnings = %q, want one about a same-origin checksum", *warnings)
+	}
+}
+
+func TestChecksumUnlisted(t *testing.T) {
+	testHome(t, nil)
+	warnings := recordWeakChecksums(t)
+	content, version := testArchive()
+	version.SHA256 = ""
+	requests := fakeNetwork(t, serveArchive(content))
+	if _, err := DownloadArchive(version); err != nil {
+		t.Fatal(err)
+	}
+	if len(*warnings) != 1 || !strings.Contains((*warnings)[0], "unverified") {
+		t.Errorf("warnings = %q, want one about an unverified archive", *warnings)
+	}
+	// The index came from go.dev, so it is not asked again
+	if got := requests(); len(got) != 1 {
+		t.Errorf("requests = %v", got)
+	}
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,30 +11,20 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"sort"
-	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/melkeydev/govm/internal/clock"
+	"github.com/melkeydev/govm/internal/events"
+	"github.com/melkeydev/govm/internal/term"
 )
 
-type GoVersion struct {
-	Version   string
-	Filename  string
-	URL       string
-	Installed bool
-	Active    bool
-	Path      string
-	Stable    bool
-}
-type SwitchCompletedMsg struct {
-	Version    string
-	ShimInPath bool
-}
-type DownloadCompleteMsg struct {
-	Version string
-	Path    string
-}
+// GoVersion is a Go release; it is defined with the messages that carry it.
+type GoVersion = events.GoVersion
 
 func SetupShimDirectory() error {
 	homeDir, err := os.UserHomeDir()
@@ -71,20 +63,63 @@ func GetShimPathInstructions() string {
 		return "Add to your shell config: export PATH=\"$HOME/.govm/shim:$PATH\""
 	}
 }
+
+// FetchGoVersions fetches the release list from go.dev and annotates it with
+// the installed and active state. The network request and the disk scans run
+// concurrently.
 func FetchGoVersions() tea.Msg {
-	// I randomly put 10 second here
-	client := &http.Client{
-		Timeout: 10 * 1000000000,
+	var remote, installed, active tea.Msg
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() { defer wg.Done(); remote = FetchRemoteVersions() }()
+	go func() { defer wg.Done(); installed = ScanInstalledVersions() }()
+	go func() { defer wg.Done(); active = DetectActiveVersion() }()
+	wg.Wait()
+	for _, msg := range []tea.Msg{remote, installed} {
+		if err, ok := msg.(events.ErrMsg); ok {
+			return err
+		}
 	}
-	resp, err := client.Get("https://go.dev/dl/?mode=json&include=all")
-	if err != nil {
-		return ErrMsg(fmt.Errorf("failed to connect to go.dev: %v", err))
+	return events.VersionsMsg(MergeVersions(remote.(events.RemoteVersionsMsg), installed.(events.InstalledVersionsMsg), string(active.(events.ActiveVersionMsg))))
+}
+
+// FetchRemoteVersions fetches the releases available on go.dev for the
+// current platform, newest first. Installed and active state is left unset.
+// The index comes from the first download mirror that answers, or from
+// ~/.govm/cache while it is younger than download.index_ttl.
+func FetchRemoteVersions() tea.Msg {
+	return fetchRemoteVersions(false)
+}
+
+// RefreshRemoteVersions is FetchRemoteVersions for an explicit refresh: it
+// asks the mirror even when the cached index is fresh, though an unchanged
+// index is still not downloaded again.
+func RefreshRemoteVersions() tea.Msg {
+	return fetchRemoteVersions(true)
+}
+
+// CachedRemoteVersions is FetchRemoteVersions from ~/.govm/cache alone,
+// whatever its age, so the TUI can show it before the network answers. It
+// returns an empty events.RemoteVersionsMsg when nothing is cached.
+func CachedRemoteVersions() tea.Msg {
+	cache, cached := readIndexCache()
+	if !cached {
+		return events.RemoteVersionsMsg(nil)
 	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
+	return parseIndex(cache.Body)
+}
+
+func fetchRemoteVersions(refresh bool) tea.Msg {
+	body, err := loadIndex(refresh)
 	if err != nil {
-		return ErrMsg(err)
+		return events.NewErrMsg(err)
 	}
+	return parseIndex(body)
+}
+
+// parseIndex turns the JSON release index into the releases for the
+// current platform, newest first.
+func parseIndex(body []byte) tea.Msg {
 	var releases []struct {
 		Version string `json:"version"`
 		Stable  bool   `json:"stable"`
@@ -92,262 +127,560 @@ func FetchGoVersions() tea.Msg {
 			Filename string `json:"filename"`
 			OS       string `json:"os"`
 			Arch     string `json:"arch"`
+			Kind     string `json:"kind"`
+			SHA256   string `json:"sha256"`
 			Size     int    `json:"size"`
 		} `json:"files"`
 	}
-	err = json.Unmarshal(body, &releases)
-	if err != nil {
-		return ErrMsg(fmt.Errorf("failed to parse API response: %v", err))
+	if err := json.Unmarshal(body, &releases); err != nil {
+		return events.ErrMsgf("failed to parse API response: %v", err)
 	}
 	currentOS := runtime.GOOS
 	arch := runtime.GOARCH
-	// Get home directory
+	var versions []GoVersion
+	for _, release := range releases {
+		version := strings.TrimPrefix(release.Version, "go")
+		var match *GoVersion
+		for _, file := range release.Files {
+			if file.OS != currentOS || file.Arch != arch {
+				continue
+			}
+			// Some old releases only publish an installer (.pkg/.msi) for a
+			// platform; keep looking for an archive but remember the release
+			if match == nil || file.Kind == "archive" {
+				match = &GoVersion{
+					Version:     version,
+					Filename:    file.Filename,
+					URL:         "https://go.dev/dl/" + file.Filename,
+					Stable:      release.Stable,
+					SHA256:      file.SHA256,
+					Unsupported: UnsupportedReason(version),
+				}
+			}
+			if file.Kind == "archive" {
+				break
+			}
+		}
+		if match == nil {
+			continue
+		}
+		if match.Unsupported == "" && !IsArchiveReference(match.Filename) {
+			match.Unsupported = "only an installer package is published for " + currentOS + "/" + arch
+		}
+		versions = append(versions, *match)
+	}
+	SortVersions(versions)
+	return events.RemoteVersionsMsg(versions)
+}
+
+// SortVersions orders versions newest first.
+func SortVersions(versions []GoVersion) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return CompareVersions(versions[i].Version, versions[j].Version) > 0
+	})
+}
+
+// ScanInstalledVersions finds the versions installed under ~/.govm/versions,
+// mapping each version to its directory.
+func ScanInstalledVersions() tea.Msg {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return ErrMsg(err)
+		return events.NewErrMsg(err)
 	}
 	goVersionsDir := filepath.Join(homeDir, ".govm", "versions")
 	err = os.MkdirAll(goVersionsDir, 0755)
 	if err != nil {
-		return ErrMsg(err)
+		return events.NewErrMsg(err)
 	}
-	activeVersion := ""
-	activeVersionFile := filepath.Join(homeDir, ".govm", "active_version")
-	if versionBytes, err := os.ReadFile(activeVersionFile); err == nil {
-		activeVersion = string(versionBytes)
-	} else {
-		activeVersion = GetCurrentGoVersion()
-	}
-	installedVersions := map[string]string{}
+	installedVersions := events.InstalledVersionsMsg{}
 	entries, _ := os.ReadDir(goVersionsDir)
 	for _, entry := range entries {
 		if entry.IsDir() && strings.HasPrefix(entry.Name(), "go") {
 			versionPath := filepath.Join(goVersionsDir, entry.Name())
 			version := strings.TrimPrefix(entry.Name(), "go")
-			goBin := filepath.Join(versionPath, "bin", goBinary)
-			if _, err := os.Stat(goBin); err == nil {
+			if IsCompleteInstall(versionPath) {
 				installedVersions[version] = versionPath
 			}
 		}
 	}
-	var versions []GoVersion
-	for _, release := range releases {
-		version := strings.TrimPrefix(release.Version, "go")
-		for _, file := range release.Files {
-			if file.OS == currentOS && file.Arch == arch {
-				v := GoVersion{
-					Version:   version,
-					Filename:  file.Filename,
-					URL:       "https://go.dev/dl/" + file.Filename,
-					Installed: false,
-					Active:    false,
-					Stable:    release.Stable,
-				}
-				if path, ok := installedVersions[version]; ok {
-					v.Installed = true
-					v.Path = path
-				}
-				if activeVersion == version {
-					v.Active = true
-				}
-				versions = append(versions, v)
-				break
-			}
+	return installedVersions
+}
+
+// DetectActiveVersion reads the active version GoVM recorded, falling back to
+// whichever go is on PATH.
+func DetectActiveVersion() tea.Msg {
+	homeDir, err := os.UserHomeDir()
+	if err == nil {
+		activeVersionFile := filepath.Join(homeDir, ".govm", "active_version")
+		if versionBytes, err := os.ReadFile(activeVersionFile); err == nil {
+			return events.ActiveVersionMsg(versionBytes)
 		}
 	}
-	sort.Slice(versions, func(i, j int) bool {
-		iParts := strings.Split(versions[i].Version, ".")
-		jParts := strings.Split(versions[j].Version, ".")
-		if len(iParts) > 0 && len(jParts) > 0 {
-			iMajor, _ := strconv.Atoi(iParts[0])
-			jMajor, _ := strconv.Atoi(jParts[0])
-			if iMajor != jMajor {
-				return iMajor > jMajor
-			}
-		}
-		// Compare minor versions
-		if len(iParts) > 1 && len(jParts) > 1 {
-			iMinor, _ := strconv.Atoi(iParts[1])
-			jMinor, _ := strconv.Atoi(jParts[1])
-			if iMinor != jMinor {
-				return iMinor > jMinor
-			}
-		}
-		if len(iParts) > 2 && len(jParts) > 2 {
-			iPatch, _ := strconv.Atoi(iParts[2])
-			jPatch, _ := strconv.Atoi(jParts[2])
-			return iPatch > jPatch
+	return events.ActiveVersionMsg(GetCurrentGoVersion())
+}
+
+// MergeVersions annotates the remote release list with installed and active
+// state.
+func MergeVersions(remote []GoVersion, installed map[string]string, active string) []GoVersion {
+	versions := make([]GoVersion, len(remote))
+	for i, v := range remote {
+		if path, ok := installed[v.Version]; ok {
+			v.Installed = true
+			v.Path = path
 		}
-		return versions[i].Version > versions[j].Version
-	})
-	return VersionsMsg(versions)
+		v.Active = v.Version == active
+		versions[i] = v
+	}
+	return versions
+}
+
+// IsCompleteInstall reports whether versionPath holds a usable toolchain.
+func IsCompleteInstall(versionPath string) bool {
+	_, err := os.Stat(filepath.Join(versionPath, "bin", goBinary))
+	return err == nil
+}
+
+// markInstalled stamps versionDir with the time it was installed, for
+// InstalledAt. The directory otherwise keeps the time it was packed or
+// built with, which the rename that installs it does not change.
+func markInstalled(versionDir string) {
+	now := clock.Now()
+	if err := os.Chtimes(versionDir, now, now); err != nil {
+		term.Debugf("could not record when %s was installed: %v", versionDir, err)
+	}
+}
+
+// InstalledAt returns when the toolchain at versionPath was installed, in
+// UTC. It reports false when that cannot be told.
+func InstalledAt(versionPath string) (time.Time, bool) {
+	info, err := os.Stat(versionPath)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return info.ModTime().UTC().Truncate(time.Second), true
 }
 func GetCurrentGoVersion() string {
-	cmd := exec.Command("go", "version")
-	output, err := cmd.Output()
+	goBin, err := exec.LookPath("go")
 	if err != nil {
 		return ""
 	}
-	parts := strings.Split(string(output), " ")
-	if len(parts) >= 3 {
-		return strings.TrimPrefix(parts[2], "go")
+	// Look through GoVM's own shim so the cache is keyed by the real binary
+	if homeDir, err := os.UserHomeDir(); err == nil && filepath.Dir(goBin) == filepath.Join(homeDir, ".govm", "shim") {
+		if target, err := ShimTarget("go"); err == nil {
+			goBin = target
+		}
 	}
-	return ""
+	version, err := ToolchainVersion(goBin)
+	if err != nil {
+		return ""
+	}
+	return version
 }
+
+// DefaultInstallJobs is how many versions install at once when several are
+// asked for: by install --parallel unless --jobs says otherwise, and by the
+// TUI's install queue.
+const DefaultInstallJobs = 4
+
 func DownloadAndInstall(version GoVersion) tea.Cmd {
+	return DownloadAndInstallWithProgress(version, nil)
+}
+
+// DownloadAndInstallWithProgress is DownloadAndInstall, calling progress
+// periodically while the archive downloads. Each phase is also recorded for
+// LoadInstallProgress.
+func DownloadAndInstallWithProgress(version GoVersion, progress DownloadProgressFunc) tea.Cmd {
 	return func() tea.Msg {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return ErrMsg(err)
-		}
-		goVersionsDir := filepath.Join(homeDir, ".govm", "versions")
-		downloadDir := filepath.Join(homeDir, ".govm", "downloads")
-		for _, dir := range []string{goVersionsDir, downloadDir} {
-			if err := os.MkdirAll(dir, 0755); err != nil {
-				return ErrMsg(err)
+		if version.Version == TipVersion {
+			versionDir, err := BuildTip()
+			if err != nil {
+				return events.NewErrMsg(err)
 			}
+			return events.DownloadCompleteMsg{Version: version.Version, Path: versionDir}
 		}
-		versionDir := filepath.Join(goVersionsDir, fmt.Sprintf("go%s", version.Version))
-		if _, err := os.Stat(versionDir); err == nil {
-			if err := os.RemoveAll(versionDir); err != nil {
-				return ErrMsg(fmt.Errorf("failed to remove existing installation: %v", err))
-			}
+		failed := func(err error) tea.Msg {
+			recordProgress(InstallProgress{Version: version.Version, Phase: PhaseFailed, Error: err.Error()})
+			return events.NewErrMsg(err)
 		}
-		downloadPath := filepath.Join(downloadDir, version.Filename)
-		if _, err := os.Stat(downloadPath); err == nil {
-			if err := os.Remove(downloadPath); err != nil {
-				return ErrMsg(fmt.Errorf("failed to remove existing download: %v", err))
+		recordProgress(InstallProgress{Version: version.Version, Phase: PhaseDownloading})
+		downloadPath, err := DownloadArchiveWithProgress(version, func(p events.DownloadProgressMsg) {
+			recordProgress(downloadProgress(p))
+			if progress != nil {
+				progress(p)
 			}
-		}
-		resp, err := http.Get(version.URL)
+		})
 		if err != nil {
-			return ErrMsg(err)
+			return failed(err)
 		}
-		defer resp.Body.Close()
-		out, err := os.Create(downloadPath)
+		recordProgress(InstallProgress{Version: version.Version, Phase: PhaseExtracting})
+		// The archive stays in the downloads directory so reinstalling,
+		// repairing or rolling back to this version needs no download
+		versionDir, err := ExtractArchive(version, downloadPath)
 		if err != nil {
-			return ErrMsg(err)
+			return failed(err)
 		}
-		defer out.Close()
-		written, err := io.Copy(out, resp.Body)
-		if err != nil {
-			return ErrMsg(err)
-		}
-		if written == 0 {
-			return ErrMsg(fmt.Errorf("downloaded empty file"))
-		}
-		out.Close()
-		var cmd *exec.Cmd
-		if runtime.GOOS == "windows" {
-			if strings.HasSuffix(version.Filename, ".zip") {
-				cmd = exec.Command("powershell", "-Command",
-					fmt.Sprintf("Expand-Archive -Path \"%s\" -DestinationPath \"%s\" -Force",
-						downloadPath, goVersionsDir))
-			} else {
-				return ErrMsg(fmt.Errorf("unsupported archive format for Windows: %s", version.Filename))
-			}
+		recordProgress(InstallProgress{Version: version.Version, Phase: PhaseDone})
+		return events.DownloadCompleteMsg{Version: version.Version, Path: versionDir}
+	}
+}
+
+// DownloadArchive downloads the release archive for version into
+// ~/.govm/downloads and returns its path. An archive already there with the
+// published checksum is returned without downloading it again.
+func DownloadArchive(version GoVersion) (string, error) {
+	return DownloadArchiveWithProgress(version, nil)
+}
+
+// DownloadArchiveWithProgress is DownloadArchive, calling progress
+// periodically with the bytes downloaded so far.
+func DownloadArchiveWithProgress(version GoVersion, progress DownloadProgressFunc) (string, error) {
+	if version.Unsupported != "" {
+		return "", fmt.Errorf("Go %s is not installable via govm: %s", version.Version, version.Unsupported)
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	downloadDir := filepath.Join(homeDir, ".govm", "downloads")
+	if err := os.MkdirAll(downloadDir, 0755); err != nil {
+		return "", err
+	}
+	downloadPath := filepath.Join(downloadDir, version.Filename)
+	if IsOffline() {
+		return cachedArchive(version, downloadPath)
+	}
+	// An archive left by an earlier download is reused when it matches the
+	// checksum the release index lists for its filename; without a checksum
+	// it cannot be trusted and is downloaded again
+	if _, err := os.Stat(downloadPath); err == nil && version.SHA256 != "" {
+		_, err := cachedArchive(version, downloadPath)
+		if err == nil {
+			return downloadPath, nil
+		}
+		term.Debugf("%v; downloading it again", err)
+	}
+	// The archive downloads to a .part file that is only renamed once
+	// complete, so a download that fails partway can resume where it stopped
+	partPath := downloadPath + ".part"
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil {
+		offset = info.Size()
+	}
+	resp, err := fetchArchive(version, offset)
+	if err != nil {
+		if _, statErr := os.Stat(downloadPath); statErr == nil && isUnreachable(err) {
+			goOffline(err, time.Time{})
+			return cachedArchive(version, downloadPath)
+		}
+		return "", err
+	}
+	defer resp.Body.Close()
+	if _, err := os.Stat(downloadPath); err == nil {
+		if err := os.Remove(downloadPath); err != nil {
+			return "", fmt.Errorf("failed to remove existing download: %v", err)
+		}
+	}
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if resp.StatusCode == http.StatusPartialContent {
+		term.Debugf("resuming %s at byte %d", partPath, offset)
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	} else {
+		offset = 0
+		term.Debugf("saving to %s", partPath)
+	}
+	out, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+	// The checksum is computed as the archive streams in; a resumed
+	// download first hashes the part already on disk
+	hash := sha256.New()
+	if offset > 0 {
+		if err := hashFile(hash, partPath); err != nil {
+			return "", err
+		}
+	}
+	var body io.Reader = io.TeeReader(resp.Body, hash)
+	rate, err := downloadRateLimit()
+	if err != nil {
+		return "", err
+	}
+	if rate > 0 {
+		term.Debugf("limiting the download to %s/s", events.FormatBytes(rate))
+		body = newRateLimitedReader(body, rate)
+	}
+	if progress != nil {
+		total := resp.ContentLength
+		if total > 0 {
+			total += offset
+		}
+		body = newProgressReader(body, version.Version, offset, total, progress)
+	}
+	written, err := io.Copy(out, body)
+	if err != nil {
+		return "", err
+	}
+	if offset+written == 0 {
+		return "", fmt.Errorf("downloaded empty file")
+	}
+	if err := out.Close(); err != nil {
+		return "", err
+	}
+	if err := verifyChecksum(version, hex.EncodeToString(hash.Sum(nil)), resp.Request.URL.String()); err != nil {
+		// A corrupt or tampered part must not be resumed either
+		os.Remove(partPath)
+		return "", err
+	}
+	return downloadPath, os.Rename(partPath, downloadPath)
+}
+
+// ExtractArchive installs a downloaded release archive into
+// ~/.govm/versions, replacing any existing install of the same version, and
+// verifies the resulting go binary runs. It returns the version directory.
+func ExtractArchive(version GoVersion, downloadPath string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	goVersionsDir := filepath.Join(homeDir, ".govm", "versions")
+	if err := os.MkdirAll(goVersionsDir, 0755); err != nil {
+		return "", err
+	}
+	versionDir := filepath.Join(goVersionsDir, fmt.Sprintf("go%s", version.Version))
+	if _, err := os.Stat(versionDir); err == nil {
+		if err := CheckNotInside(versionDir); err != nil {
+			return "", fmt.Errorf("cannot replace Go %s: %v", version.Version, err)
+		}
+		if err := os.RemoveAll(versionDir); err != nil {
+			return "", fmt.Errorf("failed to remove existing installation: %v", err)
+		}
+	}
+	// Extract into a private directory first so concurrent installs never
+	// share the archive's top-level "go" directory
+	extractDir, err := os.MkdirTemp(goVersionsDir, ".extract-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create extraction directory: %v", err)
+	}
+	defer os.RemoveAll(extractDir)
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		if strings.HasSuffix(version.Filename, ".zip") {
+			cmd = exec.Command("powershell", "-Command",
+				fmt.Sprintf("Expand-Archive -Path \"%s\" -DestinationPath \"%s\" -Force",
+					downloadPath, extractDir))
 		} else {
-			if strings.HasSuffix(version.Filename, ".tar.gz") {
-				cmd = exec.Command("tar", "-xzf", downloadPath, "-C", goVersionsDir)
-			} else {
-				return ErrMsg(fmt.Errorf("unsupported archive format for Unix: %s", version.Filename))
-			}
+			return "", fmt.Errorf("unsupported archive format for Windows: %s", version.Filename)
 		}
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			return ErrMsg(fmt.Errorf("extraction error: %v\nOutput: %s", err, string(output)))
+	} else {
+		if strings.HasSuffix(version.Filename, ".tar.gz") {
+			cmd = exec.Command("tar", "-xzf", downloadPath, "-C", extractDir)
+		} else {
+			return "", fmt.Errorf("unsupported archive format for Unix: %s", version.Filename)
 		}
+	}
+	term.Debugf("running %s", strings.Join(cmd.Args, " "))
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("extraction error: %v\nOutput: %s", err, string(output))
+	}
+	// Release archives wrap the toolchain in a top-level "go" directory, but
+	// a few historical ones put bin/ and src/ at the root instead
+	rootPath := ""
+	if IsCompleteInstall(extractDir) {
+		rootPath = extractDir
+	}
+	entries, _ := os.ReadDir(extractDir)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+		sourcePath := filepath.Join(extractDir, entry.Name())
+		if rootPath == "" && entry.IsDir() && IsCompleteInstall(sourcePath) {
+			rootPath = sourcePath
+		}
+	}
+	if rootPath == "" {
+		return "", fmt.Errorf("archive %s has no bin/%s; found %s", version.Filename, goBinary, strings.Join(names, ", "))
+	}
+	term.Debugf("moving %s to %s", rootPath, versionDir)
+	if err := os.Rename(rootPath, versionDir); err != nil {
+		return "", fmt.Errorf("failed to rename directory: %v", err)
+	}
+	markInstalled(versionDir)
+	goBin := filepath.Join(versionDir, "bin", goBinary)
+	if runtime.GOOS != "windows" {
+		if _, err := os.Stat(goBin); err == nil {
+			os.Chmod(goBin, 0755)
+		}
+	}
+	if _, err := os.Stat(goBin); os.IsNotExist(err) {
+		return "", fmt.Errorf("installation failed: Go binary not found at %s", goBin)
+	}
+	verifyCmd := exec.Command(goBin, "version")
+	verifyOutput, err := verifyCmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("Go binary verification failed: %v\nOutput: %s", err, string(verifyOutput))
+	}
+	return versionDir, nil
+}
 
-		if runtime.GOOS != "windows" {
-			goBin := filepath.Join(versionDir, "bin", goBinary)
-			if _, err := os.Stat(goBin); err == nil {
-				os.Chmod(goBin, 0755)
-			}
+// PublishedChecksum returns the SHA-256 the release index lists for the
+// archive filename, or an empty string when the index is unavailable or
+// does not list it.
+func PublishedChecksum(filename string) string {
+	versions, ok := FetchRemoteVersions().(events.RemoteVersionsMsg)
+	if !ok {
+		return ""
+	}
+	for _, v := range versions {
+		if v.Filename == filename {
+			return v.SHA256
 		}
-		goBin := filepath.Join(versionDir, "bin", goBinary)
-		if _, err := os.Stat(goBin); os.IsNotExist(err) {
-			entries, _ := os.ReadDir(goVersionsDir)
-			for _, entry := range entries {
-				if entry.IsDir() && strings.HasPrefix(entry.Name(), "go") {
-					testPath := filepath.Join(goVersionsDir, entry.Name(), "bin", goBinary)
-					if _, err := os.Stat(testPath); err == nil {
-						sourcePath := filepath.Join(goVersionsDir, entry.Name())
-						if sourcePath != versionDir {
-							if err := os.Rename(sourcePath, versionDir); err != nil {
-								return ErrMsg(fmt.Errorf("failed to rename directory: %v", err))
-							}
-						}
-						break
-					}
-				}
-			}
+	}
+	return ""
+}
+
+// cachedArchive returns downloadPath when an archive for version is already
+// there, checked against the published checksum. Offline it is the only
+// archive that can be installed.
+func cachedArchive(version GoVersion, downloadPath string) (string, error) {
+	if _, err := os.Stat(downloadPath); err != nil {
+		return "", fmt.Errorf("%w and Go %s is not in %s; download it with 'govm download %s' while online", ErrOffline, version.Version, filepath.Dir(downloadPath), version.Version)
+	}
+	if version.SHA256 != "" {
+		sum, err := FileSHA256(downloadPath)
+		if err != nil {
+			return "", err
 		}
-		if _, err := os.Stat(goBin); os.IsNotExist(err) {
-			return ErrMsg(fmt.Errorf("installation failed: Go binary not found at %s", goBin))
+		if sum != version.SHA256 {
+			return "", fmt.Errorf("checksum mismatch for the cached %s: got %s, the release index lists %s", downloadPath, sum, version.SHA256)
+		}
+	}
+	term.Debugf("using the cached %s", downloadPath)
+	return downloadPath, nil
+}
+
+// fetchArchive requests the release archive from each download mirror in
+// turn, then from the historical download locations, until one serves it.
+// With download.base_url set it asks that repository alone.
+// With a non-zero offset it asks for the rest of the archive from there on;
+// the response is 206 Partial Content if the server honored that and 200
+// with the whole archive if it did not.
+func fetchArchive(version GoVersion, offset int64) (*http.Response, error) {
+	// An archive URL given on the command line is tried before the mirrors
+	var urls []string
+	if !strings.HasPrefix(version.URL, DefaultDownloadMirror) {
+		urls = append(urls, version.URL)
+	}
+	bases := append(DownloadMirrors(), archiveMirrors...)
+	if base := DownloadBaseURL(); base != "" {
+		// An artifact repository is often the only place downloads are
+		// allowed from, so there is nothing to fall back to
+		bases = []string{base}
+	}
+	for _, base := range bases {
+		if url := base + version.Filename; !slices.Contains(urls, url) {
+			urls = append(urls, url)
+		}
+	}
+	var lastErr error
+	notFound := 0
+	for _, url := range urls {
+		resp, err := getArchive(url, offset)
+		if err == nil && resp.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+			// The partial file is no prefix of this archive; start over
+			resp.Body.Close()
+			resp, err = getArchive(url, 0)
 		}
-		verifyCmd := exec.Command(goBin, "version")
-		verifyOutput, err := verifyCmd.CombinedOutput()
 		if err != nil {
-			return ErrMsg(fmt.Errorf("Go binary verification failed: %v\nOutput: %s", err, string(verifyOutput)))
+			lastErr = err
+			continue
 		}
-		// Remove the existing downloads since they should be installed
-		if err := os.Remove(downloadPath); err != nil {
-			// Just log the error but don't fail the installation
-			fmt.Printf("Warning: failed to clean up download file: %v\n", err)
+		if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusPartialContent {
+			return resp, nil
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+			notFound++
+			continue
 		}
-		return DownloadCompleteMsg{Version: version.Version, Path: versionDir}
+		lastErr = statusError(url, resp)
+	}
+	if notFound == len(urls) {
+		return nil, fmt.Errorf("Go %s is no longer distributed: %s was not found on go.dev or its mirrors", version.Version, version.Filename)
+	}
+	return nil, lastErr
+}
+
+// getArchive requests url, asking for the bytes from offset on when it is
+// non-zero. A 206 response whose range does not start at offset is turned
+// into an error rather than appended to the partial file.
+func getArchive(url string, offset int64) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	authorize(req)
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		term.Debugf("GET %s (from byte %d)", redactURL(url), offset)
+	} else {
+		term.Debugf("GET %s", redactURL(url))
+	}
+	client, err := newHTTPClient(archiveRequest)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, classifyNetworkError(url, err)
 	}
+	if resp.StatusCode == http.StatusPartialContent && !strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)) {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected range %q from %s", resp.Header.Get("Content-Range"), redactURL(url))
+	}
+	return resp, nil
 }
 func SwitchVersion(version GoVersion) tea.Cmd {
+	return SwitchVersionWithProgress(version, nil)
+}
+
+// SwitchVersionWithProgress is SwitchVersion, calling progress as each shim
+// is written so slow filesystems do not look stalled.
+func SwitchVersionWithProgress(version GoVersion, progress ShimProgressFunc) tea.Cmd {
 	return func() tea.Msg {
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
-			return ErrMsg(err)
+			return events.NewErrMsg(err)
 		}
 		if err := SetupShimDirectory(); err != nil {
-			return ErrMsg(err)
+			return events.NewErrMsg(err)
 		}
 		shimDir := filepath.Join(homeDir, ".govm", "shim")
 		versionBinDir := filepath.Join(version.Path, "bin")
 		if _, err := os.Stat(versionBinDir); os.IsNotExist(err) {
-			return ErrMsg(fmt.Errorf("go version directory not found: %s", versionBinDir))
+			return events.ErrMsgf("go version directory not found: %s", versionBinDir)
 		}
-		entries, err := os.ReadDir(versionBinDir)
-		if err != nil {
-			return ErrMsg(fmt.Errorf("failed to read bin directory: %v", err))
-		}
-		for _, entry := range entries {
-			if !entry.IsDir() {
-				binName := strings.Trim(entry.Name(), ".exe")
-				targetBin := filepath.Join(versionBinDir, binName)
-				shimPath := filepath.Join(shimDir, binName)
-				os.Remove(shimPath)
-				if runtime.GOOS == "windows" {
-					shimContent := fmt.Sprintf(`@echo off
-"%s" %%*
-`, targetBin)
-					if err := os.WriteFile(shimPath+".bat", []byte(shimContent), 0755); err != nil {
-						return ErrMsg(fmt.Errorf("failed to create shim for %s: %v", binName, err))
-					}
-				} else {
-					shimContent := fmt.Sprintf(`#!/usr/bin/env bash
-"%s" "$@"
-`, targetBin)
-					if err := os.WriteFile(shimPath, []byte(shimContent), 0755); err != nil {
-						return ErrMsg(fmt.Errorf("failed to create shim for %s: %v", binName, err))
-					}
-					if err := os.Chmod(shimPath, 0755); err != nil {
-						return ErrMsg(fmt.Errorf("failed to make shim executable: %v", err))
-					}
-				}
-			}
+		term.Debugf("writing shims in %s for %s", shimDir, versionBinDir)
+		if err := writeShims(shimDir, versionBinDir, progress); err != nil {
+			return events.NewErrMsg(err)
 		}
 		versionFile := filepath.Join(homeDir, ".govm", "active_version")
+		if previous, err := os.ReadFile(versionFile); err == nil && string(previous) != version.Version {
+			previousFile := filepath.Join(homeDir, ".govm", "previous_version")
+			if err := os.WriteFile(previousFile, previous, 0644); err != nil {
+				return events.ErrMsgf("failed to record previous version: %v", err)
+			}
+		}
+		term.Debugf("recording Go %s in %s", version.Version, versionFile)
 		if err := os.WriteFile(versionFile, []byte(version.Version), 0644); err != nil {
-			return ErrMsg(fmt.Errorf("failed to update active version file: %v", err))
+			return events.ErrMsgf("failed to update active version file: %v", err)
+		}
+		if err := RecordSwitch(version.Version); err != nil {
+			return events.ErrMsgf("failed to record switch history: %v", err)
 		}
 		shimInPath := IsShimInPath()
-		return SwitchCompletedMsg{
+		return events.SwitchCompletedMsg{
 			Version:    version.Version,
 			ShimInPath: shimInPath,
 		}
@@ -357,17 +690,42 @@ func SwitchVersion(version GoVersion) tea.Cmd {
 func DeleteVersion(version GoVersion) tea.Cmd {
 	return func() tea.Msg {
 		if !version.Installed {
-			return ErrMsg(fmt.Errorf("version %s is not installed", version.Version))
+			return events.ErrMsgf("version %s is not installed", version.Version)
 		}
 
 		if version.Active {
-			return ErrMsg(fmt.Errorf("cannot delete active version - switch to another version first"))
+			return events.ErrMsgf("cannot delete active version - switch to another version first")
+		}
+
+		if err := CheckNotInside(version.Path); err != nil {
+			return events.ErrMsgf("cannot delete Go %s: %v", version.Version, err)
 		}
 
 		if err := os.RemoveAll(version.Path); err != nil {
-			return ErrMsg(fmt.Errorf("failed to delete version %s: %v", version.Version, err))
+			return events.ErrMsgf("failed to delete version %s: %v", version.Version, err)
 		}
 
-		return DeleteCompleteMsg{Version: version.Version}
+		return events.DeleteCompleteMsg{Version: version.Version}
+	}
+}
+
+// CheckNotInside returns an error if the current working directory is dir
+// or below it. Removing such a directory fails on Windows and leaves the
+// shell in a deleted directory on Unix.
+func CheckNotInside(dir string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	if resolved, err := filepath.EvalSymlinks(cwd); err == nil {
+		cwd = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	rel, err := filepath.Rel(dir, cwd)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil
 	}
+	return fmt.Errorf("the current directory %s is inside %s; change to another directory first", cwd, dir)
 }
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// versionCacheEntry records the output of 'go version' for a binary as of
+// its modification time.
+type versionCacheEntry struct {
+	ModTime time.Time `json:"mod_time"`
+	Version string    `json:"version"`
+}
+
+var versionCacheMu sync.Mutex
+
+func versionCachePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".govm", "version_cache.json"), nil
+}
+
+// ToolchainVersion returns the version reported by 'go version' for goBin.
+// Results are cached under ~/.govm keyed by the binary's path and mtime, so
+// the process is only spawned again after the toolchain changes.
+func ToolchainVersion(goBin string) (string, error) {
+	info, err := os.Stat(goBin)
+	if err != nil {
+		return "", err
+	}
+	cachePath, cacheErr := versionCachePath()
+	versionCacheMu.Lock()
+	defer versionCacheMu.Unlock()
+	cache := map[string]versionCacheEntry{}
+	if cacheErr == nil {
+		if content, err := os.ReadFile(cachePath); err == nil {
+			json.Unmarshal(content, &cache)
+		}
+		if entry, ok := cache[goBin]; ok && entry.ModTime.Equal(info.ModTime()) {
+			return entry.Version, nil
+		}
+	}
+	output, err := exec.Command(goBin, "version").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run %s version: %v", goBin, err)
+	}
+	parts := strings.Split(string(output), " ")
+	if len(parts) < 3 {
+		return "", fmt.Errorf("unexpected output from %s version: %s", goBin, output)
+	}
+	version := strings.TrimPrefix(parts[2], "go")
+	if cacheErr == nil {
+		cache[goBin] = versionCacheEntry{ModTime: info.ModTime(), Version: version}
+		if content, err := json.Marshal(cache); err == nil {
+			os.MkdirAll(filepath.Dir(cachePath), 0755)
+			os.WriteFile(cachePath, content, 0644)
+		}
+	}
+	return version, nil
+}
@@ -1,15 +1,16 @@
 package main
 
 import (
-	"fmt"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/melkeydev/govm/internal/cli"
+	"github.com/melkeydev/govm/internal/config"
 	"github.com/melkeydev/govm/internal/model"
 	"github.com/melkeydev/govm/internal/setup"
+	"github.com/melkeydev/govm/internal/term"
 	"github.com/melkeydev/govm/internal/utils"
 	"os"
 	"path/filepath"
@@ -17,97 +18,82 @@ import (
 )
 
 func main() {
+	os.Args = parseOutputFlags(os.Args)
+	// Prompt segments run on every prompt, so skip all setup work
+	if len(os.Args) > 1 && os.Args[1] == "prompt" {
+		cli.Prompt()
+		return
+	}
 	// Check if user is requesting version information
 	if len(os.Args) > 1 && os.Args[1] == "version" {
-		fmt.Printf("govm %s\n", utils.GetVersion())
+		term.Printf("govm %s\n", utils.GetVersion())
 		os.Exit(0)
 	}
-	if err := utils.SetupShimDirectory(); err != nil {
-		fmt.Printf("Warning: Failed to set up shim directory: %v\n", err)
-	}
 	if len(os.Args) > 1 {
-		handleCommandLine()
-		return
+		utils.OnNetworkUnreachable = cli.ReportOffline
+		utils.OnStaleIndex = cli.ReportStaleIndex
+		utils.OnWeakChecksum = cli.ReportWeakChecksum
+		runCommand(os.Args[1:])
+		os.Exit(cli.ExitCode())
 	}
 	// handleCommandLine and TUI should never throw at the same time
 	launchTUI()
 }
-func handleCommandLine() {
-	if len(os.Args) < 2 {
-		printUsage()
-		return
-	}
-	command := os.Args[1]
-	switch command {
-	case "install":
-		if len(os.Args) < 3 {
-			fmt.Println("Error: 'install' requires a version argument")
-			fmt.Println("Usage: govm install <version>")
-			fmt.Println("Example: govm install 1.21")
-			return
+
+// parseOutputFlags removes the global --no-emoji, --no-color, --quiet,
+// --verbose, --limit-rate, --offline, --ipv4 and --ipv6 flags from args,
+// wherever they appear before a "--", and applies them with the environment
+// to the output policy and the network. Arguments after "--" belong to the
+// command run by exec or run and are left alone.
+func parseOutputFlags(args []string) []string {
+	noEmoji, noColor := false, false
+	kept := args[:1]
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			kept = append(kept, args[i:]...)
+			break
 		}
-		version := os.Args[2]
-		version = strings.TrimPrefix(version, "go")
-		cli.InstallVersion(version)
-	case "use":
-		if len(os.Args) < 3 {
-			fmt.Println("Error: 'use' requires a version argument")
-			fmt.Println("Usage: govm use <version>")
-			fmt.Println("Example: govm use 1.21")
-			return
+		if rate, ok := strings.CutPrefix(arg, "--limit-rate="); ok || arg == "--limit-rate" {
+			if !ok {
+				if i+1 == len(args) {
+					usageError("--limit-rate needs a rate, such as --limit-rate 5M")
+				}
+				i++
+				rate = args[i]
+			}
+			limit, err := utils.ParseRate(rate)
+			if err != nil {
+				usageError(err.Error())
+			}
+			utils.LimitRate = limit
+			continue
 		}
-		version := os.Args[2]
-		version = strings.TrimPrefix(version, "go")
-		cli.UseVersion(version)
-	case "delete":
-		if len(os.Args) < 3 {
-			fmt.Println("Error: 'delete' requires a version argument")
-			fmt.Println("Usage: govm delete <version>")
-			fmt.Println("Example: govm delete 1.21")
-			return
+		switch arg {
+		case "--no-emoji":
+			noEmoji = true
+		case "--no-color":
+			noColor = true
+		case "--quiet", "-q":
+			term.Quiet = true
+		case "--verbose":
+			term.Verbose = true
+		case "--offline":
+			utils.SetOffline(true)
+		case "--ipv4":
+			utils.IPVersion = "4"
+		case "--ipv6":
+			utils.IPVersion = "6"
+		default:
+			kept = append(kept, arg)
 		}
-		version := os.Args[2]
-		version = strings.TrimPrefix(version, "go")
-		cli.DeleteVersion(version)
-	case "list":
-		cli.ListVersions()
-	case "help":
-		printUsage()
-	default:
-		fmt.Printf("Unknown command: %s\n", command)
-		printUsage()
 	}
+	term.Init(noEmoji, noColor)
+	return kept
 }
-func printUsage() {
-	fmt.Println("GoVM - Go Version Manager")
-	fmt.Println("\nUsage:")
-	fmt.Println("  govm                   Launch the interactive TUI")
-	fmt.Println("  govm install <version> Install a specific Go version")
-	fmt.Println("  govm use <version>     Switch to a specific Go version")
-	fmt.Println("  govm delete <version>  Delete a specific Go version")
-	fmt.Println("  govm list              List installed Go versions")
-	fmt.Println("  govm help              Show this help message")
-	fmt.Println("\nExamples:")
-	fmt.Println("  govm install 1.21      Install Go 1.21.x (latest)")
-	fmt.Println("  govm use 1.20          Switch to Go 1.20.x (latest)")
-}
-func launchTUI() {
-	if !setup.IsShimInPath() {
-		setupModel := setup.New()
-		p := tea.NewProgram(setupModel, tea.WithAltScreen())
-		if _, err := p.Run(); err != nil {
-			fmt.Printf("Error in setup: %v\n", err)
-			os.Exit(1)
-		}
-	}
-	s := spinner.New()
-	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#3c71a8"))
-	columns := []table.Column{
-		{Title: "Version", Width: 10},
-		{Title: "Path", Width: 40},
-		{Title: "Status", Width: 10},
-	}
+
+// newTable returns a focused table in the GoVM colors.
+func newTable(columns []table.Column) table.Model {
 	t := table.New(
 		table.WithColumns(columns),
 		table.WithFocused(true),
@@ -127,9 +113,47 @@ func launchTUI() {
 		Cell: lipgloss.NewStyle().
 			Padding(0, 1),
 	})
+	return t
+}
+func launchTUI() {
+	// Verbose details are written straight to the terminal and would corrupt
+	// the TUI
+	term.Verbose = false
+	if !setup.IsShimInPath() {
+		setupModel := setup.New()
+		p := tea.NewProgram(setupModel, tea.WithAltScreen())
+		if _, err := p.Run(); err != nil {
+			term.Printf("Error in setup: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	if !term.Emoji {
+		s.Spinner = spinner.Line
+	}
+	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#3c71a8"))
+	columns := []table.Column{
+		{Title: "Version", Width: 10},
+		{Title: "Path", Width: 40},
+		{Title: "Status", Width: 10},
+		{Title: "Installed", Width: 32},
+		{Title: "Last used", Width: 32},
+	}
+	t := newTable(columns)
+	h := newTable([]table.Column{
+		{Title: "Switched", Width: 32},
+		{Title: "Version", Width: 10},
+	})
+	projects := newTable([]table.Column{
+		{Title: "Project", Width: 40},
+		{Title: "Pin file", Width: 14},
+		{Title: "Pinned", Width: 10},
+		{Title: "Status", Width: 20},
+	})
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		fmt.Println("Error getting home directory:", err)
+		term.Println("Error getting home directory:", err)
 		os.Exit(1)
 	}
 	goVersionsDir := filepath.Join(homeDir, ".govm", "versions")
@@ -141,6 +165,14 @@ func launchTUI() {
 	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.
 		Foreground(lipgloss.Color("#DDDDDD")).
 		Background(lipgloss.Color("#3c71a8"))
+	cfg, err := config.Load()
+	if err != nil {
+		term.Printf("Warning: %v\n", err)
+	}
+	if cfg.TUI.Compact {
+		delegate.ShowDescription = false
+		delegate.SetSpacing(0)
+	}
 	l := list.New([]list.Item{}, delegate, 0, 0)
 	l.Title = "Go Versions"
 	l.SetShowHelp(false)
@@ -152,11 +184,17 @@ func launchTUI() {
 		HomeDir:        homeDir,
 		GoVersionsDir:  goVersionsDir,
 		InstalledTable: t,
+		HistoryTable:   h,
+		ProjectsTable:  projects,
+		Delegate:       delegate,
+		Compact:        cfg.TUI.Compact,
+		ListHeight:     cfg.TUI.ListHeight,
+		TableHeight:    cfg.TUI.TableHeight,
+		ISOTime:        cfg.ISOTime(),
 	}
 	p := tea.NewProgram(initialModel, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
-		fmt.Printf("Error running program: %v\n", err)
+		term.Printf("Error running program: %v\n", err)
 		os.Exit(1)
 	}
 }
-